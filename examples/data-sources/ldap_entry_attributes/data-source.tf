@@ -0,0 +1,8 @@
+data "ldap_entry_attributes" "jdoe" {
+  dn = "uid=jdoe,ou=users,dc=example,dc=com"
+}
+
+# Only user attributes can be managed with ldap_entry
+output "manageable_attributes" {
+  value = data.ldap_entry_attributes.jdoe.user_attributes
+}
@@ -0,0 +1,16 @@
+# Generate import blocks for every user below ou=users
+data "ldap_import" "users" {
+  basedn     = "ou=users,dc=example,dc=com"
+  filter     = "(objectClass=inetOrgPerson)"
+  attributes = ["objectClass", "cn", "sn", "uid", "mail"]
+}
+
+# terraform output -raw user_imports > imports.tf
+# terraform plan -generate-config-out=generated.tf
+output "user_imports" {
+  value = data.ldap_import.users.import_blocks
+}
+
+output "user_dns" {
+  value = [for entry in data.ldap_import.users.entries : entry.dn]
+}
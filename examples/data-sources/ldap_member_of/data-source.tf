@@ -0,0 +1,21 @@
+# Read the memberOf attribute maintained by the server
+data "ldap_member_of" "direct" {
+  user_dn = "uid=jdoe,ou=users,dc=example,dc=com"
+}
+
+# Search groups for the user instead of relying on memberOf
+data "ldap_member_of" "search" {
+  user_dn       = "uid=jdoe,ou=users,dc=example,dc=com"
+  group_base_dn = "ou=groups,dc=example,dc=com"
+}
+
+# Active Directory: include membership through nested groups
+data "ldap_member_of" "nested" {
+  user_dn       = "CN=John Doe,OU=Users,DC=example,DC=com"
+  group_base_dn = "OU=Groups,DC=example,DC=com"
+  transitive    = true
+}
+
+output "is_admin" {
+  value = contains(data.ldap_member_of.nested.groups, "CN=Admins,OU=Groups,DC=example,DC=com")
+}
@@ -0,0 +1,23 @@
+data "ldap_root_dse" "server" {}
+
+locals {
+  # Active Directory announces the domain; OpenLDAP only lists its naming contexts
+  base_dn = coalesce(data.ldap_root_dse.server.default_naming_context, data.ldap_root_dse.server.naming_contexts[0])
+}
+
+# The same configuration works in every environment, whatever its base DN
+resource "ldap_entry" "service_accounts" {
+  dn = "ou=service-accounts,${local.base_dn}"
+  attributes = {
+    objectClass = ["organizationalUnit"]
+    ou          = ["service-accounts"]
+  }
+}
+
+# Warn before Kerberos starts failing: Active Directory allows 5 minutes of clock skew
+check "clock_skew" {
+  assert {
+    condition     = data.ldap_root_dse.server.clock_skew_seconds == null || abs(coalesce(data.ldap_root_dse.server.clock_skew_seconds, 0)) < 300
+    error_message = "The directory clock is ${coalesce(data.ldap_root_dse.server.clock_skew_seconds, 0)} seconds off."
+  }
+}
@@ -24,6 +24,23 @@ data "ldap_search" "direct_children" {
   filter = "(objectClass=organizationalUnit)"
 }
 
+# Single-valued attributes (per the server schema) as plain strings
+data "ldap_search" "posix_users" {
+  basedn                = "ou=users,dc=example,dc=com"
+  filter                = "(objectClass=posixAccount)"
+  requested_attributes  = ["uid", "uidNumber", "gidNumber"]
+  flatten_single_valued = true
+}
+
+# Effective group SIDs of an Active Directory user, including nested groups
+data "ldap_search" "token_groups" {
+  basedn               = "CN=Jane Doe,CN=Users,DC=example,DC=com"
+  scope                = "base"
+  filter               = "(objectClass=user)"
+  requested_attributes = ["tokenGroups"]
+  sid_attributes       = ["tokenGroups"]
+}
+
 # Output examples using the new structure
 output "user_count" {
   description = "Total number of users found"
@@ -34,3 +51,8 @@ output "user_dns" {
   description = "List of all user DNs"
   value       = [for result in data.ldap_search.all_users.results : result.dn]
 }
+
+output "uid_numbers" {
+  description = "uidNumber of each POSIX user"
+  value       = [for result in data.ldap_search.posix_users.results : result.flattened_attributes["uidNumber"]]
+}
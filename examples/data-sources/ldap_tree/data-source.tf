@@ -0,0 +1,14 @@
+# Export an OU and everything below it as nested data
+data "ldap_tree" "engineering" {
+  basedn               = "ou=engineering,dc=example,dc=com"
+  requested_attributes = ["objectClass", "cn", "mail"]
+  max_depth            = 2
+}
+
+output "engineering_teams" {
+  value = keys(data.ldap_tree.engineering.tree.children)
+}
+
+output "platform_team_mail" {
+  value = [for member in data.ldap_tree.engineering.tree.children["ou=platform"].children : member.attributes.mail]
+}
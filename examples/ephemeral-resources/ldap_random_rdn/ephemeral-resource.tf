@@ -0,0 +1,13 @@
+# Give a temporary entry added by a provisioner a name no other run uses
+ephemeral "ldap_random_rdn" "fixture" {
+  prefix = "fixture-"
+}
+
+resource "terraform_data" "fixture" {
+  provisioner "local-exec" {
+    command = "printf 'dn: cn=%s,ou=test,dc=example,dc=com\\nobjectClass: person\\ncn: %s\\nsn: Fixture\\n' \"$CN\" \"$CN\" | ldapadd -x -H ldap://localhost:389 -D cn=Manager,dc=example,dc=com -w \"$LDAP_BIND_PASSWORD\""
+    environment = {
+      CN = ephemeral.ldap_random_rdn.fixture.value
+    }
+  }
+}
@@ -0,0 +1,20 @@
+variable "admins" {
+  type = list(string)
+}
+
+resource "ldap_entry" "admins" {
+  dn = "cn=admins,ou=groups,dc=example,dc=com"
+  attributes = {
+    objectClass = ["groupOfNames"]
+    cn          = ["admins"]
+    member      = var.admins
+  }
+
+  lifecycle {
+    # Fails if the server holds different members than configured, in any order
+    postcondition {
+      condition     = provider::ldap::attr_values_equal(self.attributes.member, var.admins)
+      error_message = "The admins group does not hold the configured members."
+    }
+  }
+}
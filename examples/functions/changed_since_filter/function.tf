@@ -0,0 +1,5 @@
+# Find users changed in the last 24 hours
+data "ldap_search" "recently_changed" {
+  basedn = "ou=users,dc=example,dc=com"
+  filter = provider::ldap::changed_since_filter("(objectClass=user)", timeadd(plantimestamp(), "-24h"))
+}
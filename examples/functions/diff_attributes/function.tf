@@ -0,0 +1,13 @@
+# Preview what changing a group's members would send to the server
+output "member_changes" {
+  value = provider::ldap::diff_attributes(
+    {
+      cn     = ["admins"]
+      member = ["uid=alice,ou=users,dc=example,dc=com", "uid=bob,ou=users,dc=example,dc=com"]
+    },
+    {
+      cn     = ["admins"]
+      member = ["uid=alice,ou=users,dc=example,dc=com", "uid=carol,ou=users,dc=example,dc=com"]
+    },
+  ).changed
+}
@@ -0,0 +1,10 @@
+variable "password" {
+  type      = string
+  sensitive = true
+}
+
+# Precompute the unicodePwd value for an LDIF file applied by other tooling
+output "unicode_pwd_ldif" {
+  value     = "unicodePwd:: ${provider::ldap::encode_unicode_pwd(var.password)}"
+  sensitive = true
+}
@@ -0,0 +1,9 @@
+variable "name" {
+  type = string
+}
+
+# Find users whose cn contains var.name literally, even if it holds *, ( or \
+data "ldap_search" "name_contains" {
+  basedn = "ou=users,dc=example,dc=com"
+  filter = "(&(objectClass=person)(cn=*${provider::ldap::escape_filter_assertion(var.name)}*))"
+}
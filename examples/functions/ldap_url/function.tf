@@ -0,0 +1,14 @@
+# Point a referral at the server holding the sales subtree
+resource "ldap_entry" "sales_referral" {
+  dn = "ou=sales,dc=example,dc=com"
+  attributes = {
+    objectClass = ["referral", "extensibleObject"]
+    ou          = ["sales"]
+    ref         = [provider::ldap::ldap_url("ldap.sales.example.com", "ou=sales,dc=example,dc=com", [], "", "")]
+  }
+}
+
+# Link to a search for a user's mail address
+output "mail_lookup_url" {
+  value = provider::ldap::ldap_url("ldaps://ldap.example.com", "ou=users,dc=example,dc=com", ["mail"], "sub", "(uid=jdoe)")
+}
@@ -0,0 +1,18 @@
+data "ldap_search" "developers" {
+  basedn               = "cn=developers,ou=groups,dc=example,dc=com"
+  scope                = "base"
+  filter               = "(objectClass=groupOfNames)"
+  requested_attributes = ["member"]
+}
+
+locals {
+  desired_members = [
+    "uid=alice,ou=users,dc=example,dc=com",
+    "uid=bob,ou=users,dc=example,dc=com",
+  ]
+}
+
+# Review the membership changes before applying them
+output "developers_membership_changes" {
+  value = provider::ldap::member_delta(data.ldap_search.developers.results[0].attributes["member"], local.desired_members)
+}
@@ -0,0 +1,6 @@
+data "ldap_root_dse" "forest" {}
+
+# Find the partition, e.g. the child domain, a user lives in
+output "user_partition" {
+  value = provider::ldap::naming_context("CN=jdoe,OU=Staff,DC=emea,DC=example,DC=com", data.ldap_root_dse.forest.naming_contexts)
+}
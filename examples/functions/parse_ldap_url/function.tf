@@ -0,0 +1,21 @@
+variable "referral" {
+  type    = string
+  default = "ldap://ldap.sales.example.com/ou=sales,dc=example,dc=com??sub"
+}
+
+locals {
+  referral = provider::ldap::parse_ldap_url(var.referral)
+}
+
+# Search the server and subtree a referral points at
+provider "ldap" {
+  alias = "sales"
+  url   = "${local.referral.scheme}://${local.referral.host}"
+}
+
+data "ldap_search" "sales_users" {
+  provider = ldap.sales
+  basedn   = local.referral.dn
+  scope    = local.referral.scope
+  filter   = "(objectClass=inetOrgPerson)"
+}
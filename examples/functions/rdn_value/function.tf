@@ -0,0 +1,4 @@
+# Get the owning user's uid from a device DN nested below the user
+output "device_owner" {
+  value = provider::ldap::rdn_value("cn=laptop,uid=jdoe,ou=users,dc=example,dc=com", "uid")
+}
@@ -0,0 +1,4 @@
+# Preview the DN of a user after changing its uid
+output "renamed_dn" {
+  value = provider::ldap::rename_dn("uid=jdoe,ou=users,dc=example,dc=com", "uid=john.doe")
+}
@@ -0,0 +1,22 @@
+locals {
+  user_dn = "uid=jdoe,ou=users,dc=example,dc=com"
+  user_attributes = {
+    objectClass = ["inetOrgPerson"]
+    uid         = ["jdoe"]
+    cn          = ["John Doe"]
+    sn          = ["Doe"]
+    mail        = ["jdoe@example.com", "john.doe@example.com"]
+    jpegPhoto   = [filebase64("${path.module}/jdoe.jpg")]
+  }
+}
+
+resource "ldap_entry" "user" {
+  dn                = local.user_dn
+  attributes        = local.user_attributes
+  binary_attributes = ["jpegPhoto"]
+}
+
+# Preview the entry the resource adds, e.g. in a plan under review
+output "user_ldif" {
+  value = provider::ldap::render_ldif(local.user_dn, local.user_attributes, "jpegPhoto")
+}
@@ -0,0 +1,9 @@
+variable "object_classes" {
+  type    = list(string)
+  default = ["top", "person", "organizationalPerson", "inetOrgPerson"]
+}
+
+# "inetOrgPerson"
+output "structural_class" {
+  value = provider::ldap::structural_class(var.object_classes)
+}
@@ -0,0 +1,15 @@
+variable "member_dns" {
+  type    = list(string)
+  default = ["uid=jdoe,ou=users,dc=example,dc=com"]
+}
+
+# posixGroup membership uses bare usernames (memberUid) rather than DNs
+resource "ldap_entry" "posix_group" {
+  dn = "cn=developers,ou=groups,dc=example,dc=com"
+  attributes = {
+    objectClass = ["posixGroup"]
+    cn          = ["developers"]
+    gidNumber   = ["10001"]
+    memberUid   = [for dn in var.member_dns : provider::ldap::uid_from_dn(dn)]
+  }
+}
@@ -0,0 +1,16 @@
+variable "user_attributes" {
+  type = map(list(string))
+}
+
+# Catch typos in attribute names before apply
+resource "ldap_entry" "user" {
+  dn         = "uid=jdoe,ou=users,dc=example,dc=com"
+  attributes = var.user_attributes
+
+  lifecycle {
+    precondition {
+      condition     = alltrue([for name in keys(var.user_attributes) : provider::ldap::valid_attribute_name(name)])
+      error_message = "All attribute names must be valid LDAP attribute descriptions."
+    }
+  }
+}
@@ -5,3 +5,13 @@ terraform import ldap_entry.user "CN=user,OU=Users,DC=example,DC=com"
 # JSON with specific attributes:
 terraform import ldap_entry.user '{"dn": "CN=user,OU=Users,DC=example,DC=com", "attributes": ["objectClass", "cn", "sAMAccountName",
   "userPrincipalName"]}'
+
+# JSON with an empty attribute list (imports no attributes, not even objectClass):
+terraform import ldap_entry.user '{"dn": "CN=user,OU=Users,DC=example,DC=com", "attributes": []}'
+
+# entryUUID (or objectGUID on Active Directory) instead of the DN, to import the entry wherever it
+# currently is. The UUID is resolved with a subtree search below the provider's base_dn, or below
+# every naming context of the server, which can be slow on large directories without an index on
+# the attribute. The bound identity needs search access to entryUUID or objectGUID there.
+terraform import ldap_entry.user "597ae2f6-16a6-1027-98f4-abcdefabcdef"
+terraform import ldap_entry.user '{"dn": "597ae2f6-16a6-1027-98f4-abcdefabcdef", "attributes": ["objectClass", "cn"]}'
@@ -82,4 +82,36 @@ resource "ldap_entry" "ad_user" {
 
   # Increment this version to rotate the password
   attributes_wo_version = 1
+
+  # Change this value to unlock the account after it was locked out
+  unlock = "1"
+}
+
+# Create a POSIX group. memberUid values are usernames, not DNs;
+# uid_from_dn extracts the username from a user's DN.
+resource "ldap_entry" "posix_group" {
+  dn = "cn=engineers,ou=groups,dc=example,dc=com"
+  attributes = {
+    objectClass = ["posixGroup"]
+    cn          = ["engineers"]
+    gidNumber   = ["10001"]
+    memberUid = [
+      provider::ldap::uid_from_dn("uid=jdoe,ou=users,dc=example,dc=com"),
+    ]
+  }
+}
+
+# Example: Active Directory organizational unit with a managed DACL.
+# sd_flags = 4 reads and writes only the DACL part of ntSecurityDescriptor,
+# which is binary and therefore given base64-encoded.
+resource "ldap_entry" "delegated_ou" {
+  dn = "OU=Delegated,DC=example,DC=com"
+  attributes = {
+    objectClass          = ["top", "organizationalUnit"]
+    ou                   = ["Delegated"]
+    ntSecurityDescriptor = [filebase64("${path.module}/delegated-ou.sd")]
+  }
+
+  binary_attributes = ["ntSecurityDescriptor"]
+  sd_flags          = 4
 }
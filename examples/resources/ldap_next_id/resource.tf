@@ -0,0 +1,32 @@
+# Counter entry holding the next free uidNumber. Changes to its attributes are ignored, so that
+# applying it does not reset the counter advanced by ldap_next_id.
+resource "ldap_entry" "uid_counter" {
+  dn = "cn=uidNumber,ou=counters,dc=example,dc=com"
+  attributes = {
+    objectClass = ["device", "extensibleObject"]
+    cn          = ["uidNumber"]
+    uidNumber   = ["10000"]
+  }
+
+  lifecycle {
+    ignore_changes = [attributes]
+  }
+}
+
+resource "ldap_next_id" "jdoe_uid" {
+  dn        = ldap_entry.uid_counter.dn
+  attribute = "uidNumber"
+}
+
+resource "ldap_entry" "jdoe" {
+  dn = "uid=jdoe,ou=users,dc=example,dc=com"
+  attributes = {
+    objectClass   = ["inetOrgPerson", "posixAccount"]
+    uid           = ["jdoe"]
+    cn            = ["John Doe"]
+    sn            = ["Doe"]
+    uidNumber     = [tostring(ldap_next_id.jdoe_uid.value)]
+    gidNumber     = ["10000"]
+    homeDirectory = ["/home/jdoe"]
+  }
+}
@@ -0,0 +1,22 @@
+# Create a user and add it to a group atomically: if either operation fails, neither is applied
+resource "ldap_transaction" "onboard_jdoe" {
+  operations = [
+    {
+      dn     = "uid=jdoe,ou=users,dc=example,dc=com"
+      action = "add"
+      attributes = {
+        objectClass = ["inetOrgPerson"]
+        uid         = ["jdoe"]
+        cn          = ["John Doe"]
+        sn          = ["Doe"]
+      }
+    },
+    {
+      dn     = "cn=developers,ou=groups,dc=example,dc=com"
+      action = "add_values"
+      attributes = {
+        member = ["uid=jdoe,ou=users,dc=example,dc=com"]
+      }
+    },
+  ]
+}
@@ -0,0 +1,61 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &AttrValuesEqualFunction{}
+
+func NewAttrValuesEqualFunction() function.Function {
+	return &AttrValuesEqualFunction{}
+}
+
+// AttrValuesEqualFunction compares two attribute value lists the way ldap_entry detects changes.
+type AttrValuesEqualFunction struct{}
+
+func (f *AttrValuesEqualFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "attr_values_equal"
+}
+
+func (f *AttrValuesEqualFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Compare two attribute value lists as ldap_entry does",
+		MarkdownDescription: "Returns whether two lists of attribute values are equal the way `ldap_entry` decides whether an attribute changed, " +
+			"e.g. for a `precondition` or `postcondition` that should agree with the provider's plan. " +
+			"Order does not matter, but duplicates do: `[\"a\", \"a\", \"b\"]` and `[\"a\", \"b\", \"b\"]` differ. " +
+			"Values are compared exactly, including case.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:                "a",
+				MarkdownDescription: "First list of values. `null` is treated as an empty list.",
+				ElementType:         types.StringType,
+				AllowNullValue:      true,
+			},
+			function.ListParameter{
+				Name:                "b",
+				MarkdownDescription: "Second list of values. `null` is treated as an empty list.",
+				ElementType:         types.StringType,
+				AllowNullValue:      true,
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *AttrValuesEqualFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var a, b []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &a, &b))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, stringSlicesEqual(a, b)))
+}
@@ -0,0 +1,82 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestAttrValuesEqualFunction_Run(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        []string
+		b        []string
+		expected bool
+	}{
+		{name: "empty lists", a: []string{}, b: []string{}, expected: true},
+		{name: "null lists", a: nil, b: nil, expected: true},
+		{name: "one null one empty", a: nil, b: []string{}, expected: true},
+		{name: "equal single elements", a: []string{"a"}, b: []string{"a"}, expected: true},
+		{name: "different single elements", a: []string{"a"}, b: []string{"b"}, expected: false},
+		{name: "same elements same order", a: []string{"a", "b", "c"}, b: []string{"a", "b", "c"}, expected: true},
+		{name: "same elements different order", a: []string{"c", "a", "b"}, b: []string{"a", "b", "c"}, expected: true},
+		{name: "different lengths", a: []string{"a", "b"}, b: []string{"a", "b", "c"}, expected: false},
+		{name: "duplicates in both - same count", a: []string{"a", "a", "b"}, b: []string{"b", "a", "a"}, expected: true},
+		{name: "duplicates - different count", a: []string{"a", "a", "b"}, b: []string{"a", "b", "b"}, expected: false},
+		{name: "duplicate against single", a: []string{"a", "a"}, b: []string{"a"}, expected: false},
+		{name: "case sensitive", a: []string{"a", "B"}, b: []string{"A", "b"}, expected: false},
+		{
+			name:     "ldap objectClass example",
+			a:        []string{"person", "organizationalPerson", "inetOrgPerson"},
+			b:        []string{"inetOrgPerson", "person", "organizationalPerson"},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					testAttrValuesList(tt.a),
+					testAttrValuesList(tt.b),
+				}),
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.BoolUnknown()),
+			}
+
+			NewAttrValuesEqualFunction().Run(context.Background(), req, resp)
+
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+
+			if !resp.Result.Value().Equal(types.BoolValue(tt.expected)) {
+				t.Errorf("attr_values_equal(%v, %v) = %s, want %v", tt.a, tt.b, resp.Result.Value(), tt.expected)
+			}
+
+			// Must agree with the drift detection of ldap_entry
+			if result := stringSlicesEqual(tt.a, tt.b); result != tt.expected {
+				t.Errorf("stringSlicesEqual(%v, %v) = %v, want %v", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
+
+// testAttrValuesList returns values as a list value, null for a nil slice.
+func testAttrValuesList(values []string) types.List {
+	if values == nil {
+		return types.ListNull(types.StringType)
+	}
+	elements := make([]attr.Value, len(values))
+	for i, v := range values {
+		elements[i] = types.StringValue(v)
+	}
+	return types.ListValueMust(types.StringType, elements)
+}
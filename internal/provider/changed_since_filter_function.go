@@ -0,0 +1,118 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &ChangedSinceFilterFunction{}
+
+func NewChangedSinceFilterFunction() function.Function {
+	return &ChangedSinceFilterFunction{}
+}
+
+// ChangedSinceFilterFunction composes a search filter matching entries changed at or after a point in time.
+type ChangedSinceFilterFunction struct{}
+
+func (f *ChangedSinceFilterFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "changed_since_filter"
+}
+
+func (f *ChangedSinceFilterFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Build a filter for entries changed since a timestamp",
+		MarkdownDescription: "Combines `base_filter` with a `whenChanged` greater-or-equal assertion, formatting `timestamp` as LDAP generalized time in UTC. " +
+			"For example `changed_since_filter(\"(objectClass=user)\", \"2024-01-02T03:04:05Z\")` returns `(&(objectClass=user)(whenChanged>=20240102030405Z))`. " +
+			"An empty `base_filter` returns only the `whenChanged` assertion.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "base_filter",
+				MarkdownDescription: "Filter to combine with the time bound. Outer parentheses are added if missing.",
+			},
+			function.StringParameter{
+				Name:                "timestamp",
+				MarkdownDescription: "RFC 3339 timestamp, e.g. the output of `timestamp()` or `timeadd()`.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ChangedSinceFilterFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var baseFilter, timestamp string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &baseFilter, &timestamp))
+	if resp.Error != nil {
+		return
+	}
+
+	since, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("timestamp must be in RFC 3339 format: %s", err))
+		return
+	}
+
+	filter, err := buildChangedSinceFilter(baseFilter, since)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, filter))
+}
+
+// formatGeneralizedTime formats t as LDAP generalized time (RFC 4517) in UTC with second precision.
+func formatGeneralizedTime(t time.Time) string {
+	return t.UTC().Format("20060102150405Z")
+}
+
+// buildChangedSinceFilter ANDs baseFilter with a whenChanged>=since assertion and validates the result.
+func buildChangedSinceFilter(baseFilter string, since time.Time) (string, error) {
+	timeFilter := fmt.Sprintf("(whenChanged>=%s)", formatGeneralizedTime(since))
+
+	baseFilter = strings.TrimSpace(baseFilter)
+	if baseFilter == "" {
+		return timeFilter, nil
+	}
+
+	filter := fmt.Sprintf("(&%s%s)", parenthesizeFilter(baseFilter), timeFilter)
+	if _, err := ldap.CompileFilter(filter); err != nil {
+		return "", fmt.Errorf("invalid base_filter %q: %s", baseFilter, err)
+	}
+
+	return filter, nil
+}
+
+// parenthesizeFilter wraps a filter in parentheses unless it is already a single parenthesized filter.
+// Literal parentheses inside assertion values must be escaped (\28, \29) per RFC 4515, so every
+// bare parenthesis is structural.
+func parenthesizeFilter(filter string) string {
+	if strings.HasPrefix(filter, "(") {
+		depth := 0
+		for i, c := range filter {
+			switch c {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			if depth == 0 {
+				if i == len(filter)-1 {
+					return filter
+				}
+				break
+			}
+		}
+	}
+
+	return "(" + filter + ")"
+}
@@ -0,0 +1,175 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestFormatGeneralizedTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    time.Time
+		expected string
+	}{
+		{
+			name:     "utc",
+			input:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			expected: "20240102030405Z",
+		},
+		{
+			name:     "offset converted to utc",
+			input:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("EST", -5*60*60)),
+			expected: "20240102080405Z",
+		},
+		{
+			name:     "fractional seconds dropped",
+			input:    time.Date(2024, 12, 31, 23, 59, 59, 999000000, time.UTC),
+			expected: "20241231235959Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatGeneralizedTime(tt.input)
+			if result != tt.expected {
+				t.Errorf("formatGeneralizedTime(%v) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildChangedSinceFilter(t *testing.T) {
+	since := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		baseFilter  string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:       "parenthesized base",
+			baseFilter: "(objectClass=*)",
+			expected:   "(&(objectClass=*)(whenChanged>=20240102030405Z))",
+		},
+		{
+			name:       "unparenthesized base",
+			baseFilter: "objectClass=user",
+			expected:   "(&(objectClass=user)(whenChanged>=20240102030405Z))",
+		},
+		{
+			name:       "compound base",
+			baseFilter: "(&(objectClass=user)(cn=a*))",
+			expected:   "(&(&(objectClass=user)(cn=a*))(whenChanged>=20240102030405Z))",
+		},
+		{
+			// Wrapping "(a)(b)" yields "((a)(b))", which is not a valid filter
+			name:        "adjacent filters without operator",
+			baseFilter:  "(objectClass=user)(cn=a*)",
+			expectError: true,
+		},
+		{
+			name:       "escaped parenthesis in value",
+			baseFilter: `(cn=foo\28bar\29)`,
+			expected:   `(&(cn=foo\28bar\29)(whenChanged>=20240102030405Z))`,
+		},
+		{
+			name:       "empty base",
+			baseFilter: "",
+			expected:   "(whenChanged>=20240102030405Z)",
+		},
+		{
+			name:       "whitespace base",
+			baseFilter: "  ",
+			expected:   "(whenChanged>=20240102030405Z)",
+		},
+		{
+			name:        "invalid base",
+			baseFilter:  "(objectClass=*",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := buildChangedSinceFilter(tt.baseFilter, since)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("buildChangedSinceFilter(%q) expected error, got %q", tt.baseFilter, result)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("buildChangedSinceFilter(%q) unexpected error: %v", tt.baseFilter, err)
+			}
+
+			if result != tt.expected {
+				t.Errorf("buildChangedSinceFilter(%q) = %q, want %q", tt.baseFilter, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestChangedSinceFilterFunction_Run(t *testing.T) {
+	tests := []struct {
+		name        string
+		baseFilter  string
+		timestamp   string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:       "valid",
+			baseFilter: "(objectClass=user)",
+			timestamp:  "2024-01-02T03:04:05+01:00",
+			expected:   "(&(objectClass=user)(whenChanged>=20240102020405Z))",
+		},
+		{
+			name:        "invalid timestamp",
+			baseFilter:  "(objectClass=user)",
+			timestamp:   "2024-01-02",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue(tt.baseFilter),
+					types.StringValue(tt.timestamp),
+				}),
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.StringUnknown()),
+			}
+
+			NewChangedSinceFilterFunction().Run(context.Background(), req, resp)
+
+			if tt.expectError {
+				if resp.Error == nil {
+					t.Errorf("expected error, got result %s", resp.Result.Value())
+				}
+				return
+			}
+
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+
+			if !resp.Result.Value().Equal(types.StringValue(tt.expected)) {
+				t.Errorf("result = %s, want %q", resp.Result.Value(), tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,850 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"golang.org/x/net/proxy"
+)
+
+// LdapClient is the provider data handed to resources and data sources: the bound connection
+// plus provider-level settings that influence how requests are built. The connection is only
+// reachable through the methods of the client, which hold connMu and reconnect as configured.
+type LdapClient struct {
+	// conn is the bound connection requests are sent on, see connection.
+	conn *ldap.Conn
+
+	// connMu guards conn while it is replaced, see SetMaxConnectionAge. Requests hold it for reading.
+	connMu sync.RWMutex
+	// connectedAt is when conn was dialed.
+	connectedAt time.Time
+	// maxConnectionAge is how long conn is used before it is replaced. Zero disables recycling.
+	maxConnectionAge time.Duration
+	// reconnect replaces conn and retries requests failing because it broke, see EnableReconnect.
+	reconnect bool
+	// bind authenticates a newly dialed connection like the original one.
+	bind func(*ldap.Conn) error
+
+	// BaseDN is appended to relative DNs, see ResolveDN. Empty disables DN resolution.
+	BaseDN string
+
+	// ExactAttributeNames disables case folding of attribute names, see FoldsAttributeNames.
+	ExactAttributeNames bool
+
+	// MaxValueBytes is the largest attribute value ldap_entry accepts unless it sets its own
+	// max_value_bytes. Zero means no limit.
+	MaxValueBytes int
+
+	// StrictRead fails reads of attribute values that cannot be represented instead of
+	// base64-encoding them with a warning, see LenientReads.
+	StrictRead bool
+
+	// AllowedBaseDNs restricts ldap_entry to entries below one of these DNs, see AllowsDN. Empty
+	// allows every DN.
+	AllowedBaseDNs []string
+
+	// ProtectedObjectClasses are the object classes of entries ldap_entry refuses to delete, see
+	// ProtectedClassesOf.
+	ProtectedObjectClasses []string
+
+	// dial opens a new connection to the server the client is connected to, see DialAnonymous.
+	dial func() (*ldap.Conn, error)
+
+	// referralDial opens a secured and bound connection to the server named by a referral URL.
+	// Writes referred elsewhere are repeated there if set, see followReferral.
+	referralDial func(referralURL string) (*ldap.Conn, error)
+
+	// searches caches data source search results when search_cache is enabled, see CachedSearch.
+	searches *searchCache
+
+	// schema caches the server schema, see Schema.
+	schemaMu sync.Mutex
+	schema   *LdapSchema
+}
+
+// SetMaxConnectionAge makes the client replace its connection with a newly dialed and bound one
+// before the next request once the connection is older than age, for servers that silently drop
+// long-lived sessions. Requests in flight finish on the old connection, which is then closed.
+// bind authenticates the new connection; nil leaves it anonymous.
+func (c *LdapClient) SetMaxConnectionAge(age time.Duration, bind func(*ldap.Conn) error) {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+
+	c.maxConnectionAge = age
+	c.bind = bind
+	c.connectedAt = time.Now()
+}
+
+// EnableReconnect makes the client replace its connection with a newly dialed and bound one when a
+// request fails because the connection broke, e.g. because the server dropped it after its idle
+// timeout, and repeat the request once on the new connection. A write whose connection broke
+// before the result arrived may have been applied, so the repetition can fail, e.g. with
+// entryAlreadyExists. bind authenticates the new connection; nil leaves it anonymous.
+func (c *LdapClient) EnableReconnect(bind func(*ldap.Conn) error) {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+
+	c.reconnect = true
+	c.bind = bind
+}
+
+// connection returns the connection to use for a request, replacing it first if it exceeds the
+// maximum connection age. The caller must call release once the request is done.
+func (c *LdapClient) connection() (conn *ldap.Conn, release func(), err error) {
+	c.connMu.RLock()
+	if c.maxConnectionAge <= 0 || time.Since(c.connectedAt) < c.maxConnectionAge {
+		return c.conn, c.connMu.RUnlock, nil
+	}
+	c.connMu.RUnlock()
+
+	c.connMu.Lock()
+	// Another request may have replaced it in the meantime
+	if time.Since(c.connectedAt) >= c.maxConnectionAge {
+		if err := c.recycle(fmt.Sprintf("older than max_connection_age (%s)", c.maxConnectionAge)); err != nil {
+			c.connMu.Unlock()
+			return nil, nil, err
+		}
+	}
+	c.connMu.Unlock()
+
+	c.connMu.RLock()
+	return c.conn, c.connMu.RUnlock, nil
+}
+
+// recycle replaces conn with a newly dialed and bound connection; reason describes the replaced
+// one for errors. connMu must be held for writing.
+func (c *LdapClient) recycle(reason string) error {
+	if c.dial == nil {
+		return errors.New("no LDAP server to connect to")
+	}
+
+	conn, err := c.dial()
+	if err != nil {
+		return fmt.Errorf("unable to replace connection %s: %w", reason, err)
+	}
+	if c.bind != nil {
+		if err := c.bind(conn); err != nil {
+			conn.Close()
+			return fmt.Errorf("unable to bind connection replacing one %s: %w", reason, err)
+		}
+	}
+
+	if c.conn != nil {
+		c.conn.Close()
+	}
+	c.conn = conn
+	c.connectedAt = time.Now()
+	return nil
+}
+
+// do runs request on the current connection, see connection. If reconnecting is enabled and the
+// request failed because the connection broke, the connection is replaced and request runs again.
+func (c *LdapClient) do(request func(conn *ldap.Conn) error) error {
+	conn, release, err := c.connection()
+	if err != nil {
+		return err
+	}
+	err = request(conn)
+	release()
+	if !c.reconnect || !connectionBroken(conn, err) {
+		return err
+	}
+
+	c.connMu.Lock()
+	// Another request may have replaced it in the meantime
+	if c.conn == conn {
+		if rerr := c.recycle("that broke"); rerr != nil {
+			c.connMu.Unlock()
+			return fmt.Errorf("%w (%s)", err, rerr)
+		}
+	}
+	c.connMu.Unlock()
+
+	conn, release, err = c.connection()
+	if err != nil {
+		return err
+	}
+	defer release()
+	return request(conn)
+}
+
+// connectionBroken reports whether err, the result of a request on conn, means that conn broke,
+// e.g. because the server closed it. Requests that timed out leave the connection usable.
+func connectionBroken(conn *ldap.Conn, err error) bool {
+	return ldap.IsErrorWithCode(err, ldap.ErrorNetwork) && conn.IsClosing()
+}
+
+// Search runs a search on the current connection, see do.
+func (c *LdapClient) Search(searchRequest *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	var sr *ldap.SearchResult
+	err := c.do(func(conn *ldap.Conn) error {
+		var err error
+		sr, err = conn.Search(searchRequest)
+		return err
+	})
+	return sr, err
+}
+
+// SearchPages runs a search fetching the results in pages on the current connection, see do and
+// searchPages. A paged search interrupted by a broken connection is run again from the first page,
+// as the cookie of the next page is only valid on the connection that returned it.
+func (c *LdapClient) SearchPages(searchRequest *ldap.SearchRequest, pageSize uint32, onPage func(page int, entries int)) (*ldap.SearchResult, error) {
+	var sr *ldap.SearchResult
+	err := c.do(func(conn *ldap.Conn) error {
+		var err error
+		sr, err = searchPages(conn, searchRequest, pageSize, onPage)
+		return err
+	})
+	return sr, err
+}
+
+// Extended sends an extended request on the current connection, see do. The requests of a
+// transaction must share one connection and are sent by RunTransaction instead.
+func (c *LdapClient) Extended(request *ldap.ExtendedRequest) (*ldap.ExtendedResponse, error) {
+	var resp *ldap.ExtendedResponse
+	err := c.do(func(conn *ldap.Conn) error {
+		var err error
+		resp, err = conn.Extended(request)
+		return err
+	})
+	return resp, err
+}
+
+// Add adds an entry on the current connection, see do, following referrals if enabled.
+func (c *LdapClient) Add(addRequest *ldap.AddRequest) error {
+	return c.do(func(conn *ldap.Conn) error {
+		return c.followReferral(conn.Add(addRequest), addRequest.DN, func(conn *ldap.Conn, dn string) error {
+			referred := *addRequest
+			referred.DN = dn
+			return conn.Add(&referred)
+		})
+	})
+}
+
+// Modify modifies an entry on the current connection, see do, following referrals if enabled.
+func (c *LdapClient) Modify(modifyRequest *ldap.ModifyRequest) error {
+	return c.do(func(conn *ldap.Conn) error {
+		return c.followReferral(conn.Modify(modifyRequest), modifyRequest.DN, func(conn *ldap.Conn, dn string) error {
+			referred := *modifyRequest
+			referred.DN = dn
+			return conn.Modify(&referred)
+		})
+	})
+}
+
+// ModifyDN renames an entry on the current connection, see do, following referrals if enabled.
+func (c *LdapClient) ModifyDN(modifyDNRequest *ldap.ModifyDNRequest) error {
+	return c.do(func(conn *ldap.Conn) error {
+		return c.followReferral(conn.ModifyDN(modifyDNRequest), modifyDNRequest.DN, func(conn *ldap.Conn, dn string) error {
+			referred := *modifyDNRequest
+			referred.DN = dn
+			return conn.ModifyDN(&referred)
+		})
+	})
+}
+
+// Del deletes an entry on the current connection, see do, following referrals if enabled.
+func (c *LdapClient) Del(delRequest *ldap.DelRequest) error {
+	return c.do(func(conn *ldap.Conn) error {
+		return c.followReferral(conn.Del(delRequest), delRequest.DN, func(conn *ldap.Conn, dn string) error {
+			referred := *delRequest
+			referred.DN = dn
+			return conn.Del(&referred)
+		})
+	})
+}
+
+// EnableSearchCache makes CachedSearch reuse the results of identical searches.
+func (c *LdapClient) EnableSearchCache() {
+	c.searches = newSearchCache()
+}
+
+// CachedSearch runs a search like LdapSearch, reusing the result of an identical earlier search
+// if the search cache is enabled. Use it for data sources only: resources must see their own writes.
+func (c *LdapClient) CachedSearch(baseDN string, scope string, filter string, attributes []string, opts LdapSearchOptions) (*ldap.SearchResult, error) {
+	if c.searches == nil {
+		return LdapSearch(c, baseDN, scope, filter, attributes, opts)
+	}
+	return c.searches.search(c, baseDN, scope, filter, attributes, opts)
+}
+
+// DialAnonymous opens a new, unbound connection to the same server, through the same proxy and with
+// the same TLS settings as the client. Requests on it are anonymous. The caller must close it.
+func (c *LdapClient) DialAnonymous() (*ldap.Conn, error) {
+	if c == nil || c.dial == nil {
+		return nil, errors.New("no LDAP server to connect to")
+	}
+	return c.dial()
+}
+
+// FoldsAttributeNames reports whether attribute names differing only in case are treated as the
+// same attribute, as LDAP does. This is the default; a nil client folds too.
+func (c *LdapClient) FoldsAttributeNames() bool {
+	return c == nil || !c.ExactAttributeNames
+}
+
+// LenientReads reports whether attribute values that cannot be represented are base64-encoded
+// with a warning instead of failing the read, see MarshalOptions.Lenient. This is the default; a
+// nil client is lenient too.
+func (c *LdapClient) LenientReads() bool {
+	return c == nil || !c.StrictRead
+}
+
+// AllowsDN reports whether dn is a descendant of one of AllowedBaseDNs, compared ignoring case
+// and insignificant spaces. Every DN is allowed without AllowedBaseDNs; a DN that cannot be
+// parsed is not allowed otherwise.
+func (c *LdapClient) AllowsDN(dn string) bool {
+	if c == nil || len(c.AllowedBaseDNs) == 0 {
+		return true
+	}
+
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range c.AllowedBaseDNs {
+		base, err := ldap.ParseDN(allowed)
+		if err == nil && base.AncestorOfFold(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProtectedClassesOf returns the object classes in objectClasses that are among
+// ProtectedObjectClasses, compared ignoring case. An entry with any of them must not be deleted.
+func (c *LdapClient) ProtectedClassesOf(objectClasses []string) []string {
+	if c == nil {
+		return nil
+	}
+
+	var protected []string
+	for _, objectClass := range objectClasses {
+		if containsFold(c.ProtectedObjectClasses, objectClass) {
+			protected = append(protected, objectClass)
+		}
+	}
+	return protected
+}
+
+// ResolveDN returns dn as an absolute DN. If BaseDN is set and dn does not already end with it
+// (compared as DNs, ignoring case and insignificant spaces), ",<BaseDN>" is appended. An empty dn,
+// which addresses the root DSE, and DNs that cannot be parsed are returned unchanged.
+func (c *LdapClient) ResolveDN(dn string) string {
+	if c == nil || c.BaseDN == "" || strings.TrimSpace(dn) == "" {
+		return dn
+	}
+
+	base, err := ldap.ParseDN(c.BaseDN)
+	if err != nil {
+		return dn
+	}
+
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return dn
+	}
+
+	if parsed.EqualFold(base) || base.AncestorOfFold(parsed) {
+		return dn
+	}
+
+	return dn + "," + c.BaseDN
+}
+
+// parseProxyURL validates a proxy URL for tunneling LDAP connections.
+// Supported schemes are socks5, socks5h (hostname resolved by the proxy) and http (CONNECT).
+func parseProxyURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "socks5", "socks5h", "http":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q, must be one of socks5, socks5h or http", u.Scheme)
+	}
+
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("proxy URL %q is missing a host", rawURL)
+	}
+
+	if u.Port() == "" {
+		return nil, fmt.Errorf("proxy URL %q is missing a port", rawURL)
+	}
+
+	return u, nil
+}
+
+// newProxyDialer returns a dialer that tunnels TCP connections through the proxy at proxyURL.
+// Credentials embedded in the URL (user:password@) are used to authenticate to the proxy.
+func newProxyDialer(proxyURL *url.URL, forward *net.Dialer) (proxy.Dialer, error) {
+	if proxyURL.Scheme == "http" {
+		return &httpConnectDialer{proxyURL: proxyURL, forward: forward}, nil
+	}
+
+	return proxy.FromURL(proxyURL, forward)
+}
+
+// dialLdapViaProxy connects to an ldap:// or ldaps:// URL through dialer. For ldaps:// the TLS
+// handshake is performed end-to-end with the directory server over the tunnel, so the proxy only
+// relays encrypted traffic and the certificate is verified against the LDAP URL host.
+func dialLdapViaProxy(ldapURL string, dialer proxy.Dialer, tlsConfig *tls.Config) (*ldap.Conn, error) {
+	u, err := url.Parse(ldapURL)
+	if err != nil {
+		return nil, ldap.NewError(ldap.ErrorNetwork, err)
+	}
+
+	port := u.Port()
+	switch u.Scheme {
+	case "ldap":
+		if port == "" {
+			port = ldap.DefaultLdapPort
+		}
+	case "ldaps":
+		if port == "" {
+			port = ldap.DefaultLdapsPort
+		}
+	default:
+		return nil, ldap.NewError(ldap.ErrorNetwork, fmt.Errorf("scheme %q is not supported through a proxy, use ldap:// or ldaps://", u.Scheme))
+	}
+
+	c, err := dialer.Dial("tcp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		return nil, ldap.NewError(ldap.ErrorNetwork, err)
+	}
+
+	if u.Scheme == "ldaps" {
+		cfg := tlsConfig.Clone()
+		if cfg.ServerName == "" {
+			cfg.ServerName = u.Hostname()
+		}
+
+		tlsConn := tls.Client(c, cfg)
+		if err := tlsConn.Handshake(); err != nil {
+			c.Close()
+			return nil, ldap.NewError(ldap.ErrorNetwork, err)
+		}
+		c = tlsConn
+	}
+
+	conn := ldap.NewConn(c, u.Scheme == "ldaps")
+	conn.Start()
+	return conn, nil
+}
+
+// httpConnectDialer tunnels TCP connections through an HTTP proxy using the CONNECT method.
+type httpConnectDialer struct {
+	proxyURL *url.URL
+	forward  proxy.Dialer
+}
+
+func (d *httpConnectDialer) Dial(network, addr string) (net.Conn, error) {
+	c, err := d.forward.Dial("tcp", d.proxyURL.Host)
+	if err != nil {
+		return nil, fmt.Errorf("unable to reach HTTP proxy %s: %w", d.proxyURL.Host, err)
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if user := d.proxyURL.User; user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if err := req.Write(c); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("unable to send CONNECT to HTTP proxy: %w", err)
+	}
+
+	// LDAP is client-first, so nothing beyond the CONNECT response is buffered here.
+	resp, err := http.ReadResponse(bufio.NewReader(c), req)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("unable to read CONNECT response from HTTP proxy: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.Close()
+		return nil, fmt.Errorf("HTTP proxy refused CONNECT to %s: %s", addr, resp.Status)
+	}
+
+	return c, nil
+}
+
+// clientCertificateSource supplies the certificate presented when the server requests one during
+// the TLS handshake. The private key of the returned certificate only needs to implement
+// crypto.Signer, so keys that cannot be exported, such as keys held in an HSM, can be used.
+// Releases are built without cgo, which rules out sources that load native libraries such as
+// PKCS#11 modules.
+type clientCertificateSource interface {
+	ClientCertificate(info *tls.CertificateRequestInfo) (*tls.Certificate, error)
+}
+
+// staticClientCertificate presents the same certificate, loaded once, on every request.
+type staticClientCertificate struct {
+	certificate *tls.Certificate
+}
+
+func (s staticClientCertificate) ClientCertificate(info *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return s.certificate, nil
+}
+
+// appendCACertificates adds the PEM-encoded certificates in data to pool. Blocks of other types are
+// skipped, but data must hold at least one certificate and every certificate must be valid.
+func appendCACertificates(pool *x509.CertPool, data []byte) error {
+	found := false
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("invalid certificate: %w", err)
+		}
+		pool.AddCert(cert)
+		found = true
+	}
+	if !found {
+		return errors.New("no PEM CERTIFICATE block found")
+	}
+	return nil
+}
+
+// readCertificatePEM reads the PEM-encoded certificate chain in file, leaf certificate first.
+func readCertificatePEM(file string) ([]byte, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM CERTIFICATE block found in %s", file)
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return nil, fmt.Errorf("invalid certificate in %s: %w", file, err)
+		}
+		return data, nil
+	}
+}
+
+// readPrivateKeyPEM reads the PEM-encoded private key in file, decrypting it with password if it
+// is encrypted. Only legacy encrypted PEM blocks ("Proc-Type: 4,ENCRYPTED") can be decrypted.
+func readPrivateKeyPEM(file string, password string) ([]byte, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var block *pem.Block
+	for rest := data; ; {
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM PRIVATE KEY block found in %s", file)
+		}
+		if strings.HasSuffix(block.Type, "PRIVATE KEY") {
+			break
+		}
+	}
+
+	if block.Type == "ENCRYPTED PRIVATE KEY" {
+		return nil, fmt.Errorf("%s holds an encrypted PKCS#8 key, which is not supported: convert it to an unencrypted key or a legacy encrypted PEM key", file)
+	}
+	//nolint:staticcheck // Legacy PEM encryption is what client_key_password decrypts.
+	if !x509.IsEncryptedPEMBlock(block) {
+		if password != "" {
+			return nil, fmt.Errorf("%s is not encrypted, but client_key_password is set", file)
+		}
+		return pem.EncodeToMemory(block), nil
+	}
+	if password == "" {
+		return nil, fmt.Errorf("%s is encrypted, but client_key_password is not set", file)
+	}
+
+	//nolint:staticcheck // Legacy PEM encryption is what client_key_password decrypts.
+	der, err := x509.DecryptPEMBlock(block, []byte(password))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt %s with client_key_password: %w", file, err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: block.Type, Bytes: der}), nil
+}
+
+// defaultTLSMinVersion is the tls_min_version of providers not setting it.
+const defaultTLSMinVersion = "1.2"
+
+// tlsMinVersions maps the accepted values of tls_min_version to TLS versions.
+var tlsMinVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// parseTLSMinVersion returns the TLS version of a tls_min_version value.
+func parseTLSMinVersion(value string) (uint16, error) {
+	version, ok := tlsMinVersions[value]
+	if !ok {
+		return 0, fmt.Errorf("unsupported TLS minimum version %q, expected \"1.2\" or \"1.3\"", value)
+	}
+	return version, nil
+}
+
+// newTLSConfig returns the TLS configuration for connections to the server. The server certificate
+// is verified against rootCAs, or the system roots if nil; insecure skips the verification unless
+// rootCAs is set. certificates may be nil to present no client certificate.
+func newTLSConfig(insecure bool, rootCAs *x509.CertPool, certificates clientCertificateSource) *tls.Config {
+	config := &tls.Config{
+		InsecureSkipVerify: insecure && rootCAs == nil,
+		RootCAs:            rootCAs,
+	}
+	if certificates != nil {
+		config.GetClientCertificate = certificates.ClientCertificate
+	}
+	return config
+}
+
+// tlsConfigForServer returns a copy of config that verifies the server certificate against
+// serverName, rather than the host name of the URL dialed.
+func tlsConfigForServer(config *tls.Config, serverName string) *tls.Config {
+	config = config.Clone()
+	config.ServerName = serverName
+	return config
+}
+
+// saslExternal is the SASL mechanism that authenticates with the identity established outside LDAP,
+// i.e. the TLS client certificate or, over ldapi://, the credentials of the local process.
+const saslExternal = "EXTERNAL"
+
+// authMethodAnonymous is the auth_method that sends an explicit anonymous bind.
+const authMethodAnonymous = "anonymous"
+
+// sessionConn is the subset of *ldap.Conn used to secure and authenticate a new connection.
+type sessionConn interface {
+	StartTLS(config *tls.Config) error
+	Bind(username string, password string) error
+	UnauthenticatedBind(username string) error
+	ExternalBind() error
+	NTLMBind(domain string, username string, password string) error
+	NTLMBindWithHash(domain string, username string, hash string) error
+	SetTimeout(timeout time.Duration)
+}
+
+// sessionOptions describes how a newly dialed connection is secured and authenticated.
+type sessionOptions struct {
+	StartTLS      bool
+	TLSConfig     *tls.Config
+	SASLMechanism string
+	BindDN        string
+	BindPW        string
+	BindTimeout   time.Duration
+
+	// AnonymousBind sends an explicit anonymous bind instead of not binding at all.
+	AnonymousBind bool
+
+	// RequestTimeout limits how long every request on the connection waits for the server's
+	// answer; zero means no limit.
+	RequestTimeout time.Duration
+
+	// NTLM binds with NTLMDomain and NTLMUsername, authenticated by NTLMPassword or, if set, by
+	// the NT hash NTLMHash, instead of a simple bind.
+	NTLM         bool
+	NTLMDomain   string
+	NTLMUsername string
+	NTLMPassword string
+	NTLMHash     string
+}
+
+// validate reports combinations that can never authenticate for a connection to a URL with scheme.
+func (o sessionOptions) validate(scheme string) error {
+	if o.StartTLS && scheme != "ldap" {
+		return fmt.Errorf("start_tls upgrades ldap:// connections and cannot be used with %s://", scheme)
+	}
+
+	if o.NTLM {
+		if err := o.validateNTLM(); err != nil {
+			return err
+		}
+	}
+
+	if o.AnonymousBind && (o.BindDN != "" || o.BindPW != "" || o.SASLMechanism != "" || o.NTLM) {
+		return errors.New("auth_method anonymous binds without credentials and cannot be combined with bind_dn, bind_password, sasl_mechanism or ntlm")
+	}
+
+	if o.SASLMechanism != saslExternal {
+		return nil
+	}
+	if o.BindDN != "" || o.BindPW != "" {
+		return errors.New("sasl_mechanism EXTERNAL takes the identity from the connection and cannot be combined with bind_dn or bind_password")
+	}
+	if scheme == "ldapi" {
+		return nil
+	}
+	if scheme != "ldaps" && !o.StartTLS {
+		return fmt.Errorf("sasl_mechanism EXTERNAL over %s:// has no identity to use: connect with ldapi://, or use TLS (ldaps:// or start_tls) with a client certificate", scheme)
+	}
+	if o.TLSConfig == nil || (o.TLSConfig.GetClientCertificate == nil && len(o.TLSConfig.Certificates) == 0) {
+		return errors.New("sasl_mechanism EXTERNAL over TLS needs a client certificate, but client_cert_file is not set")
+	}
+	return nil
+}
+
+// validateNTLM reports NTLM settings that can never authenticate.
+func (o sessionOptions) validateNTLM() error {
+	if o.SASLMechanism != "" {
+		return errors.New("ntlm cannot be combined with sasl_mechanism")
+	}
+	if o.BindDN != "" || o.BindPW != "" {
+		return errors.New("ntlm binds as ntlm.username and cannot be combined with bind_dn or bind_password")
+	}
+	if o.NTLMDomain == "" || o.NTLMUsername == "" {
+		return errors.New("ntlm needs a non-empty domain and username")
+	}
+	if (o.NTLMPassword == "") == (o.NTLMHash == "") {
+		return errors.New("ntlm needs exactly one of password and hash")
+	}
+	if o.NTLMHash != "" {
+		if _, err := hex.DecodeString(o.NTLMHash); err != nil || len(o.NTLMHash) != 32 {
+			return errors.New("ntlm.hash must be an NT hash of 32 hexadecimal characters")
+		}
+	}
+	return nil
+}
+
+// unencryptedNTLM reports whether the NTLM bind would be sent over a connection to a URL with
+// scheme that is not encrypted.
+func (o sessionOptions) unencryptedNTLM(scheme string) bool {
+	return o.NTLM && scheme == "ldap" && !o.StartTLS
+}
+
+// identity describes who authenticate binds as, for error messages.
+func (o sessionOptions) identity() string {
+	switch {
+	case o.SASLMechanism != "":
+		return "SASL " + o.SASLMechanism
+	case o.NTLM:
+		return fmt.Sprintf("NTLM user %s\\%s", o.NTLMDomain, o.NTLMUsername)
+	case o.AnonymousBind:
+		return "the anonymous identity"
+	default:
+		return "DN " + o.BindDN
+	}
+}
+
+// secure applies the request timeout to conn and upgrades it to TLS with StartTLS if requested. It
+// must complete before authenticate, so that the credentials, or the client certificate EXTERNAL
+// relies on, are sent over TLS.
+func (o sessionOptions) secure(conn sessionConn) error {
+	conn.SetTimeout(o.RequestTimeout)
+	if !o.StartTLS {
+		return nil
+	}
+	if err := conn.StartTLS(o.TLSConfig); err != nil {
+		return fmt.Errorf("StartTLS failed: %w", extendedOperationError("StartTLS", oidStartTLS, err))
+	}
+	return nil
+}
+
+// authenticate binds conn as configured: with SASL EXTERNAL, with NTLM, with an explicit anonymous
+// bind, with a simple bind if a bind DN is set, or not at all, leaving the connection anonymous.
+func (o sessionOptions) authenticate(conn sessionConn) error {
+	if o.SASLMechanism == saslExternal {
+		return withBindTimeout(conn, o.BindTimeout, o.RequestTimeout, conn.ExternalBind)
+	}
+	if o.NTLM {
+		return withBindTimeout(conn, o.BindTimeout, o.RequestTimeout, func() error {
+			if o.NTLMHash != "" {
+				return conn.NTLMBindWithHash(o.NTLMDomain, o.NTLMUsername, o.NTLMHash)
+			}
+			return conn.NTLMBind(o.NTLMDomain, o.NTLMUsername, o.NTLMPassword)
+		})
+	}
+	if o.AnonymousBind {
+		return withBindTimeout(conn, o.BindTimeout, o.RequestTimeout, func() error {
+			return conn.UnauthenticatedBind("")
+		})
+	}
+	if o.BindDN == "" {
+		return nil
+	}
+	return bindWithTimeout(conn, o.BindDN, o.BindPW, o.BindTimeout, o.RequestTimeout)
+}
+
+// errChannelBindingRequired is the code Active Directory reports in the diagnostic message of a
+// bind rejected for lacking channel binding tokens (SEC_E_BAD_BINDINGS), e.g.
+// "80090346: LdapErr: DSID-0C090A1D, comment: AcceptSecurityContext error, data 80090346, v4563".
+const errChannelBindingRequired = "80090346"
+
+// channelBindingRequired reports whether err is a bind Active Directory rejected because it
+// enforces LDAP channel binding and the bind carried no channel binding token.
+func channelBindingRequired(err error) bool {
+	return err != nil && ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) && strings.Contains(err.Error(), errChannelBindingRequired)
+}
+
+// defaultRequestTimeout is the request_timeout of providers not setting it.
+const defaultRequestTimeout = 30 * time.Second
+
+// defaultDialTimeout is the dial_timeout of providers not setting it.
+const defaultDialTimeout = 10 * time.Second
+
+// errBindTimeout is returned by bindWithTimeout when the server did not answer the bind in time.
+var errBindTimeout = errors.New("bind timed out")
+
+// bindWithTimeout binds conn, giving up after timeout (zero means no limit). The request timeout
+// of conn is changed for the bind only and reset to requestTimeout afterwards, so later requests
+// are not affected.
+func bindWithTimeout(conn sessionConn, bindDN string, bindPW string, timeout time.Duration, requestTimeout time.Duration) error {
+	return withBindTimeout(conn, timeout, requestTimeout, func() error {
+		return conn.Bind(bindDN, bindPW)
+	})
+}
+
+// withBindTimeout runs bind, which binds conn, like bindWithTimeout.
+func withBindTimeout(conn sessionConn, timeout time.Duration, requestTimeout time.Duration, bind func() error) error {
+	if timeout <= 0 {
+		return bind()
+	}
+
+	conn.SetTimeout(timeout)
+	defer conn.SetTimeout(requestTimeout)
+
+	start := time.Now()
+	err := bind()
+	if err != nil && ldap.IsErrorWithCode(err, ldap.ErrorNetwork) && time.Since(start) >= timeout {
+		return fmt.Errorf("%w after %s: %s", errBindTimeout, timeout, err)
+	}
+	return err
+}
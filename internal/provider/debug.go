@@ -0,0 +1,142 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// redactedValue replaces the length and value of redacted packet lines.
+const redactedValue = "<redacted>"
+
+// defaultRedactedAttributes are the attributes whose values are always redacted from the wire log.
+var defaultRedactedAttributes = []string{"userPassword", "unicodePwd"}
+
+// redactedCredentials are the descriptions go-ldap gives to the credentials of bind requests.
+var redactedCredentials = map[string]bool{
+	"Password":    true,
+	"Credentials": true,
+	"SASL Cred":   true,
+}
+
+// wireLog writes the debug output of go-ldap, which goes to a single package-level logger, to
+// tflog at TRACE level. It is shared by all provider instances of the process: it logs with the
+// logger of the last one configured, and redacts the attributes registered by any of them.
+var wireLog = &wireLogWriter{ctx: context.Background()}
+
+// setWireLogger installs wireLog as the logger of go-ldap, once per process.
+var setWireLogger sync.Once
+
+// enableWireLog routes the debug output of go-ldap to tflog with the logger of ctx. Debug mode is
+// enabled per connection, see the debug provider argument.
+func enableWireLog(ctx context.Context) {
+	wireLog.mu.Lock()
+	wireLog.ctx = ctx
+	wireLog.mu.Unlock()
+	setWireLogger.Do(func() {
+		ldap.Logger(log.New(wireLog, "", 0))
+	})
+}
+
+// wireLogWriter logs the lines go-ldap writes, with the credentials of binds and the values of
+// sensitive attributes redacted. go-ldap dumps a packet one node per line, as
+// "<description>: (<class>, <type>, <tag>) Len=<length> <value>", indented by depth; attribute
+// values follow the line holding their attribute type.
+type wireLogWriter struct {
+	mu  sync.Mutex
+	ctx context.Context
+
+	// redacted holds the lowercase names of attributes whose values are redacted, in addition to
+	// defaultRedactedAttributes.
+	redacted map[string]bool
+	// redacting is set while the values of a redacted attribute are written.
+	redacting bool
+}
+
+// redactAttributes redacts the values of the attributes names from the wire log from now on, e.g.
+// those of attributes_wo.
+func (w *wireLogWriter) redactAttributes(names []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.redacted == nil {
+		w.redacted = map[string]bool{}
+	}
+	for _, name := range names {
+		w.redacted[strings.ToLower(attributeType(name))] = true
+	}
+}
+
+// redactWriteOnlyAttributes redacts the values of the attributes of attributesWO, the
+// attributes_wo of an entry, from the wire log.
+func redactWriteOnlyAttributes(attributesWO types.Map) {
+	if attributesWO.IsNull() || attributesWO.IsUnknown() {
+		return
+	}
+
+	names := make([]string, 0, len(attributesWO.Elements()))
+	for name := range attributesWO.Elements() {
+		names = append(names, name)
+	}
+	wireLog.redactAttributes(names)
+}
+
+func (w *wireLogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		tflog.Trace(w.ctx, w.redact(line))
+	}
+	return len(p), nil
+}
+
+// redact returns line with the value it holds replaced if it is a credential or a value of a
+// redacted attribute.
+func (w *wireLogWriter) redact(line string) string {
+	description, rest, ok := strings.Cut(strings.TrimLeft(line, " "), ": (")
+	if !ok {
+		return line
+	}
+
+	switch description {
+	case "Type", "Attribute Name":
+		// The value of the line is the attribute type, quoted
+		_, value, _ := strings.Cut(rest, ") Len=")
+		_, name, _ := strings.Cut(value, " ")
+		w.redacting = w.isRedacted(strings.Trim(name, `"`))
+		return line
+	case "AttributeValue", "Attribute Values":
+		return line
+	case "Vals", "Attribute Value":
+		if !w.redacting {
+			return line
+		}
+	default:
+		w.redacting = false
+		if !redactedCredentials[description] {
+			return line
+		}
+	}
+
+	prefix, _, _ := strings.Cut(line, ") Len=")
+	return prefix + ") " + redactedValue
+}
+
+func (w *wireLogWriter) isRedacted(name string) bool {
+	base := strings.ToLower(attributeType(name))
+	for _, redacted := range defaultRedactedAttributes {
+		if base == strings.ToLower(redacted) {
+			return true
+		}
+	}
+	return w.redacted[base]
+}
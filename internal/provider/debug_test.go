@@ -0,0 +1,59 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestWireLogWriter(t *testing.T) {
+	var output bytes.Buffer
+	w := &wireLogWriter{ctx: tflogtest.RootLogger(context.Background(), &output)}
+	w.redactAttributes([]string{"sambaNTPassword"})
+
+	ldap.Logger(log.New(w, "", 0))
+	t.Cleanup(func() { ldap.Logger(log.New(os.Stderr, "", log.LstdFlags)) })
+
+	// The server reads the requests and never answers, so every request times out
+	client, server := net.Pipe()
+	t.Cleanup(func() { server.Close() })
+	go func() { _, _ = io.Copy(io.Discard, server) }()
+	conn := ldap.NewConn(client, false)
+	conn.Debug.Enable(true)
+	conn.SetTimeout(100 * time.Millisecond)
+	conn.Start()
+	t.Cleanup(func() { conn.Close() })
+
+	_ = conn.Bind("cn=admin,dc=example,dc=com", "bind-secret")
+
+	modifyReq := ldap.NewModifyRequest("uid=jdoe,dc=example,dc=com", nil)
+	modifyReq.Replace("mail", []string{"jdoe@example.com"})
+	modifyReq.Replace("userPassword;x-test", []string{"user-secret"})
+	modifyReq.Replace("SAMBANTPASSWORD", []string{"wo-secret"})
+	modifyReq.Replace("description", []string{"after the secrets"})
+	_ = conn.Modify(modifyReq)
+
+	logged := output.String()
+	for _, visible := range []string{"cn=admin,dc=example,dc=com", "jdoe@example.com", "after the secrets", "redacted"} {
+		if !strings.Contains(logged, visible) {
+			t.Errorf("expected %q in the wire log:\n%s", visible, logged)
+		}
+	}
+	for _, secret := range []string{"bind-secret", "user-secret", "wo-secret"} {
+		if strings.Contains(logged, secret) {
+			t.Errorf("expected %q to be redacted from the wire log:\n%s", secret, logged)
+		}
+	}
+}
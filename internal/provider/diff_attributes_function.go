@@ -0,0 +1,159 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &DiffAttributesFunction{}
+
+func NewDiffAttributesFunction() function.Function {
+	return &DiffAttributesFunction{}
+}
+
+// DiffAttributesFunction compares two attribute maps the way ldap_entry updates an entry.
+type DiffAttributesFunction struct{}
+
+// attributeDiff is the result of diff_attributes.
+type attributeDiff struct {
+	Added   map[string][]string           `tfsdk:"added"`   // Attributes only in new, with their values
+	Removed map[string][]string           `tfsdk:"removed"` // Attributes only in old, with their values
+	Changed map[string]attributeValueDiff `tfsdk:"changed"` // Attributes in both with different value sets
+}
+
+// attributeValueDiff lists the values of one attribute added and removed between old and new.
+type attributeValueDiff struct {
+	Added   []string `tfsdk:"added"`
+	Removed []string `tfsdk:"removed"`
+}
+
+var attributeValuesType = types.ListType{ElemType: types.StringType}
+
+func (f *DiffAttributesFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "diff_attributes"
+}
+
+func (f *DiffAttributesFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Compare two attribute maps",
+		MarkdownDescription: "Compares two `ldap_entry`-style attribute maps and returns what an update from `old` to `new` changes, as an object with three maps keyed by attribute name: " +
+			"`added` holds attributes only in `new` and `removed` attributes only in `old`, each with all their values; " +
+			"`changed` holds attributes in both whose values differ, as objects with the `added` and `removed` values. " +
+			"Values are compared as sets, so order and duplicates do not matter, and an attribute with an empty list counts as absent. " +
+			"Attribute names differing only in case name the same attribute and are reported under the spelling used in `new`, as with the provider's default `case_insensitive_attribute_names = true`.",
+		Parameters: []function.Parameter{
+			function.MapParameter{
+				Name:                "old",
+				MarkdownDescription: "Current attributes. `null` is treated as an empty map.",
+				ElementType:         attributeValuesType,
+				AllowNullValue:      true,
+			},
+			function.MapParameter{
+				Name:                "new",
+				MarkdownDescription: "Desired attributes. `null` is treated as an empty map.",
+				ElementType:         attributeValuesType,
+				AllowNullValue:      true,
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: map[string]attr.Type{
+				"added":   types.MapType{ElemType: attributeValuesType},
+				"removed": types.MapType{ElemType: attributeValuesType},
+				"changed": types.MapType{ElemType: types.ObjectType{AttrTypes: map[string]attr.Type{
+					"added":   attributeValuesType,
+					"removed": attributeValuesType,
+				}}},
+			},
+		},
+	}
+}
+
+func (f *DiffAttributesFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var oldMap, newMap types.Map
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &oldMap, &newMap))
+	if resp.Error != nil {
+		return
+	}
+
+	oldAttrs := make(map[string][]string)
+	if !oldMap.IsNull() {
+		if diags := oldMap.ElementsAs(ctx, &oldAttrs, false); diags.HasError() {
+			resp.Error = function.FuncErrorFromDiags(ctx, diags)
+			return
+		}
+	}
+
+	newAttrs := make(map[string][]string)
+	if !newMap.IsNull() {
+		if diags := newMap.ElementsAs(ctx, &newAttrs, false); diags.HasError() {
+			resp.Error = function.FuncErrorFromDiags(ctx, diags)
+			return
+		}
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, diffAttributes(oldAttrs, newAttrs)))
+}
+
+// diffAttributes compares old and new with the set semantics of ldap_entry updates. old is
+// modified: its attribute names are aligned to the spelling used in new.
+func diffAttributes(oldAttrs map[string][]string, newAttrs map[string][]string) attributeDiff {
+	diff := attributeDiff{
+		Added:   make(map[string][]string),
+		Removed: make(map[string][]string),
+		Changed: make(map[string]attributeValueDiff),
+	}
+
+	alignAttributeNames(oldAttrs, newAttrs)
+
+	for name, newValues := range newAttrs {
+		oldValues := oldAttrs[name]
+		switch {
+		case len(newValues) == 0 && len(oldValues) == 0:
+		case len(oldValues) == 0:
+			diff.Added[name] = uniqueSortedValues(newValues)
+		case len(newValues) == 0:
+			diff.Removed[name] = uniqueSortedValues(oldValues)
+		case !stringSetsEqual(oldValues, newValues):
+			diff.Changed[name] = attributeValueDiff{
+				Added:   uniqueSortedValues(valuesNotIn(newValues, oldValues)),
+				Removed: uniqueSortedValues(valuesNotIn(oldValues, newValues)),
+			}
+		}
+	}
+
+	for name, oldValues := range oldAttrs {
+		if _, exists := newAttrs[name]; !exists && len(oldValues) > 0 {
+			diff.Removed[name] = uniqueSortedValues(oldValues)
+		}
+	}
+
+	return diff
+}
+
+// stringSetsEqual reports whether a and b contain the same values, ignoring order and duplicates.
+func stringSetsEqual(a []string, b []string) bool {
+	return stringSlicesEqual(uniqueSortedValues(a), uniqueSortedValues(b))
+}
+
+// uniqueSortedValues returns a sorted copy of values without duplicates, never nil.
+func uniqueSortedValues(values []string) []string {
+	result := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
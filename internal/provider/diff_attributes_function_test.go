@@ -0,0 +1,145 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDiffAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      map[string][]string
+		new      map[string][]string
+		expected attributeDiff
+	}{
+		{
+			name: "no changes",
+			old:  map[string][]string{"cn": {"John"}, "mail": {"a@example.com", "b@example.com"}},
+			new:  map[string][]string{"cn": {"John"}, "mail": {"b@example.com", "a@example.com"}},
+			expected: attributeDiff{
+				Added:   map[string][]string{},
+				Removed: map[string][]string{},
+				Changed: map[string]attributeValueDiff{},
+			},
+		},
+		{
+			name: "attribute added",
+			old:  map[string][]string{"cn": {"John"}},
+			new:  map[string][]string{"cn": {"John"}, "mail": {"john@example.com"}},
+			expected: attributeDiff{
+				Added:   map[string][]string{"mail": {"john@example.com"}},
+				Removed: map[string][]string{},
+				Changed: map[string]attributeValueDiff{},
+			},
+		},
+		{
+			name: "attribute removed",
+			old:  map[string][]string{"cn": {"John"}, "description": {"old"}},
+			new:  map[string][]string{"cn": {"John"}},
+			expected: attributeDiff{
+				Added:   map[string][]string{},
+				Removed: map[string][]string{"description": {"old"}},
+				Changed: map[string]attributeValueDiff{},
+			},
+		},
+		{
+			name: "attribute emptied",
+			old:  map[string][]string{"description": {"old"}},
+			new:  map[string][]string{"description": {}},
+			expected: attributeDiff{
+				Added:   map[string][]string{},
+				Removed: map[string][]string{"description": {"old"}},
+				Changed: map[string]attributeValueDiff{},
+			},
+		},
+		{
+			name: "empty attribute absent before",
+			old:  map[string][]string{},
+			new:  map[string][]string{"description": {}},
+			expected: attributeDiff{
+				Added:   map[string][]string{},
+				Removed: map[string][]string{},
+				Changed: map[string]attributeValueDiff{},
+			},
+		},
+		{
+			name: "values changed",
+			old:  map[string][]string{"member": {"uid=a", "uid=b", "uid=c"}},
+			new:  map[string][]string{"member": {"uid=d", "uid=c", "uid=a"}},
+			expected: attributeDiff{
+				Added:   map[string][]string{},
+				Removed: map[string][]string{},
+				Changed: map[string]attributeValueDiff{"member": {Added: []string{"uid=d"}, Removed: []string{"uid=b"}}},
+			},
+		},
+		{
+			name: "single value replaced",
+			old:  map[string][]string{"sn": {"Doe"}},
+			new:  map[string][]string{"sn": {"Smith"}},
+			expected: attributeDiff{
+				Added:   map[string][]string{},
+				Removed: map[string][]string{},
+				Changed: map[string]attributeValueDiff{"sn": {Added: []string{"Smith"}, Removed: []string{"Doe"}}},
+			},
+		},
+		{
+			name: "attribute name case ignored",
+			old:  map[string][]string{"objectClass": {"person"}},
+			new:  map[string][]string{"objectclass": {"person", "inetOrgPerson"}},
+			expected: attributeDiff{
+				Added:   map[string][]string{},
+				Removed: map[string][]string{},
+				Changed: map[string]attributeValueDiff{"objectclass": {Added: []string{"inetOrgPerson"}, Removed: []string{}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffAttributes(tt.old, tt.new); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("diffAttributes() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDiffAttributesFunction_Run(t *testing.T) {
+	valuesType := types.ListType{ElemType: types.StringType}
+	newMap := types.MapValueMust(valuesType, map[string]attr.Value{
+		"cn": types.ListValueMust(types.StringType, []attr.Value{types.StringValue("John")}),
+	})
+
+	req := function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{types.MapNull(valuesType), newMap}),
+	}
+	definition := &function.DefinitionResponse{}
+	NewDiffAttributesFunction().Definition(context.Background(), function.DefinitionRequest{}, definition)
+	resp := &function.RunResponse{
+		Result: function.NewResultData(types.ObjectUnknown(definition.Definition.Return.GetType().(types.ObjectType).AttrTypes)),
+	}
+
+	NewDiffAttributesFunction().Run(context.Background(), req, resp)
+
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %s", resp.Error)
+	}
+
+	result, ok := resp.Result.Value().(types.Object)
+	if !ok {
+		t.Fatalf("result = %T, want object", resp.Result.Value())
+	}
+	if got := result.Attributes()["added"]; !got.Equal(types.MapValueMust(valuesType, newMap.Elements())) {
+		t.Errorf("added = %s, want %s", got, newMap)
+	}
+	if got := result.Attributes()["removed"].(types.Map); len(got.Elements()) != 0 {
+		t.Errorf("removed = %s, want empty map", got)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &EncodeUnicodePwdFunction{}
+
+func NewEncodeUnicodePwdFunction() function.Function {
+	return &EncodeUnicodePwdFunction{}
+}
+
+// EncodeUnicodePwdFunction encodes a password as an Active Directory unicodePwd value.
+type EncodeUnicodePwdFunction struct{}
+
+func (f *EncodeUnicodePwdFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "encode_unicode_pwd"
+}
+
+func (f *EncodeUnicodePwdFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Encode a password as an Active Directory unicodePwd value",
+		MarkdownDescription: "Encodes `password` the way `ldap_entry` does for `unicodePwd`: enclosed in double quotes, encoded as UTF-16LE and returned base64 encoded, " +
+			"e.g. for an LDIF line such as `unicodePwd:: ${provider::ldap::encode_unicode_pwd(var.password)}`. " +
+			"Do not pass the result to `ldap_entry`, which encodes `unicodePwd` itself.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "password",
+				MarkdownDescription: "Password in clear text.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *EncodeUnicodePwdFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var password string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &password))
+	if resp.Error != nil {
+		return
+	}
+
+	encoded, err := encodeUnicodePwd(password)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("unable to encode password: %s", err))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, base64.StdEncoding.EncodeToString([]byte(encoded))))
+}
@@ -0,0 +1,83 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestEncodeUnicodePwdFunction_Run(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		expected []byte
+	}{
+		{
+			name:     "ascii",
+			password: "Ab1",
+			expected: []byte{'"', 0, 'A', 0, 'b', 0, '1', 0, '"', 0},
+		},
+		{
+			name:     "empty",
+			password: "",
+			expected: []byte{'"', 0, '"', 0},
+		},
+		{
+			name:     "quotes",
+			password: `a"b`,
+			// Quotes inside the password are not escaped
+			expected: []byte{'"', 0, 'a', 0, '"', 0, 'b', 0, '"', 0},
+		},
+		{
+			name:     "unicode",
+			password: "пä",
+			expected: []byte{'"', 0, 0x3f, 0x04, 0xe4, 0x00, '"', 0},
+		},
+		{
+			name:     "emoji",
+			password: "🔒",
+			// U+1F512 is the surrogate pair D83D DD12
+			expected: []byte{'"', 0, 0x3d, 0xd8, 0x12, 0xdd, '"', 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue(tt.password),
+				}),
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.StringUnknown()),
+			}
+
+			NewEncodeUnicodePwdFunction().Run(context.Background(), req, resp)
+
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+
+			// Must match what ldap_entry sends for unicodePwd
+			encoded, err := encodeUnicodePwd(tt.password)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			want := base64.StdEncoding.EncodeToString([]byte(encoded))
+			if !resp.Result.Value().Equal(types.StringValue(want)) {
+				t.Errorf("result = %s, want %q", resp.Result.Value(), want)
+			}
+
+			if want != base64.StdEncoding.EncodeToString(tt.expected) {
+				t.Errorf("encoded %q = %q, want %q", tt.password, want, base64.StdEncoding.EncodeToString(tt.expected))
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &EscapeFilterAssertionFunction{}
+
+func NewEscapeFilterAssertionFunction() function.Function {
+	return &EscapeFilterAssertionFunction{}
+}
+
+// EscapeFilterAssertionFunction escapes a string for use as a literal inside a filter assertion value.
+type EscapeFilterAssertionFunction struct{}
+
+func (f *EscapeFilterAssertionFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "escape_filter_assertion"
+}
+
+func (f *EscapeFilterAssertionFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Escape a string for use as a literal in a search filter",
+		MarkdownDescription: "Escapes the RFC 4515 special characters `*`, `(`, `)`, `\\` and NUL in `value` as `\\2a`, `\\28`, `\\29`, `\\5c` and `\\00` (bytes outside ASCII are hex-escaped too), " +
+			"so untrusted input always matches literally and can never add wildcards or filter components. " +
+			"For a substring match keep the wildcards outside the call: `\"(cn=*${provider::ldap::escape_filter_assertion(var.name)}*)\"` " +
+			"matches entries whose `cn` contains `var.name`, even when it includes `*` itself. " +
+			"Wildcards placed inside `value` are escaped and therefore matched as a literal `*`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "value",
+				MarkdownDescription: "String to match literally.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *EscapeFilterAssertionFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, ldap.EscapeFilter(value)))
+}
@@ -0,0 +1,59 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestEscapeFilterAssertionFunction_Run(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "plain", value: "jdoe", expected: "jdoe"},
+		{name: "empty", value: "", expected: ""},
+		{name: "asterisk", value: "a*b", expected: `a\2ab`},
+		{name: "backslash", value: `DOMAIN\jdoe`, expected: `DOMAIN\5cjdoe`},
+		{name: "parentheses", value: "x)(uid=*", expected: `x\29\28uid=\2a`},
+		{name: "nul", value: "a\x00b", expected: `a\00b`},
+		{name: "non-ascii", value: "café", expected: `caf\c3\a9`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue(tt.value),
+				}),
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.StringUnknown()),
+			}
+
+			NewEscapeFilterAssertionFunction().Run(context.Background(), req, resp)
+
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+
+			if !resp.Result.Value().Equal(types.StringValue(tt.expected)) {
+				t.Errorf("result = %s, want %q", resp.Result.Value(), tt.expected)
+			}
+
+			// The escaped value must form a valid substring filter that keeps the outer wildcards
+			filter := "(cn=*" + tt.expected + "*)"
+			if _, err := ldap.CompileFilter(filter); err != nil {
+				t.Errorf("escaped value produced an invalid filter %q: %s", filter, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// oidStartTLS is the OID of the StartTLS extended operation, see RFC 4511 section 4.14.
+const oidStartTLS = "1.3.6.1.4.1.1466.20037"
+
+// unsupportedExtendedOperationError reports that the server does not support an extended
+// operation. RFC 4511 section 4.12 has servers answer extended requests they do not recognize
+// with protocolError.
+type unsupportedExtendedOperationError struct {
+	// Name is the name of the operation, e.g. "StartTLS".
+	Name string
+	OID  string
+	Err  error
+}
+
+func (e *unsupportedExtendedOperationError) Error() string {
+	return fmt.Sprintf("the server does not support the %s extended operation (%s): %s", e.Name, e.OID, e.Err)
+}
+
+func (e *unsupportedExtendedOperationError) Unwrap() error {
+	return e.Err
+}
+
+// extendedOperationError returns err, the result of the extended operation name with oid, as an
+// *unsupportedExtendedOperationError if the server answered with protocolError. Every extended
+// operation passes its errors through here, so that unsupported operations are reported uniformly.
+func extendedOperationError(name string, oid string, err error) error {
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultProtocolError) {
+		return &unsupportedExtendedOperationError{Name: name, OID: oid, Err: err}
+	}
+	return err
+}
+
+// unsupportedExtendedOperationDiagnostic returns an error diagnostic stating which extended
+// operation the server does not support, if err wraps an *unsupportedExtendedOperationError.
+func unsupportedExtendedOperationDiagnostic(err error) (diag.Diagnostic, bool) {
+	var unsupported *unsupportedExtendedOperationError
+	if !errors.As(err, &unsupported) {
+		return nil, false
+	}
+
+	return diag.NewErrorDiagnostic(
+		"LDAP extended operation not supported",
+		fmt.Sprintf("The LDAP server does not support the %s extended operation (OID %s). "+
+			"The extended operations a server supports are listed in the supportedExtension attribute of its Root DSE, see the ldap_root_dse data source.\n\n"+
+			"Server response: %s", unsupported.Name, unsupported.OID, unsupported.Err),
+	), true
+}
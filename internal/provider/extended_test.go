@@ -0,0 +1,65 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestExtendedOperationError(t *testing.T) {
+	protocolError := ldap.NewError(ldap.LDAPResultProtocolError, errors.New("unsupported extended operation"))
+	otherError := ldap.NewError(ldap.LDAPResultUnwillingToPerform, errors.New("not now"))
+
+	err := extendedOperationError("Start Transaction", oidStartTransaction, protocolError)
+	var unsupported *unsupportedExtendedOperationError
+	if !errors.As(err, &unsupported) {
+		t.Fatalf("expected protocolError to be reported as unsupported, got %v", err)
+	}
+	if unsupported.Name != "Start Transaction" || unsupported.OID != oidStartTransaction {
+		t.Errorf("unsupported operation = %s (%s), want Start Transaction (%s)", unsupported.Name, unsupported.OID, oidStartTransaction)
+	}
+	if !ldap.IsErrorWithCode(err, ldap.LDAPResultProtocolError) {
+		t.Error("expected the server error to stay accessible")
+	}
+	if !strings.Contains(err.Error(), oidStartTransaction) {
+		t.Errorf("expected error %q to name the OID", err)
+	}
+
+	if err := extendedOperationError("Start Transaction", oidStartTransaction, otherError); err != otherError {
+		t.Errorf("expected other errors to be returned unchanged, got %v", err)
+	}
+	if err := extendedOperationError("Start Transaction", oidStartTransaction, nil); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestUnsupportedExtendedOperationDiagnostic(t *testing.T) {
+	protocolError := ldap.NewError(ldap.LDAPResultProtocolError, errors.New("unsupported extended operation"))
+	wrapped := fmt.Errorf("StartTLS failed: %w", extendedOperationError("StartTLS", oidStartTLS, protocolError))
+
+	d, ok := unsupportedExtendedOperationDiagnostic(wrapped)
+	if !ok {
+		t.Fatal("expected a diagnostic for an unsupported extended operation")
+	}
+	if d.Summary() != "LDAP extended operation not supported" {
+		t.Errorf("summary = %q", d.Summary())
+	}
+	for _, expected := range []string{"StartTLS", oidStartTLS, "unsupported extended operation"} {
+		if !strings.Contains(d.Detail(), expected) {
+			t.Errorf("detail %q does not contain %q", d.Detail(), expected)
+		}
+	}
+
+	if _, ok := unsupportedExtendedOperationDiagnostic(errors.New("connection refused")); ok {
+		t.Error("expected no diagnostic for other errors")
+	}
+	if _, ok := unsupportedExtendedOperationDiagnostic(nil); ok {
+		t.Error("expected no diagnostic without error")
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// fileTimeNever is the value Active Directory uses for timestamps that never occur, e.g. in
+// msDS-UserPasswordExpiryTimeComputed for passwords that do not expire. It is returned as is.
+const fileTimeNever = "never"
+
+// fileTimeEpoch is the start of the Windows FILETIME epoch.
+var fileTimeEpoch = time.Date(1601, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// formatFileTime converts a Windows FILETIME, the number of 100-nanosecond intervals since
+// 1601-01-01 UTC written as a decimal integer (as in accountExpires, pwdLastSet or
+// msDS-UserPasswordExpiryTimeComputed), to an RFC 3339 timestamp in UTC. The largest 64-bit value
+// is returned as "never".
+func formatFileTime(value string) (string, error) {
+	ticks, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("FILETIME %q is not a 64-bit integer", value)
+	}
+	if ticks == math.MaxInt64 {
+		return fileTimeNever, nil
+	}
+	if ticks < 0 {
+		return "", fmt.Errorf("FILETIME %d is negative", ticks)
+	}
+
+	// Add whole days separately, as time.Duration only spans about 292 years
+	seconds := ticks / 10000000
+	nanoseconds := (ticks % 10000000) * 100
+	t := fileTimeEpoch.AddDate(0, 0, int(seconds/86400)).
+		Add(time.Duration(seconds%86400)*time.Second + time.Duration(nanoseconds))
+	return t.Format(time.RFC3339Nano), nil
+}
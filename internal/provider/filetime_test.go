@@ -0,0 +1,85 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestFormatFileTime(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expected    string
+		expectError bool
+	}{
+		{name: "epoch", value: "0", expected: "1601-01-01T00:00:00Z"},
+		{name: "unix epoch", value: "116444736000000000", expected: "1970-01-01T00:00:00Z"},
+		{name: "password expiry", value: "133853040000000000", expected: "2025-03-01T12:00:00Z"},
+		{name: "sub-second", value: "116444736000000001", expected: "1970-01-01T00:00:00.0000001Z"},
+		{name: "never", value: "9223372036854775807", expected: "never"},
+		{name: "one before never", value: "9223372036854775806", expected: "30828-09-14T02:48:05.4775806Z"},
+		{name: "negative", value: "-1", expectError: true},
+		{name: "not a number", value: "20250301120000.0Z", expectError: true},
+		{name: "overflow", value: "9223372036854775808", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := formatFileTime(tt.value)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("formatFileTime(%q) expected error, got %q", tt.value, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("formatFileTime(%q) unexpected error: %s", tt.value, err)
+			}
+			if result != tt.expected {
+				t.Errorf("formatFileTime(%q) = %q, want %q", tt.value, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMarshalLdapResults_FileTimeAttributes(t *testing.T) {
+	sr := &ldap.SearchResult{Entries: []*ldap.Entry{
+		ldap.NewEntry("CN=Jane Doe,CN=Users,DC=example,DC=com", map[string][]string{
+			"msDS-UserPasswordExpiryTimeComputed": {"9223372036854775807"},
+			"pwdLastSet":                          {"133853040000000000"},
+			"uSNChanged":                          {"12345"},
+		}),
+	}}
+
+	requested := []string{"msDS-UserPasswordExpiryTimeComputed", "pwdLastSet", "uSNChanged"}
+	results, err := MarshalLdapResults(context.Background(), sr, requested, MarshalOptions{
+		FileTimeAttributes: []string{"msds-userpasswordexpirytimecomputed", "pwdLastSet"},
+	})
+	if err != nil {
+		t.Fatalf("MarshalLdapResults unexpected error: %v", err)
+	}
+
+	attributes := make(map[string][]string)
+	if diags := results[0].Attributes.ElementsAs(context.Background(), &attributes, false); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	expected := map[string][]string{
+		"msDS-UserPasswordExpiryTimeComputed": {"never"},
+		"pwdLastSet":                          {"2025-03-01T12:00:00Z"},
+		"uSNChanged":                          {"12345"},
+	}
+	if !reflect.DeepEqual(attributes, expected) {
+		t.Errorf("attributes = %v, want %v", attributes, expected)
+	}
+
+	sr.Entries[0].Attributes[0].Values = []string{"not a filetime"}
+	if _, err := MarshalLdapResults(context.Background(), sr, requested, MarshalOptions{FileTimeAttributes: []string{"msDS-UserPasswordExpiryTimeComputed", "pwdLastSet"}}); err == nil {
+		t.Error("expected error for a malformed FILETIME")
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &LdapEntryAttributesDataSource{}
+
+func NewLdapEntryAttributesDataSource() datasource.DataSource {
+	return &LdapEntryAttributesDataSource{}
+}
+
+// LdapEntryAttributesDataSource defines the data source implementation.
+type LdapEntryAttributesDataSource struct {
+	conn *LdapClient
+}
+
+// LdapEntryAttributesDataSourceModel describes the data source data model.
+type LdapEntryAttributesDataSourceModel struct {
+	DN                    types.String `tfsdk:"dn"`
+	UserAttributes        types.List   `tfsdk:"user_attributes"`
+	OperationalAttributes types.List   `tfsdk:"operational_attributes"`
+}
+
+func (d *LdapEntryAttributesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_entry_attributes"
+}
+
+func (d *LdapEntryAttributesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the attributes present on an entry, split into user attributes, which can be managed with `ldap_entry`, " +
+			"and operational attributes maintained by the server, such as `createTimestamp` or `entryUUID`, which cannot. " +
+			"The entry is read with `*` and `+`, and each attribute is classified by the server schema: " +
+			"attribute types with a `USAGE` other than `userApplications` or marked `NO-USER-MODIFICATION` are operational. " +
+			"Attributes the schema does not define are classified by whether the server returns them for `*`. " +
+			"Only attributes the bound identity may read are listed.",
+
+		Attributes: map[string]schema.Attribute{
+			"dn": schema.StringAttribute{
+				MarkdownDescription: "The distinguished name (DN) of the entry. Relative to the provider `base_dn` if it does not already end with it.",
+				Required:            true,
+			},
+			"user_attributes": schema.ListAttribute{
+				MarkdownDescription: "Names of the user attributes of the entry, sorted, as returned by the server (including options such as `;lang-en`).",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"operational_attributes": schema.ListAttribute{
+				MarkdownDescription: "Names of the operational attributes of the entry, sorted, as returned by the server.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+		},
+	}
+}
+
+func (d *LdapEntryAttributesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.conn = GetLdapConnection(req.ProviderData, &resp.Diagnostics, "Data Source")
+}
+
+func (d *LdapEntryAttributesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data LdapEntryAttributesDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dn := d.conn.ResolveDN(data.DN.ValueString())
+
+	serverSchema, err := d.conn.Schema(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read LDAP schema", err.Error())
+		return
+	}
+
+	user, operational, err := classifyEntryAttributes(d.conn, dn, serverSchema)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to read LDAP entry attributes",
+			fmt.Sprintf("Unable to read the attributes of %s: %s", dn, err),
+		)
+		return
+	}
+
+	userList, diags := types.ListValueFrom(ctx, types.StringType, user)
+	resp.Diagnostics.Append(diags...)
+	operationalList, diags := types.ListValueFrom(ctx, types.StringType, operational)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.UserAttributes = userList
+	data.OperationalAttributes = operationalList
+
+	tflog.Trace(ctx, fmt.Sprintf("read %d user and %d operational attribute names of %s", len(user), len(operational), dn))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// classifyEntryAttributes returns the sorted names of the user and operational attributes of the
+// entry at dn. Attribute types defined in serverSchema are classified by it; the others by whether
+// the server returns them for "*", which requires a second search.
+func classifyEntryAttributes(conn LdapSearcher, dn string, serverSchema *LdapSchema) (user []string, operational []string, err error) {
+	sr, err := LdapSearch(conn, dn, "base", "(objectClass=*)", []string{"*", "+"}, LdapSearchOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(sr.Entries) == 0 {
+		return nil, nil, fmt.Errorf("entry %s not found", dn)
+	}
+
+	user, operational = []string{}, []string{}
+	var unknown []string
+	for _, attr := range sr.Entries[0].Attributes {
+		at := serverSchema.AttributeType(attr.Name)
+		switch {
+		case at == nil:
+			unknown = append(unknown, attr.Name)
+		case at.IsOperational():
+			operational = append(operational, attr.Name)
+		default:
+			user = append(user, attr.Name)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sr, err := LdapSearch(conn, dn, "base", "(objectClass=*)", []string{"*"}, LdapSearchOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		returnedForAll := make(map[string]bool)
+		if len(sr.Entries) > 0 {
+			for _, attr := range sr.Entries[0].Attributes {
+				returnedForAll[strings.ToLower(attr.Name)] = true
+			}
+		}
+		for _, name := range unknown {
+			if returnedForAll[strings.ToLower(name)] {
+				user = append(user, name)
+			} else {
+				operational = append(operational, name)
+			}
+		}
+	}
+
+	sort.Strings(user)
+	sort.Strings(operational)
+	return user, operational, nil
+}
@@ -0,0 +1,164 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+// attributeSelectorSearcher serves one entry, returning its user attributes for "*" and its
+// operational attributes for "+".
+type attributeSelectorSearcher struct {
+	user        map[string][]string
+	operational map[string][]string
+	searches    int
+}
+
+func (s *attributeSelectorSearcher) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	s.searches++
+
+	attributes := make(map[string][]string)
+	for _, selector := range req.Attributes {
+		source := s.user
+		if selector == "+" {
+			source = s.operational
+		}
+		for name, values := range source {
+			attributes[name] = values
+		}
+	}
+	return &ldap.SearchResult{Entries: []*ldap.Entry{ldap.NewEntry(req.BaseDN, attributes)}}, nil
+}
+
+func TestClassifyEntryAttributes(t *testing.T) {
+	serverSchema, _ := ParseLdapSchema([]string{
+		"( 2.5.4.3 NAME ( 'cn' 'commonName' ) SUP name )",
+		"( 2.5.4.13 NAME 'description' SYNTAX 1.3.6.1.4.1.1466.115.121.1.15 )",
+		"( 2.5.4.0 NAME 'objectClass' SYNTAX 1.3.6.1.4.1.1466.115.121.1.38 )",
+		"( 2.5.18.1 NAME 'createTimestamp' SINGLE-VALUE NO-USER-MODIFICATION USAGE directoryOperation )",
+		"( 1.3.6.1.1.16.4 NAME 'entryUUID' SINGLE-VALUE NO-USER-MODIFICATION USAGE directoryOperation )",
+		"( 1.3.6.1.4.1.4203.666.1.7 NAME 'entryCSN' SINGLE-VALUE NO-USER-MODIFICATION USAGE dSAOperation )",
+		"( 2.5.18.10 NAME 'subschemaSubentry' SINGLE-VALUE NO-USER-MODIFICATION USAGE directoryOperation )",
+		"( 1.2.840.113556.1.2.102 NAME 'memberOf' NO-USER-MODIFICATION )",
+	})
+
+	t.Run("defined in schema", func(t *testing.T) {
+		searcher := &attributeSelectorSearcher{
+			user: map[string][]string{
+				"objectClass":         {"person"},
+				"cn":                  {"John"},
+				"description;lang-en": {"Engineer"},
+			},
+			operational: map[string][]string{
+				"createTimestamp": {"20250301120000Z"},
+				"entryUUID":       {"a7b3c1d2-0000-0000-0000-000000000000"},
+				"entryCSN":        {"20250301120000.000000Z#000000#000#000000"},
+				"memberOf":        {"cn=developers,dc=example,dc=com"},
+			},
+		}
+
+		user, operational, err := classifyEntryAttributes(searcher, "cn=John,dc=example,dc=com", serverSchema)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if expected := []string{"cn", "description;lang-en", "objectClass"}; !reflect.DeepEqual(user, expected) {
+			t.Errorf("user = %v, want %v", user, expected)
+		}
+		if expected := []string{"createTimestamp", "entryCSN", "entryUUID", "memberOf"}; !reflect.DeepEqual(operational, expected) {
+			t.Errorf("operational = %v, want %v", operational, expected)
+		}
+		if searcher.searches != 1 {
+			t.Errorf("expected one search when the schema defines every attribute, got %d", searcher.searches)
+		}
+	})
+
+	t.Run("unknown to schema", func(t *testing.T) {
+		searcher := &attributeSelectorSearcher{
+			user:        map[string][]string{"cn": {"John"}, "x-customAttribute": {"value"}},
+			operational: map[string][]string{"x-serverCounter": {"42"}},
+		}
+
+		user, operational, err := classifyEntryAttributes(searcher, "cn=John,dc=example,dc=com", serverSchema)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if expected := []string{"cn", "x-customAttribute"}; !reflect.DeepEqual(user, expected) {
+			t.Errorf("user = %v, want %v", user, expected)
+		}
+		if expected := []string{"x-serverCounter"}; !reflect.DeepEqual(operational, expected) {
+			t.Errorf("operational = %v, want %v", operational, expected)
+		}
+		if searcher.searches != 2 {
+			t.Errorf("expected a second search for attributes unknown to the schema, got %d", searcher.searches)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, _, err := classifyEntryAttributes(&staticSearcher{result: &ldap.SearchResult{}}, "cn=missing,dc=example,dc=com", serverSchema)
+		if err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Errorf("expected a not found error, got %v", err)
+		}
+	})
+}
+
+func TestAccLdapEntryAttributesDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapEntryAttributesDataSourceConfig(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.ldap_entry_attributes.test",
+						tfjsonpath.New("user_attributes"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact("cn"),
+							knownvalue.StringExact("objectClass"),
+							knownvalue.StringExact("sn"),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.ldap_entry_attributes.test",
+						tfjsonpath.New("operational_attributes"),
+						knownvalue.NotNull(),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccLdapEntryAttributesDataSourceConfig() string {
+	return `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+resource "ldap_entry" "test" {
+  dn = "cn=entry-attributes,dc=example,dc=com"
+  attributes = {
+    objectClass = ["person"]
+    cn = ["entry-attributes"]
+    sn = ["User"]
+  }
+}
+
+data "ldap_entry_attributes" "test" {
+  dn = ldap_entry.test.dn
+}
+`
+}
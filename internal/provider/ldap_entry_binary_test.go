@@ -0,0 +1,138 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+// testAccUserCertificateDER is a self-signed P-256 certificate for CN=testuser, base64-encoded DER.
+const testAccUserCertificateDER = "MIIBfTCCASOgAwIBAgIUMUtaAZZNYb0wEAMZvuhLy0CWMrIwCgYIKoZIzj0EAwIwEzERMA8GA1UEAwwIdGVzdHVzZXIwIBcNMjYxMDE3MDcxNzEwWhgPMjEyNjA5MjMwNzE3MTBaMBMxETAPBgNVBAMMCHRlc3R1c2VyMFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEeWPC5RzyozgCqnyV8axn5ctIv6uTt4IClQ07XiLi/8pZHVchpBbLs50peOXw/YgvrFc86wEdbEuvhSskzLXMGaNTMFEwHQYDVR0OBBYEFJNgW6hHhwdYpy0xFi82l7Mr2zilMB8GA1UdIwQYMBaAFJNgW6hHhwdYpy0xFi82l7Mr2zilMA8GA1UdEwEB/wQFMAMBAf8wCgYIKoZIzj0EAwIDSAAwRQIgbhdEbt8j5g6O/P90a/Wz9VwolhX1Gzlmk8hwyApv5OoCIQCETJtaVDzTnKk+N8LmexlxKvjQzvPqzG0YRTyFvmnPKA=="
+
+func TestAccLdapEntryResource_BinaryUserCertificate(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			// Create the entry and read the certificate back through ldap_search
+			{
+				Config: testAccLdapEntryResourceConfigBinaryCertificate(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"ldap_entry.test_cert",
+						tfjsonpath.New("attributes").AtMapKey("userCertificate;binary").AtSliceIndex(0),
+						knownvalue.StringExact(testAccUserCertificateDER),
+					),
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.test_cert",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("attributes").AtMapKey("userCertificate;binary").AtSliceIndex(0),
+						knownvalue.StringExact(testAccUserCertificateDER),
+					),
+				},
+				Check: testAccCheckLdapAttributeBytes("ldap_entry.test_cert", "userCertificate;binary", testAccUserCertificateDER),
+			},
+			// Refresh and plan again - the base64 representation must not drift
+			{
+				Config:             testAccLdapEntryResourceConfigBinaryCertificate(),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+		},
+	})
+}
+
+func testAccLdapEntryResourceConfigBinaryCertificate() string {
+	return fmt.Sprintf(`
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+resource "ldap_entry" "test_cert" {
+  dn = "uid=certuser,ou=users,dc=example,dc=com"
+  attributes = {
+    objectClass = ["person", "organizationalPerson", "inetOrgPerson"]
+    cn = ["Cert User"]
+    sn = ["User"]
+    uid = ["certuser"]
+    "userCertificate;binary" = [%[1]q]
+  }
+  binary_attributes = ["userCertificate"]
+}
+
+data "ldap_search" "test_cert" {
+  basedn = ldap_entry.test_cert.dn
+  scope = "base"
+  filter = "(objectClass=*)"
+  requested_attributes = ["userCertificate;binary"]
+  binary_attributes = ["userCertificate"]
+}
+`, testAccUserCertificateDER)
+}
+
+// testAccCheckLdapAttributeBytes checks that the raw bytes stored on the server equal the base64-decoded expected value.
+func testAccCheckLdapAttributeBytes(resourceName, attrName, expectedBase64 string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", resourceName)
+		}
+
+		expected, err := base64.StdEncoding.DecodeString(expectedBase64)
+		if err != nil {
+			return fmt.Errorf("invalid expected base64: %w", err)
+		}
+
+		conn, err := ldap.DialURL("ldap://localhost:3389")
+		if err != nil {
+			return fmt.Errorf("failed to connect to LDAP server: %w", err)
+		}
+		defer conn.Close()
+
+		err = conn.Bind("cn=Manager,dc=example,dc=com", "secret")
+		if err != nil {
+			return fmt.Errorf("failed to bind to LDAP server: %w", err)
+		}
+
+		searchReq := ldap.NewSearchRequest(
+			rs.Primary.ID,
+			ldap.ScopeBaseObject,
+			ldap.NeverDerefAliases,
+			0,
+			0,
+			false,
+			"(objectClass=*)",
+			[]string{attrName},
+			nil,
+		)
+
+		result, err := conn.Search(searchReq)
+		if err != nil {
+			return fmt.Errorf("error searching for entry %s: %w", rs.Primary.ID, err)
+		}
+
+		if len(result.Entries) == 0 {
+			return fmt.Errorf("LDAP entry %s not found", rs.Primary.ID)
+		}
+
+		actual := result.Entries[0].GetRawAttributeValue(attrName)
+		if !bytes.Equal(actual, expected) {
+			return fmt.Errorf("attribute %s on %s = %x, want %x", attrName, rs.Primary.ID, actual, expected)
+		}
+
+		return nil
+	}
+}
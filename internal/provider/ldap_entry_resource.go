@@ -5,24 +5,35 @@ package provider
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/text/unicode/norm"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &LdapEntryResource{}
 var _ resource.ResourceWithImportState = &LdapEntryResource{}
+var _ resource.ResourceWithModifyPlan = &LdapEntryResource{}
+var _ resource.ResourceWithConfigValidators = &LdapEntryResource{}
 
 func NewLdapEntryResource() resource.Resource {
 	return &LdapEntryResource{}
@@ -30,19 +41,62 @@ func NewLdapEntryResource() resource.Resource {
 
 // LdapEntryResource defines the resource implementation for managing LDAP entries.
 type LdapEntryResource struct {
-	client *ldap.Conn
+	client *LdapClient
 }
 
 // LdapEntryResourceModel describes the resource data model for LDAP entries.
 // It maps the Terraform schema to Go types for state management.
 type LdapEntryResourceModel struct {
-	DN              types.String `tfsdk:"dn"`                    // Distinguished Name - unique identifier for the LDAP entry
-	Attributes      types.Map    `tfsdk:"attributes"`            // Map of List[String] - regular LDAP attributes stored in state
-	AttributesWO    types.Map    `tfsdk:"attributes_wo"`         // Map of List[String] - write-only sensitive attributes (not stored in state)
-	AttributesWOVer types.Int64  `tfsdk:"attributes_wo_version"` // Version trigger for attributes_wo changes
-	Id              types.String `tfsdk:"id"`                    // Resource identifier (same as DN)
+	DN                    types.String `tfsdk:"dn"`                       // Distinguished Name - unique identifier for the LDAP entry
+	Attributes            types.Map    `tfsdk:"attributes"`               // Map of List[String] - regular LDAP attributes stored in state
+	AttributesWO          types.Map    `tfsdk:"attributes_wo"`            // Map of List[String] - write-only sensitive attributes (not stored in state)
+	AttributesWOVer       types.Int64  `tfsdk:"attributes_wo_version"`    // Version trigger for attributes_wo changes
+	AttributesWOAlways    types.Bool   `tfsdk:"attributes_wo_always"`     // Send attributes_wo on every apply, ignoring the version
+	AttributesWOWrittenAt types.String `tfsdk:"attributes_wo_written_at"` // When attributes_wo were last sent
+	WriteOnceAttributes   types.List   `tfsdk:"write_once_attributes"`    // List[String] - attributes_wo only written on create
+	Id                    types.String `tfsdk:"id"`                       // Resource identifier (same as DN)
+
+	BinaryAttributes types.List                     `tfsdk:"binary_attributes"`  // List[String] - attribute types whose values are base64-encoded in configuration and state
+	ReadConsistency  *LdapEntryReadConsistencyModel `tfsdk:"read_consistency"`   // Optional post-write polling for eventually-consistent directories
+	ForceRecreate    types.String                   `tfsdk:"force_recreate"`     // Arbitrary trigger value; changing it replaces the entry
+	Unlock           types.String                   `tfsdk:"unlock"`             // Arbitrary trigger value; changing it unlocks the AD account
+	ReadDerefAliases types.Bool                     `tfsdk:"read_deref_aliases"` // Dereference an alias DN when reading the entry
+	ReadFilter       types.String                   `tfsdk:"read_filter"`        // Filter of the base-scope search reading the entry
+	MissingAsNull    types.Bool                     `tfsdk:"missing_as_null"`    // Read absent managed attributes as null instead of []
+	SDFlags          types.Int64                    `tfsdk:"sd_flags"`           // Active Directory SD Flags control value sent with reads and writes
+	DeleteOldRDN     types.Bool                     `tfsdk:"delete_old_rdn"`     // Remove the old RDN value when renaming the entry
+	VerifyDestroy    types.Bool                     `tfsdk:"verify_destroy"`     // Confirm with a search that Delete removed the entry
+	MemberBatchSize  types.Int64                    `tfsdk:"member_batch_size"`  // Maximum values of one attribute written per operation
+	MaxValueBytes    types.Int64                    `tfsdk:"max_value_bytes"`    // Largest attribute value accepted, overriding the provider setting
+
+	AuthoritativeAttributes types.List `tfsdk:"authoritative_attributes"` // List[String] - attributes whose drift is detected; null means all
+	ImplicitObjectClasses   types.List `tfsdk:"implicit_object_classes"`  // List[String] - object classes the server adds on its own; null means ["top"]
+	AllowProtectedDelete    types.Bool `tfsdk:"allow_protected_delete"`   // Delete the entry even if it has one of the provider's protected_object_classes
+
+	CreateVisibilityTimeout types.String `tfsdk:"create_visibility_timeout"` // How long to wait after Create for the entry to be readable
+	NormalizeUnicode        types.String `tfsdk:"normalize_unicode"`         // Unicode normalization form of written values, nfc or nfd
 }
 
+// LdapEntryReadConsistencyModel describes how to wait for a written value to become visible after Create/Update.
+type LdapEntryReadConsistencyModel struct {
+	Attribute     types.String `tfsdk:"attribute"`      // Attribute whose written values must be observed
+	MaxRetries    types.Int64  `tfsdk:"max_retries"`    // Number of reads after the initial one before giving up
+	RetryInterval types.String `tfsdk:"retry_interval"` // Delay between reads as a duration string
+}
+
+// defaultImplicitObjectClasses are the implicit_object_classes of entries not setting it.
+var defaultImplicitObjectClasses = []string{"top"}
+
+const (
+	defaultReadConsistencyMaxRetries    = 5
+	defaultReadConsistencyRetryInterval = time.Second
+)
+
+const (
+	defaultCreateVisibilityTimeout = 5 * time.Second
+	createVisibilityRetryInterval  = 250 * time.Millisecond
+)
+
 // Metadata sets the resource type name for the LDAP entry resource.
 func (r *LdapEntryResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_entry"
@@ -55,18 +109,22 @@ func (r *LdapEntryResource) Schema(ctx context.Context, req resource.SchemaReque
 
 ### Omitted and null attributes
 Null or omitted attributes in the configuration are **not read or managed** by the provider.
+
+### Empty attributes
+LDAP has no notion of an attribute that is present without values. An attribute configured as an empty list (e.g. ` + "`mail = []`" + `) is managed as **absent**:
+it is left out when the entry is created, deleted from the server if it appears later, and read back as an empty list. It never causes an error.
 `,
 
 		Attributes: map[string]schema.Attribute{
 			"dn": schema.StringAttribute{
-				MarkdownDescription: "The distinguished name (DN) of the LDAP entry. Changing this forces a new resource to be created.",
-				Required:            true,
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
-				},
+				MarkdownDescription: "The distinguished name (DN) of the LDAP entry. Relative to the provider `base_dn` if it does not already end with it. " +
+					"Changing only the RDN (the first component) renames the entry in place with a ModifyDN operation; include the new RDN value in `attributes` as well. " +
+					"Changing the RDN value in `attributes` alone is rejected at plan time, as only a rename can change it. " +
+					"Changing the parent forces a new resource to be created. Switching between a relative DN and the equivalent absolute DN, or changing only the case or spacing of the DN, changes nothing on the server.",
+				Required: true,
 			},
 			"attributes": schema.MapAttribute{
-				MarkdownDescription: "Map of LDAP attributes for the entry. Attribute values must be described as lists, even for single values. The `objectClass` attribute is required and defines the schema for the entry.",
+				MarkdownDescription: "Map of LDAP attributes for the entry. Attribute values must be described as lists, even for single values. The `objectClass` attribute is required and defines the schema for the entry; changes to it add and remove single classes rather than replacing it, so auxiliary classes can be added to an existing entry.",
 				Required:            true,
 				ElementType:         types.ListType{ElemType: types.StringType},
 				PlanModifiers: []planmodifier.Map{
@@ -74,7 +132,7 @@ Null or omitted attributes in the configuration are **not read or managed** by t
 				},
 			},
 			"attributes_wo": schema.MapAttribute{
-				MarkdownDescription: "Write-only map of LDAP attributes for the entry containing sensitive values. Must be used in conjunction with `attributes_wo_version`. NOTE: `unicodePwd` will be automatically encoded as UTF-16LE for Active Directory.",
+				MarkdownDescription: "Write-only map of LDAP attributes for the entry containing sensitive values. Must be used in conjunction with `attributes_wo_version` or `attributes_wo_always`, except for attributes listed in `write_once_attributes`. An attribute must not be set in both `attributes` and `attributes_wo`, whatever the case of its name: this is an error, reported before anything is written even when one of the maps is only known during apply, rather than one of the values taking precedence. NOTE: `unicodePwd` will be automatically encoded as UTF-16LE for Active Directory, while other attributes such as `userPassword` are sent as given; all of them are written in the same add or modify operation.",
 				Optional:            true,
 				WriteOnly:           true,
 				ElementType:         types.ListType{ElemType: types.StringType},
@@ -83,6 +141,46 @@ Null or omitted attributes in the configuration are **not read or managed** by t
 				MarkdownDescription: "Version number for write-only attributes. Changing this version number triggers the provider to send the current `attributes_wo` values to the LDAP server during updates.",
 				Optional:            true,
 			},
+			"attributes_wo_always": schema.BoolAttribute{
+				MarkdownDescription: "Whether to send `attributes_wo` on every apply instead of only when `attributes_wo_version` changes, " +
+					"for values from ephemeral sources that differ every run but are meant to be written each time, such as a secret the directory must stay in sync with. " +
+					"**Note:** every plan then shows an update of the entry and every apply writes to the directory, even when nothing else changed. Defaults to `false`.",
+				Optional: true,
+			},
+			"attributes_wo_written_at": schema.StringAttribute{
+				MarkdownDescription: "When `attributes_wo` were last sent to the server, as an RFC 3339 timestamp. Null if they were never sent.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"write_once_attributes": schema.ListAttribute{
+				MarkdownDescription: "Attributes of `attributes_wo` written only when the entry is created, such as an initial `userPassword` users change afterwards. " +
+					"Updates never send them, whatever `attributes_wo_version` or `attributes_wo_always`, and they are never read back, so changes made to them later cause no drift. " +
+					"Every name must be a key of `attributes_wo`.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"binary_attributes": schema.ListAttribute{
+				MarkdownDescription: "List of attribute types holding binary data, such as `jpegPhoto`, `userCertificate` or `objectGUID`. Values of these attributes are written and read as standard base64 (e.g. from `filebase64()`). Matching ignores case and attribute options, so `userCertificate` also covers `userCertificate;binary`.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"force_recreate": schema.StringAttribute{
+				MarkdownDescription: "Arbitrary value that forces the entry to be deleted and created again whenever it changes, even if `dn` is unchanged. " +
+					"Use it as a recovery lever when incremental updates keep failing, e.g. by setting it to a timestamp or counter. " +
+					"**Note:** recreating the entry loses everything not in the configuration, including server-generated attributes such as `entryUUID`, `objectGUID`, `objectSid`, `createTimestamp` and any values written outside Terraform.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"unlock": schema.StringAttribute{
+				MarkdownDescription: "Arbitrary value that unlocks the account whenever it changes, e.g. a timestamp or counter. " +
+					"Active Directory only: the account is unlocked by setting `lockoutTime` to `0`, so there is no need to manage `lockoutTime` in `attributes` (and it should not be). " +
+					"Setting it when the entry is created, or removing it, does nothing.",
+				Optional: true,
+			},
 			"id": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "The unique identifier for this resource, which is the same as the DN.",
@@ -90,10 +188,527 @@ Null or omitted attributes in the configuration are **not read or managed** by t
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"missing_as_null": schema.BoolAttribute{
+				MarkdownDescription: "Whether managed attributes that are absent on the server are read into state as `null` instead of an empty list. Defaults to `false`. " +
+					"Since null attributes are not read or managed (see above), an attribute removed outside Terraform stops being refreshed once it is read as `null`; a non-empty configured value is still planned to be written back. " +
+					"Attributes configured as `[]` always show a difference when this is enabled, so use it only where absent and empty must be told apart.",
+				Optional: true,
+			},
+			"normalize_unicode": schema.StringAttribute{
+				MarkdownDescription: "Unicode normalization form, `nfc` or `nfd`, to convert the values of `attributes` to before writing them, for directories that store text in one form while the configuration uses the other, e.g. names from macOS, which uses NFD. " +
+					"Values read back that differ from the configured ones only by normalization are not drift, and state keeps the configured spelling. " +
+					"`attributes_wo` are written as given, so that passwords keep their exact bytes. Defaults to writing values unchanged.",
+				Optional: true,
+				Validators: []validator.String{
+					stringOneOfValidator{values: []string{"nfc", "nfd"}},
+				},
+			},
+			"sd_flags": schema.Int64Attribute{
+				MarkdownDescription: "Active Directory only. Sends the LDAP_SERVER_SD_FLAGS_OID control (`1.2.840.113556.1.4.801`) with every read and write of the entry, selecting which parts of `ntSecurityDescriptor` are read or written: " +
+					"`1` owner, `2` group, `4` DACL and `8` SACL, summed (e.g. `7` for owner, group and DACL). " +
+					"Without it AD reads and writes all parts, and touching the SACL requires the `SeSecurityPrivilege`. " +
+					"Add `ntSecurityDescriptor` to `binary_attributes` and give its value base64-encoded.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64BetweenValidator{min: 0, max: 15},
+				},
+			},
+			"authoritative_attributes": schema.ListAttribute{
+				MarkdownDescription: "Attributes of `attributes` whose drift is detected and corrected. " +
+					"Changes made outside Terraform to any other attribute are ignored: reading the entry keeps their values from state, so they never show a difference. " +
+					"They are still written on create and whenever their configured value changes. " +
+					"Use it for entries partially managed by other tools. Names are matched like attribute names (ignoring case unless `case_insensitive_attribute_names` is `false`). " +
+					"Defaults to all attributes; `[]` detects no drift at all.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"implicit_object_classes": schema.ListAttribute{
+				MarkdownDescription: "Object classes the server adds to `objectClass` on its own, such as `top` added by Active Directory. " +
+					"Unless `objectClass` in `attributes` lists them, they are left out when reading the entry, so they never show a difference, and are not removed from the entry. " +
+					"Names are compared ignoring case. Defaults to `[\"top\"]`; `[]` manages every object class.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"delete_old_rdn": schema.BoolAttribute{
+				MarkdownDescription: "Whether renaming the entry (see `dn`) removes the old RDN value from the entry (the ModifyDN `deleteoldrdn` flag). " +
+					"Set to `false` to keep it as an additional value, e.g. so that renaming `cn=Old` to `cn=New` leaves `cn` holding both `Old` and `New`; list both in `attributes`, otherwise the following update removes the old value anyway. Defaults to `true`.",
+				Optional: true,
+			},
+			"verify_destroy": schema.BoolAttribute{
+				MarkdownDescription: "Whether to confirm after deleting the entry that it is really gone, by searching for it, instead of trusting the delete result code. " +
+					"Destroy fails if the entry can still be found, e.g. because the delete was answered by a server that does not hold the entry or has not replicated yet. Defaults to `false`.",
+				Optional: true,
+			},
+			"allow_protected_delete": schema.BoolAttribute{
+				MarkdownDescription: "Whether the entry may be deleted although it has one of the provider's `protected_object_classes`. " +
+					"Destroy uses the value stored in state, so set it to `true` and apply before destroying or replacing the entry. Defaults to `false`.",
+				Optional: true,
+			},
+			"create_visibility_timeout": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("How long to wait after creating the entry for it to be readable, as a duration string (e.g. `30s`). "+
+					"The entry is searched for every %s until it is found, so that a directory whose replicas answer reads before the add reached them does not report the new entry as gone. "+
+					"If it is still not found, the apply succeeds with a warning. `0s` disables the check. Defaults to `%s`.", createVisibilityRetryInterval, defaultCreateVisibilityTimeout),
+				Optional: true,
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
+			"member_batch_size": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Maximum number of values of a single attribute, such as the `member` attribute of a large group, sent in one add or modify operation. "+
+					"Larger value lists are written with several operations, and updates of attributes with more values than this send only the added and removed values. "+
+					"Lower it if the server rejects large operations with `adminLimitExceeded`. Defaults to `%d`.", defaultMemberBatchSize),
+				Optional: true,
+				Validators: []validator.Int64{
+					int64BetweenValidator{min: 1, max: math.MaxInt32},
+				},
+			},
+			"max_value_bytes": schema.Int64Attribute{
+				MarkdownDescription: "Largest attribute value, in bytes, accepted in `attributes` and `attributes_wo`; binary attributes are measured after base64 decoding. " +
+					"Planning fails with an error naming the attribute when a value is larger, e.g. to catch a large file mistakenly going into `jpegPhoto`. " +
+					"Overrides the provider `max_value_bytes`. Defaults to the provider setting, which defaults to no limit.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64BetweenValidator{min: 1, max: math.MaxInt32},
+				},
+			},
+			"read_deref_aliases": schema.BoolAttribute{
+				MarkdownDescription: "Whether to dereference `dn` when it is an alias entry, so that reads return the attributes of the aliased (real) entry. " +
+					"Only reads are affected; LDAP never dereferences aliases for add, modify or delete operations, so writes still target `dn` itself. Defaults to `false`.",
+				Optional: true,
+			},
+			"read_filter": schema.StringAttribute{
+				MarkdownDescription: "Filter of the base-scope search reading the entry on refresh, e.g. `(objectClass=alias)` for an alias entry or a filter matching only what the bound identity may see. " +
+					"An entry not matching the filter is treated as gone, so it is removed from the state and planned for creation again. Defaults to `(objectClass=*)`.",
+				Optional: true,
+				Validators: []validator.String{
+					filterValidator{},
+				},
+			},
+			"read_consistency": schema.SingleNestedAttribute{
+				MarkdownDescription: "Wait for a written value to become visible before finishing Create/Update. Useful against eventually-consistent replicas or load balancers where a read right after a write may hit a server that has not seen the change yet. After the write, the entry is read back until `attribute` holds the values from `attributes`; a warning is emitted if it never does.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"attribute": schema.StringAttribute{
+						MarkdownDescription: "Name of an attribute managed in `attributes` whose values are polled after each write. Attributes of `attributes_wo` cannot be used. " +
+							"Values of attributes with DN syntax, such as `member`, are compared as DNs, ignoring case and spacing; other values must be read back exactly as written.",
+						Required: true,
+					},
+					"max_retries": schema.Int64Attribute{
+						MarkdownDescription: fmt.Sprintf("Number of additional reads to attempt before giving up. Defaults to `%d`.", defaultReadConsistencyMaxRetries),
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64BetweenValidator{min: 0, max: math.MaxInt32},
+						},
+					},
+					"retry_interval": schema.StringAttribute{
+						MarkdownDescription: fmt.Sprintf("Time to wait between reads, as a duration string (e.g. `500ms`, `2s`). Defaults to `%s`.", defaultReadConsistencyRetryInterval),
+						Optional:            true,
+						Validators: []validator.String{
+							durationValidator{},
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
+// ModifyPlan forces replacement when the parent of the DN changes; a changed RDN alone is a rename
+// done in Update. DNs are compared after resolving them against the provider base_dn, so switching
+// between a relative DN and the same absolute DN (e.g. after importing by absolute DN) is an
+// in-place update rather than a replacement.
+//
+// Unless the provider sets case_insensitive_attribute_names = false, attribute names differing only
+// in case name the same attribute: configuring both is an error, and renaming an attribute to a
+// different case plans no change.
+func (r *LdapEntryResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	foldCase := r.client.FoldsAttributeNames()
+
+	var configAttributes, configWriteOnly types.Map
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("attributes"), &configAttributes)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("attributes_wo"), &configWriteOnly)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if foldCase {
+		resp.Diagnostics.Append(validateUniqueAttributeNames(configAttributes)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Checked again here as config validation skips maps only known during apply
+	resp.Diagnostics.Append(writeOnlyConflictDiagnostics(configAttributes, configWriteOnly)...)
+	var readConsistency *LdapEntryReadConsistencyModel
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("read_consistency"), &readConsistency)...)
+	if readConsistency != nil {
+		resp.Diagnostics.Append(readConsistencyAttributeDiagnostics(readConsistency.Attribute, configAttributes, configWriteOnly, foldCase)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.validateValueSizes(ctx, req.Config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.validateAllowedDN(ctx, req.Plan)...)
+	resp.Diagnostics.Append(r.validateRDNValues(ctx, req.Plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Nothing to replace on create
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	if foldCase && !configAttributes.IsNull() && !configAttributes.IsUnknown() {
+		var stateAttributes types.Map
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("attributes"), &stateAttributes)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		equal, diags := attributesEqualAsSets(ctx, configAttributes, stateAttributes, true)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if equal {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("attributes"), stateAttributes)...)
+		}
+	}
+
+	var stateDN, planDN types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("dn"), &stateDN)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("dn"), &planDN)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if planDN.IsUnknown() {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("dn"))
+		return
+	}
+
+	var writeOnlyAlways types.Bool
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("attributes_wo_always"), &writeOnlyAlways)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Plan an update on every run, as Terraform only applies resources whose plan differs from the state
+	if writeOnlyAlways.ValueBool() && !configWriteOnly.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("attributes_wo_written_at"), types.StringUnknown())...)
+	}
+
+	newDN := r.client.ResolveDN(planDN.ValueString())
+	oldDN := r.client.ResolveDN(stateDN.ValueString())
+	// DNs differing only in case or spacing name the same entry: nothing to rename or replace
+	if equalDN(newDN, oldDN) {
+		return
+	}
+
+	if !sameParentDN(newDN, oldDN) {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("dn"))
+		return
+	}
+
+	// Renamed in place; the id follows the new DN
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.StringUnknown())...)
+}
+
+// validateValueSizes reports an error for each value in attributes or attributes_wo larger than
+// max_value_bytes, or the provider max_value_bytes if that is not set.
+func (r *LdapEntryResource) validateValueSizes(ctx context.Context, config tfsdk.Config) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var maxValueBytes types.Int64
+	diags.Append(config.GetAttribute(ctx, path.Root("max_value_bytes"), &maxValueBytes)...)
+	if diags.HasError() || maxValueBytes.IsUnknown() {
+		return diags
+	}
+
+	limit := 0
+	if r.client != nil {
+		limit = r.client.MaxValueBytes
+	}
+	if !maxValueBytes.IsNull() {
+		limit = int(maxValueBytes.ValueInt64())
+	}
+	if limit <= 0 {
+		return diags
+	}
+
+	var binaryAttributes types.List
+	diags.Append(config.GetAttribute(ctx, path.Root("binary_attributes"), &binaryAttributes)...)
+	if diags.HasError() {
+		return diags
+	}
+	var binaryNames []string
+	if !binaryAttributes.IsNull() && !binaryAttributes.IsUnknown() {
+		diags.Append(binaryAttributes.ElementsAs(ctx, &binaryNames, true)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	for _, name := range []string{"attributes", "attributes_wo"} {
+		var attributes types.Map
+		diags.Append(config.GetAttribute(ctx, path.Root(name), &attributes)...)
+		if diags.HasError() {
+			return diags
+		}
+		diags.Append(oversizedValues(path.Root(name), attributes, binaryNames, limit)...)
+	}
+
+	return diags
+}
+
+// oversizedValues reports an error for each known value in attributes longer than limit bytes.
+// Values of binaryAttributes are measured after base64 decoding.
+func oversizedValues(attributesPath path.Path, attributes types.Map, binaryAttributes []string, limit int) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if attributes.IsNull() || attributes.IsUnknown() {
+		return diags
+	}
+
+	names := make([]string, 0, len(attributes.Elements()))
+	for name := range attributes.Elements() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		values, ok := attributes.Elements()[name].(types.List)
+		if !ok || values.IsNull() || values.IsUnknown() {
+			continue
+		}
+
+		binary := isBinaryAttribute(name, binaryAttributes)
+		for i, element := range values.Elements() {
+			value, ok := element.(types.String)
+			if !ok || value.IsNull() || value.IsUnknown() {
+				continue
+			}
+
+			size := len(value.ValueString())
+			if binary {
+				if decoded, err := base64.StdEncoding.DecodeString(value.ValueString()); err == nil {
+					size = len(decoded)
+				}
+			}
+
+			if size > limit {
+				diags.AddAttributeError(
+					attributesPath.AtMapKey(name).AtListIndex(i),
+					"Attribute value too large",
+					fmt.Sprintf("Value %d of attribute %s is %d bytes, more than the max_value_bytes limit of %d bytes.", i, name, size, limit),
+				)
+			}
+		}
+	}
+
+	return diags
+}
+
+// validateAllowedDN reports an error if the planned DN is outside the provider allowed_base_dns.
+func (r *LdapEntryResource) validateAllowedDN(ctx context.Context, plan tfsdk.Plan) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var dn types.String
+	diags.Append(plan.GetAttribute(ctx, path.Root("dn"), &dn)...)
+	if diags.HasError() || dn.IsUnknown() || dn.IsNull() {
+		return diags
+	}
+
+	resolved := r.client.ResolveDN(dn.ValueString())
+	if !r.client.AllowsDN(resolved) {
+		diags.AddAttributeError(
+			path.Root("dn"),
+			"DN outside allowed base DNs",
+			fmt.Sprintf("Entry %s is not below any of the allowed_base_dns of the provider (%s).", resolved, strings.Join(r.client.AllowedBaseDNs, "; ")),
+		)
+	}
+	return diags
+}
+
+// validateRDNValues reports an error if attributes manages an attribute the DN is named by without
+// the value the DN names. Such a plan fails on the server: the RDN value cannot be removed by a
+// modify, and the RDN can only be changed by renaming the entry, i.e. by changing dn.
+func (r *LdapEntryResource) validateRDNValues(ctx context.Context, plan tfsdk.Plan) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var dn types.String
+	var attributes types.Map
+	diags.Append(plan.GetAttribute(ctx, path.Root("dn"), &dn)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root("attributes"), &attributes)...)
+	if diags.HasError() || dn.IsUnknown() || attributes.IsUnknown() || attributes.IsNull() {
+		return diags
+	}
+
+	attrs := make(map[string][]string)
+	for name, value := range attributes.Elements() {
+		list, ok := value.(types.List)
+		if !ok || list.IsUnknown() {
+			// Unknown values are checked once they are known
+			return diags
+		}
+		for _, v := range list.Elements() {
+			s, ok := v.(types.String)
+			if !ok || s.IsUnknown() {
+				return diags
+			}
+			attrs[name] = append(attrs[name], s.ValueString())
+		}
+	}
+
+	resolved := r.client.ResolveDN(dn.ValueString())
+	for _, missing := range missingRDNValues(resolved, attrs) {
+		detail := fmt.Sprintf("The DN %s is named by %s=%s, but attribute %s does not include %q. "+
+			"The RDN value cannot be removed by modifying the attribute; change dn to rename the entry instead.",
+			resolved, missing.Type, ldap.EscapeDN(missing.Value), missing.Name, missing.Value)
+		if values := attrs[missing.Name]; len(values) == 1 {
+			if rdns, err := ldap.ParseDN(resolved); err == nil && len(rdns.RDNs[0].Attributes) == 1 {
+				_, parent := splitDN(resolved)
+				renamed := missing.Type + "=" + ldap.EscapeDN(values[0])
+				if parent != "" {
+					renamed += "," + parent
+				}
+				detail += fmt.Sprintf(" To rename it to match %s, set dn to %q.", missing.Name, renamed)
+			}
+		}
+		diags.AddAttributeError(path.Root("attributes").AtMapKey(missing.Name), "RDN value missing from attributes", detail)
+	}
+
+	return diags
+}
+
+// missingRDNValue is an attribute value of the RDN of a DN that is missing from the attribute
+// Name of an entry.
+type missingRDNValue struct {
+	Name  string
+	Type  string
+	Value string
+}
+
+// missingRDNValues returns the values of the first RDN of dn whose attribute type is in attributes
+// (ignoring case) without that value. Values are compared ignoring case, as the naming attributes in
+// common use are case-insensitive. Attributes not in attributes are not managed and not checked.
+func missingRDNValues(dn string, attributes map[string][]string) []missingRDNValue {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 {
+		return nil
+	}
+
+	var missing []missingRDNValue
+	for _, ava := range parsed.RDNs[0].Attributes {
+		name, exists := findAttribute(attributes, ava.Type, true)
+		if !exists {
+			continue
+		}
+		found := false
+		for _, v := range attributes[name] {
+			if strings.EqualFold(v, ava.Value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, missingRDNValue{Name: name, Type: ava.Type, Value: ava.Value})
+		}
+	}
+	return missing
+}
+
+// validateUniqueAttributeNames reports an error for each attribute name that differs from another
+// only in case, since both would name the same LDAP attribute.
+func validateUniqueAttributeNames(attributes types.Map) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if attributes.IsNull() || attributes.IsUnknown() {
+		return diags
+	}
+
+	names := make([]string, 0, len(attributes.Elements()))
+	for name := range attributes.Elements() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	seen := make(map[string]string)
+	for _, name := range names {
+		if other, ok := seen[strings.ToLower(name)]; ok {
+			diags.AddAttributeError(
+				path.Root("attributes").AtMapKey(name),
+				"Duplicate attribute",
+				fmt.Sprintf("Attributes %q and %q differ only in case and name the same LDAP attribute. Merge their values into one of them.", other, name),
+			)
+			continue
+		}
+		seen[strings.ToLower(name)] = name
+	}
+
+	return diags
+}
+
+// equalDN reports whether two DNs name the same entry, compared component by component ignoring
+// case and insignificant spaces. DNs that do not parse are compared as strings.
+func equalDN(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	parsedA, err := ldap.ParseDN(a)
+	if err != nil {
+		return false
+	}
+	parsedB, err := ldap.ParseDN(b)
+	if err != nil {
+		return false
+	}
+
+	return parsedA.EqualFold(parsedB)
+}
+
+// sameParentDN reports whether two DNs have the same parent, compared case-insensitively.
+func sameParentDN(a, b string) bool {
+	_, parentA := splitDN(a)
+	_, parentB := splitDN(b)
+
+	if parentA == "" || parentB == "" {
+		return parentA == parentB
+	}
+
+	parsedA, err := ldap.ParseDN(parentA)
+	if err != nil {
+		return false
+	}
+	parsedB, err := ldap.ParseDN(parentB)
+	if err != nil {
+		return false
+	}
+
+	return parsedA.EqualFold(parsedB)
+}
+
+func (r *LdapEntryResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		attributesWriteOnlyConflictValidator{},
+		writeOnceAttributesValidator{},
+		readConsistencyAttributeValidator{foldCase: r.client.FoldsAttributeNames()},
+	}
+}
+
 // Configure initializes the resource with the LDAP client connection from the provider.
 func (r *LdapEntryResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	r.client = GetLdapConnection(req.ProviderData, &resp.Diagnostics, "Resource")
@@ -125,8 +740,12 @@ func (r *LdapEntryResource) Create(ctx context.Context, req resource.CreateReque
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if form, ok := plan.unicodeForm(); ok {
+		normalizeAttributeValues(attributes, form)
+	}
 
 	if !config.AttributesWO.IsNull() {
+		redactWriteOnlyAttributes(config.AttributesWO)
 		diags = unmarshalTerraformAttributes(ctx, &config.AttributesWO, attributes)
 		resp.Diagnostics.Append(diags...)
 		if resp.Diagnostics.HasError() {
@@ -140,12 +759,28 @@ func (r *LdapEntryResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	// Create LDAP add request
-	addReq := ldap.NewAddRequest(plan.DN.ValueString(), nil)
+	resp.Diagnostics.Append(decodeBinaryAttributes(ctx, plan.BinaryAttributes, attributes)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dn := r.client.ResolveDN(plan.DN.ValueString())
+
+	// Create LDAP add request. Very large value lists are added in batches after the entry exists.
+	addReq := ldap.NewAddRequest(dn, plan.controls())
+	var pending []ldap.PartialAttribute
 	for attr, values := range attributes {
-		// Skip attributes with empty values - LDAP servers reject empty attributes during creation
-		if len(values) > 0 {
-			addReq.Attribute(attr, values)
+		// Skip attributes with empty values - LDAP servers reject empty attributes during creation.
+		// An empty list means the attribute is managed as absent, which a new entry already satisfies.
+		if len(values) == 0 {
+			tflog.Debug(ctx, fmt.Sprintf("omitting attribute %s with no values from the add request for %s", attr, dn))
+			continue
+		}
+
+		chunks := chunkValues(values, plan.batchSize())
+		addReq.Attribute(attr, chunks[0])
+		for _, chunk := range chunks[1:] {
+			pending = append(pending, ldap.PartialAttribute{Type: attr, Vals: chunk})
 		}
 	}
 
@@ -154,13 +789,37 @@ func (r *LdapEntryResource) Create(ctx context.Context, req resource.CreateReque
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating LDAP entry",
-			fmt.Sprintf("Unable to create LDAP entry %s: %s", plan.DN.ValueString(), err),
+			fmt.Sprintf("Unable to create LDAP entry %s: %s", dn, err),
 		)
 		return
 	}
 	tflog.Trace(ctx, fmt.Sprintf("created an LDAP entry: %s", plan.Id))
 
-	plan.Id = plan.DN
+	if err := addAttributeValues(ctx, r.client, dn, pending, plan.controls()); err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating LDAP entry",
+			fmt.Sprintf("LDAP entry %s was created but not all attribute values could be added: %s", dn, err),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(r.waitForCreatedEntry(ctx, dn, plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.ReadConsistency != nil {
+		resp.Diagnostics.Append(r.waitForReadConsistency(ctx, dn, plan.ReadConsistency, attributes)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	plan.Id = types.StringValue(dn)
+	plan.AttributesWOWrittenAt = types.StringNull()
+	if !config.AttributesWO.IsNull() {
+		plan.AttributesWOWrittenAt = types.StringValue(time.Now().UTC().Format(time.RFC3339))
+	}
 
 	// Save plan into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
@@ -211,20 +870,53 @@ func (r *LdapEntryResource) Read(ctx context.Context, req resource.ReadRequest,
 		}
 	}
 
-	sr, err := LdapSearch(r.client, state.DN.ValueString(), "base", "(objectClass=*)", attributesToRequest)
+	searchOpts := LdapSearchOptions{DerefAliases: ldap.NeverDerefAliases, Controls: state.controls()}
+	if state.ReadDerefAliases.ValueBool() {
+		searchOpts.DerefAliases = ldap.DerefFindingBaseObj
+	}
+
+	filter := "(objectClass=*)"
+	if !state.ReadFilter.IsNull() {
+		filter = state.ReadFilter.ValueString()
+	}
+
+	dn := r.client.ResolveDN(state.DN.ValueString())
+
+	sr, err := LdapSearch(r.client, dn, "base", filter, attributesToRequest, searchOpts)
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+		tflog.Debug(ctx, fmt.Sprintf("LDAP entry %s no longer exists, removing from state", dn))
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading LDAP entry",
-			fmt.Sprintf("Unable to read LDAP entry %s: %s", state.DN.ValueString(), err),
+			fmt.Sprintf("Unable to read LDAP entry %s: %s", dn, err),
 		)
 		return
 	}
 
-	results, err := MarshalLdapResults(ctx, sr, attributesToRequest)
+	var binaryAttributes []string
+	if !state.BinaryAttributes.IsNull() {
+		resp.Diagnostics.Append(state.BinaryAttributes.ElementsAs(ctx, &binaryAttributes, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// State holds exactly the managed attributes: anything else the server returns, such as an
+	// attribute under a spelling that is not managed, must not reappear in state.
+	results, err := MarshalLdapResults(ctx, sr, attributesToRequest, MarshalOptions{
+		BinaryAttributes:   binaryAttributes,
+		MissingAsNull:      state.MissingAsNull.ValueBool(),
+		FoldAttributeNames: r.client.FoldsAttributeNames(),
+		OnlyRequested:      true,
+		Lenient:            r.client.LenientReads(),
+	})
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error marshaling LDAP results",
-			fmt.Sprintf("Unable to marshal LDAP results for %s: %s", state.DN.ValueString(), err),
+			fmt.Sprintf("Unable to marshal LDAP results for %s: %s", dn, err),
 		)
 		return
 	}
@@ -232,11 +924,52 @@ func (r *LdapEntryResource) Read(ctx context.Context, req resource.ReadRequest,
 		resp.State.RemoveResource(ctx)
 		return
 	}
+	resp.Diagnostics.Append(marshalWarnings(results)...)
 
 	entry := results[0]
 
-	state.Attributes = entry.Attributes
-	state.Id = state.DN
+	// Object classes the server added on its own are not drift
+	var implicitClasses []string
+	resp.Diagnostics.Append(state.implicitObjectClasses(ctx, &implicitClasses)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	entry.Attributes, diags = dropImplicitObjectClasses(ctx, entry.Attributes, attrsMap, implicitClasses)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Values the server returns in another Unicode normalization form are unchanged
+	if form, ok := state.unicodeForm(); ok {
+		entry.Attributes, diags = keepPriorNormalizedValues(ctx, entry.Attributes, attrsMap, form)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Values the server returns in another order are unchanged: keep them as they were, so that
+	// neither plans nor refresh-only runs report a difference
+	state.Attributes, diags = keepPriorValueOrder(ctx, entry.Attributes, attrsMap)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !state.AuthoritativeAttributes.IsNull() {
+		var authoritative []string
+		resp.Diagnostics.Append(state.AuthoritativeAttributes.ElementsAs(ctx, &authoritative, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		state.Attributes, diags = keepNonAuthoritativeValues(state.Attributes, attrsMap, authoritative, r.client.FoldsAttributeNames())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	state.Id = types.StringValue(dn)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
@@ -270,16 +1003,29 @@ func (r *LdapEntryResource) Update(ctx context.Context, req resource.UpdateReque
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	form, normalize := plan.unicodeForm()
+	if normalize {
+		normalizeAttributeValues(attributes, form)
+	}
 
 	versionChanged := !plan.AttributesWOVer.Equal(state.AttributesWOVer)
-
-	// Convert write-only attributes from config only if version changed
-	if versionChanged && !config.AttributesWO.IsNull() {
-		diags = unmarshalTerraformAttributes(ctx, &config.AttributesWO, attributes)
+	sendWriteOnly := !config.AttributesWO.IsNull() && (versionChanged || plan.AttributesWOAlways.ValueBool())
+
+	// Convert write-only attributes from config only if version changed or they are always sent.
+	// Write-once attributes were sent on create and are left alone.
+	if sendWriteOnly {
+		redactWriteOnlyAttributes(config.AttributesWO)
+		writeOnly := make(map[string][]string)
+		diags = unmarshalTerraformAttributes(ctx, &config.AttributesWO, writeOnly)
 		resp.Diagnostics.Append(diags...)
+		resp.Diagnostics.Append(removeWriteOnceAttributes(ctx, plan.WriteOnceAttributes, writeOnly)...)
 		if resp.Diagnostics.HasError() {
 			return
 		}
+		sendWriteOnly = len(writeOnly) > 0
+		for name, values := range writeOnly {
+			attributes[name] = values
+		}
 
 		// Special handling for unicodePwd attribute (Active Directory)
 		resp.Diagnostics.Append(ProcessUnicodePwd(attributes)...)
@@ -296,25 +1042,85 @@ func (r *LdapEntryResource) Update(ctx context.Context, req resource.UpdateReque
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	// State keeps the configured spelling, which was written normalized
+	if normalize {
+		normalizeAttributeValues(currentAttrs, form)
+	}
+
+	// Compare and write binary attributes as raw bytes. State values are decoded
+	// with the binary attribute list they were stored with.
+	resp.Diagnostics.Append(decodeBinaryAttributes(ctx, plan.BinaryAttributes, attributes)...)
+	resp.Diagnostics.Append(decodeBinaryAttributes(ctx, state.BinaryAttributes, currentAttrs)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	// Create LDAP modify request
-	modifyReq := ldap.NewModifyRequest(plan.DN.ValueString(), nil)
+	// An attribute renamed to a different case is the same attribute, not a removal and an addition
+	if r.client.FoldsAttributeNames() {
+		alignAttributeNames(currentAttrs, attributes)
+	}
+
+	dn := r.client.ResolveDN(plan.DN.ValueString())
+
+	// Rename before modifying, so attribute changes apply to the entry under its new DN
+	if oldDN := r.client.ResolveDN(state.DN.ValueString()); !equalDN(oldDN, dn) {
+		newRDN, _ := splitDN(dn)
+		deleteOldRDN := plan.DeleteOldRDN.IsNull() || plan.DeleteOldRDN.ValueBool()
+
+		tflog.Debug(ctx, fmt.Sprintf("Renaming LDAP entry %s to %s (deleteoldrdn %t)", oldDN, dn, deleteOldRDN))
+
+		modifyDNReq := ldap.NewModifyDNWithControlsRequest(oldDN, newRDN, deleteOldRDN, "", plan.controls())
+		if err := r.client.ModifyDN(modifyDNReq); err != nil {
+			resp.Diagnostics.AddError(
+				"Error renaming LDAP entry",
+				fmt.Sprintf("Unable to rename LDAP entry %s to %s: %s", oldDN, dn, err),
+			)
+			return
+		}
+	}
+
+	// Object classes the server added on its own stay on the entry when config stops listing them
+	var implicitClasses []string
+	resp.Diagnostics.Append(plan.implicitObjectClasses(ctx, &implicitClasses)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create LDAP modify request. Attributes with more values than the batch size are changed
+	// incrementally with follow-up operations: only the removed and added values are sent, in
+	// batches. Without a previous value to diff against, a very large value list replaces with the
+	// first batch and adds the remaining batches instead.
+	batchSize := plan.batchSize()
+	modifyReq := ldap.NewModifyRequest(dn, plan.controls())
+	var pending []ldap.PartialAttribute
+	var incremental []string
 
 	// Update changed attributes
 	for key, newValues := range attributes {
 		if currentValues, exists := currentAttrs[key]; !exists || !stringSlicesEqual(currentValues, newValues) {
-			if len(newValues) == 0 {
+			if exists && len(newValues) > 0 && strings.EqualFold(attributeType(key), "objectClass") {
+				// Add and delete single classes in the same request: some servers reject replacing
+				// objectClass as a whole, as that includes the structural class
+				additions, removals := objectClassChanges(currentValues, newValues)
+				removals = withoutObjectClasses(removals, implicitClasses)
+				if len(additions) > 0 {
+					modifyReq.Add(key, additions)
+				}
+				if len(removals) > 0 {
+					modifyReq.Delete(key, removals)
+				}
+			} else if len(newValues) == 0 {
 				// Delete attribute if it exists in LDAP
 				// Check state first (fast path), then check LDAP (for null → [] transitions)
 				shouldDelete := exists
 				if !shouldDelete {
 					// Attribute not in state - check if it exists in LDAP
 					// This handles null → [] transitions where the attribute exists but wasn't tracked
-					existsInLDAP, _, err := AttributeExistsInLDAP(r.client, plan.DN.ValueString(), key)
+					existsInLDAP, _, err := AttributeExistsInLDAP(r.client, dn, key)
 					if err != nil {
 						resp.Diagnostics.AddError(
 							"Error checking LDAP attribute existence",
-							fmt.Sprintf("Unable to check if attribute %s exists for %s: %s", key, plan.DN.ValueString(), err),
+							fmt.Sprintf("Unable to check if attribute %s exists for %s: %s", key, dn, err),
 						)
 						return
 					}
@@ -324,8 +1130,14 @@ func (r *LdapEntryResource) Update(ctx context.Context, req resource.UpdateReque
 				if shouldDelete {
 					modifyReq.Delete(key, nil)
 				}
+			} else if exists && (len(currentValues) > batchSize || len(newValues) > batchSize) {
+				incremental = append(incremental, key)
 			} else {
-				modifyReq.Replace(key, newValues)
+				chunks := chunkValues(newValues, batchSize)
+				modifyReq.Replace(key, chunks[0])
+				for _, chunk := range chunks[1:] {
+					pending = append(pending, ldap.PartialAttribute{Type: key, Vals: chunk})
+				}
 			}
 		}
 	}
@@ -336,20 +1148,68 @@ func (r *LdapEntryResource) Update(ctx context.Context, req resource.UpdateReque
 			modifyReq.Delete(key, nil)
 		}
 	}
+	modifyReq.Changes = orderModifyChanges(modifyReq.Changes)
+
+	// Execute LDAP modify operations if there are changes
+	if len(modifyReq.Changes) > 0 || len(incremental) > 0 {
+		if len(modifyReq.Changes) > 0 {
+			err := r.client.Modify(modifyReq)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error updating LDAP entry",
+					fmt.Sprintf("Unable to update LDAP entry %s: %s", dn, err),
+				)
+				return
+			}
+		}
 
-	// Execute LDAP modify operation if there are changes
-	if len(modifyReq.Changes) > 0 {
-		err := r.client.Modify(modifyReq)
-		if err != nil {
+		if err := addAttributeValues(ctx, r.client, dn, pending, plan.controls()); err != nil {
 			resp.Diagnostics.AddError(
 				"Error updating LDAP entry",
-				fmt.Sprintf("Unable to update LDAP entry %s: %s", plan.DN.ValueString(), err),
+				fmt.Sprintf("Unable to update LDAP entry %s: %s", dn, err),
+			)
+			return
+		}
+
+		for _, key := range incremental {
+			if err := modifyAttributeValues(ctx, r.client, dn, key, currentAttrs[key], attributes[key], batchSize, plan.controls()); err != nil {
+				resp.Diagnostics.AddError(
+					"Error updating LDAP entry",
+					fmt.Sprintf("Unable to update LDAP entry %s: %s", dn, err),
+				)
+				return
+			}
+		}
+
+		if plan.ReadConsistency != nil {
+			resp.Diagnostics.Append(r.waitForReadConsistency(ctx, dn, plan.ReadConsistency, attributes)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+	}
+
+	if !plan.Unlock.IsNull() && !plan.Unlock.Equal(state.Unlock) {
+		tflog.Debug(ctx, fmt.Sprintf("Unlocking LDAP entry %s", dn))
+
+		if err := UnlockAccount(r.client, dn, plan.controls()); err != nil {
+			resp.Diagnostics.AddError(
+				"Error unlocking LDAP entry",
+				fmt.Sprintf("Unable to unlock LDAP entry %s by setting lockoutTime to 0: %s", dn, err),
 			)
 			return
 		}
 	}
 
-	plan.Id = plan.DN
+	// The id only follows renames, not cosmetic changes of the DN
+	if plan.Id.IsUnknown() {
+		plan.Id = types.StringValue(dn)
+	}
+	if sendWriteOnly {
+		plan.AttributesWOWrittenAt = types.StringValue(time.Now().UTC().Format(time.RFC3339))
+	} else if plan.AttributesWOWrittenAt.IsUnknown() {
+		plan.AttributesWOWrittenAt = state.AttributesWOWrittenAt
+	}
 
 	// Save updated plan into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
@@ -363,22 +1223,77 @@ func (r *LdapEntryResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
-	delReq := ldap.NewDelRequest(data.DN.ValueString(), nil)
+	dn := r.client.ResolveDN(data.DN.ValueString())
+
+	if len(r.client.ProtectedObjectClasses) > 0 && !data.AllowProtectedDelete.ValueBool() {
+		sr, err := LdapSearch(r.client, dn, "base", "(objectClass=*)", []string{"objectClass"}, LdapSearchOptions{Controls: data.controls()})
+		if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			resp.Diagnostics.AddError(
+				"Error deleting LDAP entry",
+				fmt.Sprintf("Unable to read the object classes of LDAP entry %s to check protected_object_classes: %s", dn, err),
+			)
+			return
+		}
+		if err == nil && len(sr.Entries) > 0 {
+			if protected := r.client.ProtectedClassesOf(sr.Entries[0].GetEqualFoldAttributeValues("objectClass")); len(protected) > 0 {
+				resp.Diagnostics.AddError(
+					"LDAP entry is protected",
+					fmt.Sprintf("Refusing to delete LDAP entry %s because it has the object class %s, which the provider's protected_object_classes protects. "+
+						"If the entry really should be deleted, set allow_protected_delete = true on the resource and apply before destroying it.",
+						dn, strings.Join(protected, ", ")),
+				)
+				return
+			}
+		}
+	}
+
+	delReq := ldap.NewDelRequest(dn, nil)
 
 	err := r.client.Del(delReq)
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultNotAllowedOnNonLeaf) {
+		resp.Diagnostics.AddError(
+			"LDAP entry has children",
+			fmt.Sprintf("Unable to delete LDAP entry %s because it still has entries beneath it. "+
+				"Delete the child entries first, for example by making the ldap_entry resources that manage "+
+				"them reference this entry's dn so Terraform destroys them before their parent.\n\n"+
+				"Server response: %s", dn, err),
+		)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting LDAP entry",
-			fmt.Sprintf("Unable to delete LDAP entry %s: %s", data.DN.ValueString(), err),
+			fmt.Sprintf("Unable to delete LDAP entry %s: %s", dn, err),
 		)
 		return
 	}
+
+	if data.VerifyDestroy.ValueBool() {
+		exists, err := EntryExistsInLDAP(r.client, dn, LdapSearchOptions{Controls: data.controls()})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error verifying LDAP entry deletion",
+				fmt.Sprintf("Unable to verify that LDAP entry %s was deleted: %s", dn, err),
+			)
+			return
+		}
+		if exists {
+			resp.Diagnostics.AddError(
+				"LDAP entry still exists after delete",
+				fmt.Sprintf("The server reported LDAP entry %s as deleted, but it can still be found.", dn),
+			)
+			return
+		}
+	}
 }
 
 func (r *LdapEntryResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	// Import ID can be either:
 	// 1. Simple DN string: "CN=user,OU=Users,DC=example,DC=com"
 	// 2. JSON object: {"dn": "CN=user,OU=Users,DC=example,DC=com", "attributes": ["objectClass", "cn"]}
+	//    An explicitly empty list ("attributes": []) imports no attributes at all.
+	// In both, the DN may be replaced by the entryUUID or objectGUID of the entry, which is
+	// resolved to its current DN.
 
 	var dn string
 	var attributesToImport []string
@@ -391,12 +1306,27 @@ func (r *LdapEntryResource) ImportState(ctx context.Context, req resource.Import
 	if err := json.Unmarshal([]byte(req.ID), &importSpec); err == nil {
 		dn = importSpec.DN
 		attributesToImport = importSpec.Attributes
+		if attributesToImport != nil && len(attributesToImport) == 0 {
+			attributesToImport = []string{noAttributes}
+		}
 	} else {
 		// Not JSON, treat as simple DN string
 		dn = req.ID
 		attributesToImport = []string{"objectClass"} // Default to just objectClass
 	}
 
+	if isUUID(dn) {
+		resolved, err := r.findDNByUUID(dn)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Failed to import LDAP entry by UUID",
+				fmt.Sprintf("Unable to find the entry with UUID %s: %s", dn, err),
+			)
+			return
+		}
+		dn = resolved
+	}
+
 	// Set the DN in state
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("dn"), dn)...)
 
@@ -416,6 +1346,162 @@ func (r *LdapEntryResource) ImportState(ctx context.Context, req resource.Import
 	}
 }
 
+// findDNByUUID returns the DN of the entry with the entryUUID or objectGUID uuid, searching below
+// the provider's base_dn, or else below every naming context of the server.
+func (r *LdapEntryResource) findDNByUUID(uuid string) (string, error) {
+	if r.client == nil {
+		return "", errors.New("the provider is not configured")
+	}
+
+	baseDNs := []string{r.client.BaseDN}
+	if r.client.BaseDN == "" {
+		sr, err := LdapSearch(r.client, "", "base", "(objectClass=*)", []string{"namingContexts"}, LdapSearchOptions{})
+		if err != nil {
+			return "", fmt.Errorf("unable to read the naming contexts from the Root DSE: %w", err)
+		}
+		if len(sr.Entries) > 0 {
+			baseDNs = sr.Entries[0].GetEqualFoldAttributeValues("namingContexts")
+		}
+		if len(baseDNs) == 0 {
+			return "", errors.New("the Root DSE lists no namingContexts to search; set the provider's base_dn")
+		}
+	}
+
+	return FindDNByUUID(r.client, baseDNs, uuid)
+}
+
+// batchSize returns the maximum number of values of one attribute written per operation.
+func (m LdapEntryResourceModel) batchSize() int {
+	if m.MemberBatchSize.IsNull() || m.MemberBatchSize.IsUnknown() {
+		return defaultMemberBatchSize
+	}
+	return int(m.MemberBatchSize.ValueInt64())
+}
+
+// controls returns the request controls configured for the entry.
+func (m LdapEntryResourceModel) controls() []ldap.Control {
+	var controls []ldap.Control
+	if !m.SDFlags.IsNull() && !m.SDFlags.IsUnknown() {
+		sdFlags := ldap.NewControlMicrosoftSDFlags()
+		sdFlags.ControlValue = int32(m.SDFlags.ValueInt64())
+		controls = append(controls, sdFlags)
+	}
+	return controls
+}
+
+// implicitObjectClasses stores the configured implicit_object_classes in classes, or their default.
+func (m LdapEntryResourceModel) implicitObjectClasses(ctx context.Context, classes *[]string) diag.Diagnostics {
+	if m.ImplicitObjectClasses.IsNull() || m.ImplicitObjectClasses.IsUnknown() {
+		*classes = defaultImplicitObjectClasses
+		return nil
+	}
+	return m.ImplicitObjectClasses.ElementsAs(ctx, classes, false)
+}
+
+// waitForReadConsistency polls the entry until the configured attribute reflects the written values.
+// Failing to observe the values is reported as a warning since the write itself succeeded.
+func (r *LdapEntryResource) waitForReadConsistency(ctx context.Context, dn string, rc *LdapEntryReadConsistencyModel, written map[string][]string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	attrName := rc.Attribute.ValueString()
+	key, ok := findAttribute(written, attrName, r.client.FoldsAttributeNames())
+	if !ok {
+		diags.AddAttributeError(
+			path.Root("read_consistency").AtName("attribute"),
+			"Invalid read consistency attribute",
+			fmt.Sprintf("Attribute %q must be managed in attributes to be used for read consistency.", attrName),
+		)
+		return diags
+	}
+
+	retries := int64(defaultReadConsistencyMaxRetries)
+	if !rc.MaxRetries.IsNull() {
+		retries = rc.MaxRetries.ValueInt64()
+	}
+
+	interval := defaultReadConsistencyRetryInterval
+	if !rc.RetryInterval.IsNull() {
+		d, err := time.ParseDuration(rc.RetryInterval.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("read_consistency").AtName("retry_interval"),
+				"Invalid read consistency retry interval",
+				fmt.Sprintf("Unable to parse %q as a duration: %s", rc.RetryInterval.ValueString(), err),
+			)
+			return diags
+		}
+		interval = d
+	}
+
+	// The server may return DNs in its canonical case and spacing
+	equal := func(a, b string) bool { return a == b }
+	if schema, err := r.client.Schema(ctx); err != nil {
+		tflog.Debug(ctx, fmt.Sprintf("Unable to read the schema, comparing values of %s exactly: %s", attrName, err))
+	} else if schema.Syntax(attrName) == syntaxDN {
+		equal = equalDN
+	}
+
+	consistent, err := WaitForAttributeValues(ctx, r.client, dn, attrName, written[key], equal, int(retries), interval)
+	if err != nil {
+		diags.AddError(
+			"Error verifying LDAP entry consistency",
+			fmt.Sprintf("Unable to read back attribute %s on %s: %s", attrName, dn, err),
+		)
+		return diags
+	}
+
+	if !consistent {
+		diags.AddWarning(
+			"LDAP entry not yet consistent",
+			fmt.Sprintf("Attribute %s on %s did not reflect the written values after %d retries. Subsequent reads may show drift until the directory converges.", attrName, dn, retries),
+		)
+	}
+
+	return diags
+}
+
+// waitForCreatedEntry waits up to create_visibility_timeout for the entry dn, just created, to be
+// readable, and warns if it is not.
+func (r *LdapEntryResource) waitForCreatedEntry(ctx context.Context, dn string, plan LdapEntryResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	timeout := defaultCreateVisibilityTimeout
+	if !plan.CreateVisibilityTimeout.IsNull() {
+		d, err := time.ParseDuration(plan.CreateVisibilityTimeout.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("create_visibility_timeout"),
+				"Invalid create visibility timeout",
+				fmt.Sprintf("Unable to parse %q as a duration: %s", plan.CreateVisibilityTimeout.ValueString(), err),
+			)
+			return diags
+		}
+		timeout = d
+	}
+	if timeout <= 0 {
+		return diags
+	}
+
+	visible, err := WaitForEntry(ctx, r.client, dn, LdapSearchOptions{Controls: plan.controls()}, timeout, createVisibilityRetryInterval)
+	if err != nil {
+		diags.AddError(
+			"Error verifying LDAP entry",
+			fmt.Sprintf("LDAP entry %s was created but could not be read back: %s", dn, err),
+		)
+		return diags
+	}
+
+	if !visible {
+		diags.AddWarning(
+			"LDAP entry not yet visible",
+			fmt.Sprintf("LDAP entry %s was created but could not be found within create_visibility_timeout (%s). "+
+				"If the directory has not replicated it yet, the next refresh may report it as deleted.", dn, timeout),
+		)
+	}
+
+	return diags
+}
+
 // AttributesSetSemanticsModifier is a plan modifier that treats list values as sets (order-independent).
 // This is necessary because LDAP returns multi-valued attributes in arbitrary order.
 type AttributesSetSemanticsModifier struct{}
@@ -438,23 +1524,44 @@ func (m AttributesSetSemanticsModifier) PlanModifyMap(ctx context.Context, req p
 		return
 	}
 
+	// If all attributes are equal as sets, use state value to prevent spurious diff
+	// Otherwise leave plan as-is (Terraform will use config value)
+	if equal, diags := attributesEqualAsSets(ctx, req.ConfigValue, req.StateValue, false); !diags.HasError() && equal {
+		resp.PlanValue = req.StateValue
+	}
+}
+
+// attributesEqualAsSets reports whether the configured attributes match those in state, comparing
+// values as sets. Null attributes in config are ignored (treated as if not present), unless they are
+// managed in state. With foldCase, attribute names differing only in case are the same attribute.
+func attributesEqualAsSets(ctx context.Context, configValue types.Map, stateValue types.Map, foldCase bool) (bool, diag.Diagnostics) {
 	// Extract config and state as maps of lists
 	var configMap map[string]types.List
 	var stateMap map[string]types.List
 
-	diags := req.ConfigValue.ElementsAs(ctx, &configMap, false)
+	diags := configValue.ElementsAs(ctx, &configMap, false)
 	if diags.HasError() {
-		return
+		return false, diags
 	}
 
-	diags = req.StateValue.ElementsAs(ctx, &stateMap, false)
+	diags = stateValue.ElementsAs(ctx, &stateMap, false)
 	if diags.HasError() {
-		return
+		return false, diags
 	}
 
-	// Check if all attributes are equal as sets
-	// Null attributes in config are ignored (treated as if not present)
-	allEqual := true
+	lookup := func(m map[string]types.List, key string) (types.List, bool) {
+		if v, ok := m[key]; ok {
+			return v, true
+		}
+		if foldCase {
+			for k, v := range m {
+				if strings.EqualFold(k, key) {
+					return v, true
+				}
+			}
+		}
+		return types.List{}, false
+	}
 
 	// Compare non-null attributes from config against state
 	for key, configList := range configMap {
@@ -463,10 +1570,9 @@ func (m AttributesSetSemanticsModifier) PlanModifyMap(ctx context.Context, req p
 			continue
 		}
 
-		stateList, ok := stateMap[key]
+		stateList, ok := lookup(stateMap, key)
 		if !ok {
-			allEqual = false
-			break
+			return false, diags
 		}
 
 		var configValues []string
@@ -474,45 +1580,228 @@ func (m AttributesSetSemanticsModifier) PlanModifyMap(ctx context.Context, req p
 
 		diags = configList.ElementsAs(ctx, &configValues, false)
 		if diags.HasError() {
-			return
+			return false, diags
 		}
 
 		diags = stateList.ElementsAs(ctx, &stateValues, false)
 		if diags.HasError() {
-			return
+			return false, diags
 		}
 
 		// Use order-independent comparison
 		if !stringSlicesEqual(configValues, stateValues) {
-			allEqual = false
-			break
+			return false, diags
 		}
 	}
 
 	// Check for managed → unmanaged transitions (non-null → null)
 	// and removed attributes
-	if allEqual {
-		for key, stateList := range stateMap {
-			configList, exists := configMap[key]
-			if !exists {
-				// State has an attribute that config doesn't have at all
-				allEqual = false
-				break
-			}
-			// Detect managed → unmanaged transition (non-null → null)
-			// This should generate a plan to delete the attribute
-			if !stateList.IsNull() && configList.IsNull() {
-				allEqual = false
-				break
+	for key, stateList := range stateMap {
+		configList, exists := lookup(configMap, key)
+		if !exists {
+			// State has an attribute that config doesn't have at all
+			return false, diags
+		}
+		// Detect managed → unmanaged transition (non-null → null)
+		// This should generate a plan to delete the attribute
+		if !stateList.IsNull() && configList.IsNull() {
+			return false, diags
+		}
+	}
+
+	return true, diags
+}
+
+// keepNonAuthoritativeValues returns the attributes read from the server with every attribute that
+// is not in authoritative replaced by its value in prior, so drift in it is not detected. Attributes
+// missing from prior, as on import, keep the value read.
+func keepNonAuthoritativeValues(read types.Map, prior map[string]types.List, authoritative []string, foldCase bool) (types.Map, diag.Diagnostics) {
+	values := make(map[string]attr.Value, len(read.Elements()))
+	for name, value := range read.Elements() {
+		values[name] = value
+		if isAuthoritativeAttribute(name, authoritative, foldCase) {
+			continue
+		}
+		if priorValue, exists := prior[name]; exists {
+			values[name] = priorValue
+		}
+	}
+	return types.MapValue(read.ElementType(context.Background()), values)
+}
+
+// keepPriorValueOrder returns read with the prior value list of each attribute whose read values
+// are the same set, so that only actual changes differ from state. LDAP does not order values, and
+// servers may return them in any order.
+func keepPriorValueOrder(ctx context.Context, read types.Map, prior map[string]types.List) (types.Map, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	values := make(map[string]attr.Value, len(read.Elements()))
+	for name, value := range read.Elements() {
+		values[name] = value
+
+		readList, ok := value.(types.List)
+		priorList, exists := prior[name]
+		if !ok || !exists || readList.IsNull() || priorList.IsNull() || priorList.IsUnknown() {
+			continue
+		}
+
+		var readValues, priorValues []string
+		diags.Append(readList.ElementsAs(ctx, &readValues, false)...)
+		diags.Append(priorList.ElementsAs(ctx, &priorValues, false)...)
+		if diags.HasError() {
+			return read, diags
+		}
+		if stringSlicesEqual(readValues, priorValues) {
+			values[name] = priorList
+		}
+	}
+
+	result, d := types.MapValue(read.ElementType(ctx), values)
+	diags.Append(d...)
+	return result, diags
+}
+
+// unicodeForms maps the values of normalize_unicode to normalization forms.
+var unicodeForms = map[string]norm.Form{
+	"nfc": norm.NFC,
+	"nfd": norm.NFD,
+}
+
+// unicodeForm returns the normalization form of normalize_unicode, if set.
+func (m LdapEntryResourceModel) unicodeForm() (norm.Form, bool) {
+	if m.NormalizeUnicode.IsNull() || m.NormalizeUnicode.IsUnknown() {
+		return 0, false
+	}
+	form, ok := unicodeForms[m.NormalizeUnicode.ValueString()]
+	return form, ok
+}
+
+// normalizeAttributeValues converts every value of attributes to form. Base64-encoded binary values
+// are ASCII, which no normalization form changes.
+func normalizeAttributeValues(attributes map[string][]string, form norm.Form) {
+	for name, values := range attributes {
+		attributes[name] = normalizeValues(values, form)
+	}
+}
+
+// normalizeValues returns values converted to form.
+func normalizeValues(values []string, form norm.Form) []string {
+	normalized := make([]string, len(values))
+	for i, value := range values {
+		normalized[i] = form.String(value)
+	}
+	return normalized
+}
+
+// keepPriorNormalizedValues returns the attributes read from the server with the values of those
+// that equal their prior values once both are converted to form replaced by the prior values, so
+// that values the server stores in another normalization form than configured are not drift.
+func keepPriorNormalizedValues(ctx context.Context, read types.Map, prior map[string]types.List, form norm.Form) (types.Map, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	values := make(map[string]attr.Value, len(read.Elements()))
+	for name, value := range read.Elements() {
+		values[name] = value
+
+		readList, ok := value.(types.List)
+		priorList, exists := prior[name]
+		if !ok || !exists || readList.IsNull() || priorList.IsNull() || priorList.IsUnknown() {
+			continue
+		}
+
+		var readValues, priorValues []string
+		diags.Append(readList.ElementsAs(ctx, &readValues, false)...)
+		diags.Append(priorList.ElementsAs(ctx, &priorValues, false)...)
+		if diags.HasError() {
+			return read, diags
+		}
+		if stringSlicesEqual(normalizeValues(readValues, form), normalizeValues(priorValues, form)) {
+			values[name] = priorList
+		}
+	}
+
+	result, d := types.MapValue(read.ElementType(ctx), values)
+	diags.Append(d...)
+	return result, diags
+}
+
+// dropImplicitObjectClasses returns the attributes read from the server with the implicit classes
+// removed from objectClass, except those its value in prior lists. Without a prior objectClass, as
+// on import, every implicit class is removed.
+func dropImplicitObjectClasses(ctx context.Context, read types.Map, prior map[string]types.List, implicit []string) (types.Map, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	values := make(map[string]attr.Value, len(read.Elements()))
+	for name, value := range read.Elements() {
+		values[name] = value
+
+		readList, ok := value.(types.List)
+		if !ok || readList.IsNull() || !strings.EqualFold(attributeType(name), "objectClass") {
+			continue
+		}
+
+		var readClasses, priorClasses []string
+		diags.Append(readList.ElementsAs(ctx, &readClasses, false)...)
+		if priorList, exists := prior[name]; exists && !priorList.IsNull() && !priorList.IsUnknown() {
+			diags.Append(priorList.ElementsAs(ctx, &priorClasses, false)...)
+		}
+		if diags.HasError() {
+			return read, diags
+		}
+
+		var dropped []string
+		for _, class := range implicit {
+			if !containsFold(priorClasses, class) {
+				dropped = append(dropped, class)
 			}
 		}
+		kept := withoutObjectClasses(readClasses, dropped)
+		if len(kept) == len(readClasses) {
+			continue
+		}
+
+		list, d := types.ListValueFrom(ctx, types.StringType, kept)
+		diags.Append(d...)
+		values[name] = list
 	}
 
-	// If all attributes are equal as sets, use state value to prevent spurious diff
-	// Otherwise leave plan as-is (Terraform will use config value)
-	if allEqual {
-		resp.PlanValue = req.StateValue
+	result, d := types.MapValue(read.ElementType(ctx), values)
+	diags.Append(d...)
+	return result, diags
+}
+
+// withoutObjectClasses returns classes without the ones in excluded, ignoring case.
+func withoutObjectClasses(classes []string, excluded []string) []string {
+	if len(excluded) == 0 {
+		return classes
+	}
+	kept := make([]string, 0, len(classes))
+	for _, class := range classes {
+		if !containsFold(excluded, class) {
+			kept = append(kept, class)
+		}
 	}
+	return kept
+}
+
+// containsFold reports whether values contains value, ignoring case.
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAuthoritativeAttribute reports whether name is one of authoritative.
+func isAuthoritativeAttribute(name string, authoritative []string, foldCase bool) bool {
+	for _, a := range authoritative {
+		if a == name || (foldCase && strings.EqualFold(a, name)) {
+			return true
+		}
+	}
+	return false
 }
 
 // Helper function to compare string slices as sets (order-independent).
@@ -538,6 +1827,57 @@ func stringSlicesEqual(a, b []string) bool {
 	return true
 }
 
+// decodeBinaryAttributes base64-decodes the values of attributes listed in binaryAttributes (a List[String]).
+func decodeBinaryAttributes(ctx context.Context, binaryAttributes types.List, attributes map[string][]string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if binaryAttributes.IsNull() || binaryAttributes.IsUnknown() {
+		return diags
+	}
+
+	var names []string
+	diags.Append(binaryAttributes.ElementsAs(ctx, &names, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	if err := DecodeBinaryAttributes(attributes, names); err != nil {
+		diags.AddError(
+			"Error decoding binary attribute",
+			err.Error(),
+		)
+	}
+
+	return diags
+}
+
+// removeWriteOnceAttributes deletes the attributes named in writeOnce, ignoring case, from
+// attributes, so that updates do not send them.
+func removeWriteOnceAttributes(ctx context.Context, writeOnce types.List, attributes map[string][]string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if writeOnce.IsNull() || writeOnce.IsUnknown() {
+		return diags
+	}
+
+	var names []string
+	diags.Append(writeOnce.ElementsAs(ctx, &names, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	for attr := range attributes {
+		for _, name := range names {
+			if strings.EqualFold(attr, name) {
+				delete(attributes, attr)
+				break
+			}
+		}
+	}
+
+	return diags
+}
+
 // unmarshalTerraformAttributes converts a Terraform Map type to map[string][]string.
 // Null values are ignored and not included in the output map.
 func unmarshalTerraformAttributes(ctx context.Context, tfMap *types.Map, attrs map[string][]string) diag.Diagnostics {
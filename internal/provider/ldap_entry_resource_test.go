@@ -5,6 +5,7 @@ package provider
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/go-ldap/ldap/v3"
@@ -45,6 +46,36 @@ func TestAccLdapEntryResource(t *testing.T) {
 				ImportStateVerify:       true,
 				ImportStateVerifyIgnore: []string{"attributes"},
 			},
+			// ImportState testing - empty attribute list imports no attributes
+			{
+				ResourceName:  "ldap_entry.test",
+				ImportState:   true,
+				ImportStateId: `{"dn": "cn=test,dc=example,dc=com", "attributes": []}`,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 {
+						return fmt.Errorf("expected 1 imported resource, got %d", len(states))
+					}
+					if n := states[0].Attributes["attributes.%"]; n != "0" {
+						return fmt.Errorf("expected no imported attributes, got %s", n)
+					}
+					return nil
+				},
+			},
+			// ImportState testing - operational attributes such as entryDN can be imported
+			{
+				ResourceName:  "ldap_entry.test",
+				ImportState:   true,
+				ImportStateId: `{"dn": "CN=Test, DC=Example, DC=Com", "attributes": ["entryDN"]}`,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 {
+						return fmt.Errorf("expected 1 imported resource, got %d", len(states))
+					}
+					if v := states[0].Attributes["attributes.entryDN.0"]; v != "cn=test,dc=example,dc=com" {
+						return fmt.Errorf("expected normalized entryDN, got %q", v)
+					}
+					return nil
+				},
+			},
 			// Update and Read testing - add attributes
 			{
 				Config: testAccLdapEntryResourceConfigUpdated("cn=test,dc=example,dc=com"),
@@ -208,6 +239,128 @@ func TestAccLdapEntryResource_EmptyAttributes(t *testing.T) {
 	})
 }
 
+func TestAccLdapEntryResource_EmptyAttributeOnCreate(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			// mail = [] on create is omitted from the entry without error
+			{
+				Config: testAccLdapEntryResourceConfigAttribute(`mail = []`),
+				Check:  testAccCheckLdapAttributeAbsent("ldap_entry.test_user", "mail"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"ldap_entry.test_user",
+						tfjsonpath.New("attributes").AtMapKey("mail"),
+						knownvalue.ListSizeExact(0),
+					),
+				},
+			},
+			// Re-applying the same configuration is a no-op
+			{
+				Config: testAccLdapEntryResourceConfigAttribute(`mail = []`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
+// testAccCheckLdapAttributeAbsent verifies on the server that the entry of resourceName has no attrName attribute.
+// testAccCheckLdapAttributeValues checks the values of an attribute on the server, bypassing state.
+func testAccCheckLdapAttributeValues(resourceName string, attrName string, expected []string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", resourceName)
+		}
+
+		conn, err := testAccDialLdap()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		searchReq := ldap.NewSearchRequest(
+			rs.Primary.ID,
+			ldap.ScopeBaseObject,
+			ldap.NeverDerefAliases,
+			0,
+			0,
+			false,
+			"(objectClass=*)",
+			[]string{attrName},
+			nil,
+		)
+
+		result, err := conn.Search(searchReq)
+		if err != nil {
+			return fmt.Errorf("error searching for entry %s: %w", rs.Primary.ID, err)
+		}
+
+		if len(result.Entries) == 0 {
+			return fmt.Errorf("LDAP entry %s not found", rs.Primary.ID)
+		}
+
+		if values := result.Entries[0].GetAttributeValues(attrName); !stringSlicesEqual(values, expected) {
+			return fmt.Errorf("attribute %s on %s = %v, want %v", attrName, rs.Primary.ID, values, expected)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckLdapAttributeAbsent(resourceName string, attrName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", resourceName)
+		}
+
+		conn, err := ldap.DialURL("ldap://localhost:3389")
+		if err != nil {
+			return fmt.Errorf("failed to connect to LDAP server: %w", err)
+		}
+		defer conn.Close()
+
+		err = conn.Bind("cn=Manager,dc=example,dc=com", "secret")
+		if err != nil {
+			return fmt.Errorf("failed to bind to LDAP server: %w", err)
+		}
+
+		searchReq := ldap.NewSearchRequest(
+			rs.Primary.ID,
+			ldap.ScopeBaseObject,
+			ldap.NeverDerefAliases,
+			0,
+			0,
+			false,
+			"(objectClass=*)",
+			[]string{attrName},
+			nil,
+		)
+
+		result, err := conn.Search(searchReq)
+		if err != nil {
+			return fmt.Errorf("error searching for entry %s: %w", rs.Primary.ID, err)
+		}
+
+		if len(result.Entries) == 0 {
+			return fmt.Errorf("LDAP entry %s not found", rs.Primary.ID)
+		}
+
+		if values := result.Entries[0].GetAttributeValues(attrName); len(values) > 0 {
+			return fmt.Errorf("attribute %s on %s = %v, want it absent", attrName, rs.Primary.ID, values)
+		}
+
+		return nil
+	}
+}
+
 func TestAccLdapEntryResource_MailAttributesTransition(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
@@ -251,6 +404,184 @@ func TestAccLdapEntryResource_MailAttributesTransition(t *testing.T) {
 	})
 }
 
+func TestAccLdapEntryResource_NarrowManagedAttributes(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapEntryResourceConfigAttribute(`mail = ["narrow@example.com"]
+    description = ["managed for now"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_entry.test_user", "attributes.description.0", "managed for now"),
+				),
+			},
+			// Dropping description from the configuration removes it from the entry and from state
+			{
+				Config: testAccLdapEntryResourceConfigAttribute(`mail = ["narrow@example.com"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("ldap_entry.test_user", "attributes.description.#"),
+					testAccCheckLdapAttributeAbsent("ldap_entry.test_user", "description"),
+				),
+			},
+			// A refresh must not bring it back
+			{
+				RefreshState: true,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("ldap_entry.test_user", "attributes.description.#"),
+					resource.TestCheckResourceAttr("ldap_entry.test_user", "attributes.%", "5"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccLdapEntryResource_MaxValueBytes(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapEntryResourceConfigProviderOnly() + `
+resource "ldap_entry" "too_large" {
+  dn = "cn=too-large,ou=users,dc=example,dc=com"
+  attributes = {
+    objectClass = ["person"]
+    cn = ["too-large"]
+    sn = ["Large"]
+    description = ["this description is far too long"]
+  }
+  max_value_bytes = 16
+}
+`,
+				ExpectError: regexp.MustCompile(`Attribute value too large`),
+			},
+		},
+	})
+}
+
+func TestAccLdapEntryResource_MaxConnectionAge(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			// Every request is older than the connection age, so each one runs on a new connection
+			{
+				Config: testAccLdapEntryResourceConfigMaxConnectionAge("first"),
+				Check:  testAccCheckLdapAttributeValues("ldap_entry.recycled", "description", []string{"first"}),
+			},
+			{
+				Config: testAccLdapEntryResourceConfigMaxConnectionAge("second"),
+				Check:  testAccCheckLdapAttributeValues("ldap_entry.recycled", "description", []string{"second"}),
+			},
+		},
+	})
+}
+
+func testAccLdapEntryResourceConfigMaxConnectionAge(description string) string {
+	return fmt.Sprintf(`
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+  max_connection_age = "1ms"
+}
+
+resource "ldap_entry" "recycled" {
+  dn = "cn=recycled,ou=users,dc=example,dc=com"
+  attributes = {
+    objectClass = ["person"]
+    cn = ["recycled"]
+    sn = ["Recycled"]
+    description = [%q]
+  }
+}
+`, description)
+}
+
+func TestAccLdapEntryResource_AuthoritativeAttributes(t *testing.T) {
+	modify := func(name string, value string) func() {
+		return func() {
+			conn, err := testAccDialLdap()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+
+			modifyReq := ldap.NewModifyRequest("cn=partial,ou=users,dc=example,dc=com", nil)
+			modifyReq.Replace(name, []string{value})
+			if err := conn.Modify(modifyReq); err != nil {
+				t.Fatalf("failed to modify %s: %v", name, err)
+			}
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapEntryResourceConfigAuthoritative("managed"),
+			},
+			// description is not authoritative, so a change made elsewhere is not drift
+			{
+				PreConfig: modify("description", "changed elsewhere"),
+				Config:    testAccLdapEntryResourceConfigAuthoritative("managed"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				Check: testAccCheckLdapAttributeValues("ldap_entry.partial", "description", []string{"changed elsewhere"}),
+			},
+			// Changing its configured value still writes it
+			{
+				Config: testAccLdapEntryResourceConfigAuthoritative("updated"),
+				Check:  testAccCheckLdapAttributeValues("ldap_entry.partial", "description", []string{"updated"}),
+			},
+			// mail is authoritative, so the same kind of change is corrected
+			{
+				PreConfig: modify("mail", "drifted@example.com"),
+				Config:    testAccLdapEntryResourceConfigAuthoritative("updated"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("ldap_entry.partial", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: testAccCheckLdapAttributeValues("ldap_entry.partial", "mail", []string{"partial@example.com"}),
+			},
+		},
+	})
+}
+
+func testAccLdapEntryResourceConfigAuthoritative(description string) string {
+	return fmt.Sprintf(`
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+resource "ldap_entry" "partial" {
+  dn = "cn=partial,ou=users,dc=example,dc=com"
+  attributes = {
+    objectClass = ["inetOrgPerson"]
+    cn = ["partial"]
+    sn = ["Partial"]
+    mail = ["partial@example.com"]
+    description = [%q]
+  }
+  authoritative_attributes = ["mail"]
+}
+`, description)
+}
+
+// The null attribute tests rely on the default missing_as_null = false, where a managed
+// attribute absent on the server is read back as [] rather than null.
 func TestAccLdapEntryResource_NullAttribute(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
@@ -389,7 +720,39 @@ func TestAccLdapEntryResource_NullAttributeTransition(t *testing.T) {
 		},
 	})
 }
-func testAccLdapEntryResourceConfigAttribute(attr string) string {
+func TestAccLdapEntryResource_ReadConsistency(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			// Create waits for mail to be readable
+			{
+				Config: testAccLdapEntryResourceConfigReadConsistency("consistent@example.com"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"ldap_entry.test_user",
+						tfjsonpath.New("attributes").AtMapKey("mail").AtSliceIndex(0),
+						knownvalue.StringExact("consistent@example.com"),
+					),
+				},
+			},
+			// Update waits for the new mail value
+			{
+				Config: testAccLdapEntryResourceConfigReadConsistency("updated@example.com"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"ldap_entry.test_user",
+						tfjsonpath.New("attributes").AtMapKey("mail").AtSliceIndex(0),
+						knownvalue.StringExact("updated@example.com"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccLdapEntryResourceConfigReadConsistency(mail string) string {
 	return fmt.Sprintf(`
 provider "ldap" {
   url = "ldap://localhost:3389"
@@ -404,33 +767,757 @@ resource "ldap_entry" "test_user" {
     cn = ["Test User"]
     sn = ["User"]
     uid = ["testuser"]
-    %s
+    mail = [%[1]q]
+  }
+
+  read_consistency = {
+    attribute      = "mail"
+    max_retries    = 3
+    retry_interval = "100ms"
   }
 }
-`, attr)
+`, mail)
 }
 
-func testAccCheckLdapEntryDestroy(s *terraform.State) error {
-	// Create LDAP connection to verify entries are destroyed
-	conn, err := ldap.DialURL("ldap://localhost:3389")
-	if err != nil {
-		return fmt.Errorf("failed to connect to LDAP server: %w", err)
-	}
-	defer conn.Close()
+func TestAccLdapEntryResource_ReadConsistencyInvalid(t *testing.T) {
+	config := func(readConsistency string) string {
+		return testAccLdapEntryResourceConfigProviderOnly() + `
+resource "ldap_entry" "test_user" {
+  dn = "uid=testuser,dc=example,dc=com"
+  attributes = {
+    objectClass = ["person", "organizationalPerson", "inetOrgPerson"]
+    cn = ["Test User"]
+    sn = ["User"]
+    uid = ["testuser"]
+  }
+  attributes_wo = {
+    userPassword = ["secret"]
+  }
+  attributes_wo_version = 1
 
-	// Bind to LDAP server
-	err = conn.Bind("cn=Manager,dc=example,dc=com", "secret")
-	if err != nil {
-		return fmt.Errorf("failed to bind to LDAP server: %w", err)
+  read_consistency = ` + readConsistency + `
+}
+`
 	}
 
-	// Check each resource in the state
-	for _, rs := range s.RootModule().Resources {
-		if rs.Type != "ldap_entry" {
-			continue
-		}
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Write-only values are not written on every apply
+			{
+				Config:      config(`{ attribute = "userPassword" }`),
+				ExpectError: regexp.MustCompile(`is set in attributes_wo`),
+			},
+			{
+				Config:      config(`{ attribute = "cn", max_retries = -1 }`),
+				ExpectError: regexp.MustCompile(`Value out of range`),
+			},
+		},
+	})
+}
 
-		dn := rs.Primary.ID
+func TestAccLdapEntryResource_ForceRecreate(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapEntryResourceConfigForceRecreate("1"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"ldap_entry.test_user",
+						tfjsonpath.New("force_recreate"),
+						knownvalue.StringExact("1"),
+					),
+				},
+			},
+			// Unchanged trigger plans nothing
+			{
+				Config: testAccLdapEntryResourceConfigForceRecreate("1"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			// Changed trigger replaces the entry
+			{
+				Config: testAccLdapEntryResourceConfigForceRecreate("2"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("ldap_entry.test_user", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"ldap_entry.test_user",
+						tfjsonpath.New("force_recreate"),
+						knownvalue.StringExact("2"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccLdapEntryResourceConfigForceRecreate(trigger string) string {
+	return fmt.Sprintf(`
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+resource "ldap_entry" "test_user" {
+  dn = "uid=testuser,dc=example,dc=com"
+  attributes = {
+    objectClass = ["person", "organizationalPerson", "inetOrgPerson"]
+    cn = ["Test User"]
+    sn = ["User"]
+    uid = ["testuser"]
+  }
+
+  force_recreate = %[1]q
+}
+`, trigger)
+}
+
+func TestAccLdapEntryResource_Rename(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapEntryResourceConfigRename("ou=users", "Old", `["Old"]`, ""),
+			},
+			// Changing only the RDN value would remove it from the entry, which needs a rename
+			{
+				Config:      testAccLdapEntryResourceConfigRename("ou=users", "Old", `["New"]`, ""),
+				ExpectError: regexp.MustCompile(`RDN value missing from attributes`),
+			},
+			// Changing the RDN renames the entry in place and removes the old RDN value
+			{
+				Config: testAccLdapEntryResourceConfigRename("ou=users", "New", `["New"]`, ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("ldap_entry.renamed", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"ldap_entry.renamed",
+						tfjsonpath.New("id"),
+						knownvalue.StringExact("cn=New,ou=users,dc=example,dc=com"),
+					),
+					statecheck.ExpectKnownValue(
+						"ldap_entry.renamed",
+						tfjsonpath.New("attributes").AtMapKey("cn"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("New")}),
+					),
+				},
+			},
+			// Changing the parent replaces the entry
+			{
+				Config: testAccLdapEntryResourceConfigRename("ou=groups", "New", `["New"]`, ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("ldap_entry.renamed", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccLdapEntryResource_RenameKeepOldRDN(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapEntryResourceConfigRename("ou=users", "Old", `["Old", "New"]`, "delete_old_rdn = false"),
+			},
+			// cn is unchanged in the configuration, so only the rename touches it. The old RDN
+			// value is kept, otherwise refreshing would show cn drifting to ["New"].
+			{
+				Config: testAccLdapEntryResourceConfigRename("ou=users", "New", `["Old", "New"]`, "delete_old_rdn = false"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("ldap_entry.renamed", plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"ldap_entry.renamed",
+						tfjsonpath.New("id"),
+						knownvalue.StringExact("cn=New,ou=users,dc=example,dc=com"),
+					),
+				},
+			},
+			// With the default, the same rename removes the old RDN value behind the unchanged configuration
+			{
+				Config:             testAccLdapEntryResourceConfigRename("ou=users", "Old", `["Old", "New"]`, ""),
+				ExpectNonEmptyPlan: true,
+				Check:              testAccCheckLdapAttributeValues("ldap_entry.renamed", "cn", []string{"Old"}),
+			},
+		},
+	})
+}
+
+func testAccLdapEntryResourceConfigRename(parent string, cn string, cnValues string, extra string) string {
+	return fmt.Sprintf(`
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+resource "ldap_entry" "renamed" {
+  dn = "cn=%[2]s,%[1]s,dc=example,dc=com"
+  attributes = {
+    objectClass = ["person"]
+    cn = %[3]s
+    sn = ["Renamed"]
+  }
+  %[4]s
+}
+`, parent, cn, cnValues, extra)
+}
+
+func testAccLdapEntryResourceConfigAttribute(attr string) string {
+	return fmt.Sprintf(`
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+resource "ldap_entry" "test_user" {
+  dn = "uid=testuser,dc=example,dc=com"
+  attributes = {
+    objectClass = ["person", "organizationalPerson", "inetOrgPerson"]
+    cn = ["Test User"]
+    sn = ["User"]
+    uid = ["testuser"]
+    %s
+  }
+}
+`, attr)
+}
+
+func TestAccLdapEntryResource_DeleteNonLeaf(t *testing.T) {
+	childDN := "cn=child,ou=nonleaf,dc=example,dc=com"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			// Create the parent, then add a child outside of Terraform
+			{
+				Config: testAccLdapEntryResourceConfigNonLeafParent(),
+				Check: testAccAddLdapEntry(childDN, map[string][]string{
+					"objectClass": {"organizationalRole"},
+					"cn":          {"child"},
+				}),
+			},
+			// Deleting the parent fails with a diagnostic explaining the entry has children
+			{
+				Config:      testAccLdapEntryResourceConfigProviderOnly(),
+				ExpectError: regexp.MustCompile(`still has entries beneath it`),
+			},
+			// Once the child is gone the parent can be deleted
+			{
+				PreConfig: func() {
+					if err := testAccDeleteLdapEntry(childDN); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccLdapEntryResourceConfigProviderOnly(),
+			},
+		},
+	})
+}
+
+func TestAccLdapEntryResource_VerifyDestroy(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapEntryResourceConfigProviderOnly() + `
+resource "ldap_entry" "verified" {
+  dn = "cn=verified,dc=example,dc=com"
+  attributes = {
+    objectClass = ["organizationalRole"]
+    cn = ["verified"]
+  }
+  verify_destroy = true
+}
+`,
+			},
+			// Removing the resource deletes the entry and confirms it is gone
+			{
+				Config: testAccLdapEntryResourceConfigProviderOnly(),
+			},
+		},
+	})
+}
+
+func TestAccLdapEntryResource_MemberBatches(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapEntryResourceConfigMemberBatches(1),
+			},
+			// 2500 members are added with three batched modify operations
+			{
+				Config: testAccLdapEntryResourceConfigMemberBatches(2500),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"ldap_entry.big_group",
+						tfjsonpath.New("attributes").AtMapKey("member"),
+						knownvalue.ListSizeExact(2500),
+					),
+				},
+			},
+			// Shrinking removes members in batches
+			{
+				Config: testAccLdapEntryResourceConfigMemberBatches(10),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"ldap_entry.big_group",
+						tfjsonpath.New("attributes").AtMapKey("member"),
+						knownvalue.ListSizeExact(10),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccLdapEntryResource_ImplicitObjectClass(t *testing.T) {
+	dn := "cn=implicit-top,dc=example,dc=com"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapEntryResourceConfigImplicitObjectClass(dn, ""),
+			},
+			// The server returns top, which is not configured: no difference
+			{
+				PreConfig: func() {
+					testAccSetLdapAttribute(t, dn, "objectClass", "top", "person")
+				},
+				Config: testAccLdapEntryResourceConfigImplicitObjectClass(dn, ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"ldap_entry.implicit",
+						tfjsonpath.New("attributes").AtMapKey("objectClass"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("person")}),
+					),
+				},
+			},
+			// Managing every object class shows top as drift
+			{
+				Config:             testAccLdapEntryResourceConfigImplicitObjectClass(dn, "implicit_object_classes = []"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccLdapEntryResourceConfigImplicitObjectClass(dn string, extra string) string {
+	return testAccLdapEntryResourceConfigProviderOnly() + fmt.Sprintf(`
+resource "ldap_entry" "implicit" {
+  dn = %q
+  attributes = {
+    objectClass = ["person"]
+    cn = ["implicit-top"]
+    sn = ["User"]
+  }
+  %s
+}
+`, dn, extra)
+}
+
+func TestAccLdapEntryResource_ReorderedValues(t *testing.T) {
+	dn := "cn=reordered,dc=example,dc=com"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapEntryResourceConfigReorderedValues(dn),
+			},
+			// The server returns the same values in another order: no difference
+			{
+				PreConfig: func() {
+					testAccSetLdapAttribute(t, dn, "mail", "c@example.com", "a@example.com", "b@example.com")
+				},
+				Config: testAccLdapEntryResourceConfigReorderedValues(dn),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"ldap_entry.reordered",
+						tfjsonpath.New("attributes").AtMapKey("mail"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact("a@example.com"),
+							knownvalue.StringExact("b@example.com"),
+							knownvalue.StringExact("c@example.com"),
+						}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccLdapEntryResourceConfigReorderedValues(dn string) string {
+	return testAccLdapEntryResourceConfigProviderOnly() + fmt.Sprintf(`
+resource "ldap_entry" "reordered" {
+  dn = %q
+  attributes = {
+    objectClass = ["inetOrgPerson"]
+    cn = ["reordered"]
+    sn = ["User"]
+    mail = ["a@example.com", "b@example.com", "c@example.com"]
+  }
+}
+`, dn)
+}
+
+func TestAccLdapEntryResource_DeleteBeforeAdd(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapEntryResourceConfigDeleteBeforeAdd(`mail = ["swap@example.com"]`),
+			},
+			// The value moves from mail to otherMailbox in one modify request, which removes it
+			// from mail first, as a uniqueness constraint on the value would require
+			{
+				Config: testAccLdapEntryResourceConfigDeleteBeforeAdd(`otherMailbox = ["swap@example.com"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckLdapAttributeAbsent("ldap_entry.swap", "mail"),
+					testAccCheckLdapAttributeValues("ldap_entry.swap", "otherMailbox", []string{"swap@example.com"}),
+				),
+			},
+		},
+	})
+}
+
+func testAccLdapEntryResourceConfigDeleteBeforeAdd(mailbox string) string {
+	return testAccLdapEntryResourceConfigProviderOnly() + fmt.Sprintf(`
+resource "ldap_entry" "swap" {
+  dn = "cn=swap,dc=example,dc=com"
+  attributes = {
+    objectClass = ["inetOrgPerson", "extensibleObject"]
+    cn = ["swap"]
+    sn = ["User"]
+    %s
+  }
+}
+`, mailbox)
+}
+
+func TestAccLdapEntryResource_AuxiliaryObjectClass(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapEntryResourceConfigAuxiliaryObjectClass(false),
+			},
+			// The auxiliary class and its attributes are added without replacing the structural class
+			{
+				Config: testAccLdapEntryResourceConfigAuxiliaryObjectClass(true),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("ldap_entry.aux", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckLdapAttributeValues("ldap_entry.aux", "objectClass", []string{"inetOrgPerson", "posixAccount"}),
+					testAccCheckLdapAttributeValues("ldap_entry.aux", "uidNumber", []string{"10002"}),
+				),
+			},
+			// And removed again
+			{
+				Config: testAccLdapEntryResourceConfigAuxiliaryObjectClass(false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckLdapAttributeValues("ldap_entry.aux", "objectClass", []string{"inetOrgPerson"}),
+					testAccCheckLdapAttributeAbsent("ldap_entry.aux", "uidNumber"),
+				),
+			},
+		},
+	})
+}
+
+func testAccLdapEntryResourceConfigAuxiliaryObjectClass(posix bool) string {
+	attributes := `
+    objectClass = ["inetOrgPerson"]
+    cn = ["Aux User"]
+    sn = ["User"]
+    uid = ["aux"]`
+	if posix {
+		attributes = `
+    objectClass = ["inetOrgPerson", "posixAccount"]
+    cn = ["Aux User"]
+    sn = ["User"]
+    uid = ["aux"]
+    uidNumber = ["10002"]
+    gidNumber = ["10002"]
+    homeDirectory = ["/home/aux"]`
+	}
+
+	return testAccLdapEntryResourceConfigProviderOnly() + fmt.Sprintf(`
+resource "ldap_entry" "aux" {
+  dn = "uid=aux,ou=users,dc=example,dc=com"
+  attributes = {%s
+  }
+}
+`, attributes)
+}
+
+func testAccLdapEntryResourceConfigMemberBatches(members int) string {
+	return testAccLdapEntryResourceConfigProviderOnly() + fmt.Sprintf(`
+resource "ldap_entry" "big_group" {
+  dn = "cn=big,ou=groups,dc=example,dc=com"
+  attributes = {
+    objectClass = ["groupOfNames"]
+    cn = ["big"]
+    member = [for i in range(%d) : "uid=user${i},ou=users,dc=example,dc=com"]
+  }
+  member_batch_size = 1000
+}
+`, members)
+}
+
+func TestAccLdapEntryResource_CaseInsensitiveAttributeNames(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			// The server returns objectClass and mail; state keeps the configured spelling without drift
+			{
+				Config: testAccLdapEntryResourceConfigAttributeCase("objectclass", "MAIL", ""),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"ldap_entry.case",
+						tfjsonpath.New("attributes").AtMapKey("MAIL"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("case@example.com")}),
+					),
+				},
+			},
+			// Changing only the case of attribute names plans no change
+			{
+				Config: testAccLdapEntryResourceConfigAttributeCase("objectClass", "mail", ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			// Two spellings of the same attribute are rejected
+			{
+				Config:      testAccLdapEntryResourceConfigAttributeCase("objectClass", "mail", `Mail = ["other@example.com"]`),
+				ExpectError: regexp.MustCompile(`Duplicate attribute`),
+			},
+		},
+	})
+}
+
+func testAccLdapEntryResourceConfigAttributeCase(objectClass string, mail string, extra string) string {
+	return testAccLdapEntryResourceConfigProviderOnly() + fmt.Sprintf(`
+resource "ldap_entry" "case" {
+  dn = "cn=case,ou=users,dc=example,dc=com"
+  attributes = {
+    %[1]s = ["inetOrgPerson"]
+    cn = ["case"]
+    sn = ["Case"]
+    %[2]s = ["case@example.com"]
+    %[3]s
+  }
+}
+`, objectClass, mail, extra)
+}
+
+func testAccLdapEntryResourceConfigNonLeafParent() string {
+	return testAccLdapEntryResourceConfigProviderOnly() + `
+resource "ldap_entry" "parent" {
+  dn = "ou=nonleaf,dc=example,dc=com"
+  attributes = {
+    objectClass = ["organizationalUnit"]
+    ou = ["nonleaf"]
+  }
+}
+`
+}
+
+func testAccLdapEntryResourceConfigProviderOnly() string {
+	return `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+`
+}
+
+// testAccDialLdap connects and binds to the test LDAP server.
+func testAccDialLdap() (*ldap.Conn, error) {
+	conn, err := ldap.DialURL("ldap://localhost:3389")
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to LDAP server: %w", err)
+	}
+
+	if err := conn.Bind("cn=Manager,dc=example,dc=com", "secret"); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to bind to LDAP server: %w", err)
+	}
+
+	return conn, nil
+}
+
+// testAccAddLdapEntry adds an entry directly on the server, bypassing Terraform.
+func testAccAddLdapEntry(dn string, attributes map[string][]string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		conn, err := testAccDialLdap()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		addReq := ldap.NewAddRequest(dn, nil)
+		for name, values := range attributes {
+			addReq.Attribute(name, values)
+		}
+
+		if err := conn.Add(addReq); err != nil {
+			return fmt.Errorf("failed to add LDAP entry %s: %w", dn, err)
+		}
+		return nil
+	}
+}
+
+// testAccDeleteLdapEntry deletes an entry directly on the server, bypassing Terraform.
+func testAccDeleteLdapEntry(dn string) error {
+	conn, err := testAccDialLdap()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.Del(ldap.NewDelRequest(dn, nil)); err != nil {
+		return fmt.Errorf("failed to delete LDAP entry %s: %w", dn, err)
+	}
+	return nil
+}
+
+func TestAccLdapEntryResource_ImportByUUID(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapEntryResourceConfig("cn=test-uuid,dc=example,dc=com"),
+			},
+			// The UUID is resolved to the DN of the entry
+			{
+				ResourceName:            "ldap_entry.test",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccLdapEntryUUID("cn=test-uuid,dc=example,dc=com"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"attributes"},
+			},
+		},
+	})
+}
+
+// testAccLdapEntryUUID returns an ImportStateIdFunc returning the entryUUID of the entry dn.
+func testAccLdapEntryUUID(dn string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		conn, err := ldap.DialURL("ldap://localhost:3389")
+		if err != nil {
+			return "", fmt.Errorf("failed to connect to LDAP server: %w", err)
+		}
+		defer conn.Close()
+
+		if err := conn.Bind("cn=Manager,dc=example,dc=com", "secret"); err != nil {
+			return "", fmt.Errorf("failed to bind to LDAP server: %w", err)
+		}
+
+		sr, err := conn.Search(ldap.NewSearchRequest(
+			dn,
+			ldap.ScopeBaseObject,
+			ldap.NeverDerefAliases,
+			0,
+			0,
+			false,
+			"(objectClass=*)",
+			[]string{"entryUUID"},
+			nil,
+		))
+		if err != nil {
+			return "", fmt.Errorf("failed to read entryUUID of %s: %w", dn, err)
+		}
+		if len(sr.Entries) != 1 || sr.Entries[0].GetAttributeValue("entryUUID") == "" {
+			return "", fmt.Errorf("entry %s has no entryUUID", dn)
+		}
+		return sr.Entries[0].GetAttributeValue("entryUUID"), nil
+	}
+}
+
+func testAccCheckLdapEntryDestroy(s *terraform.State) error {
+	// Create LDAP connection to verify entries are destroyed
+	conn, err := ldap.DialURL("ldap://localhost:3389")
+	if err != nil {
+		return fmt.Errorf("failed to connect to LDAP server: %w", err)
+	}
+	defer conn.Close()
+
+	// Bind to LDAP server
+	err = conn.Bind("cn=Manager,dc=example,dc=com", "secret")
+	if err != nil {
+		return fmt.Errorf("failed to bind to LDAP server: %w", err)
+	}
+
+	// Check each resource in the state
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "ldap_entry" {
+			continue
+		}
+
+		dn := rs.Primary.ID
 
 		// Search for the entry
 		searchReq := ldap.NewSearchRequest(
@@ -465,3 +1552,54 @@ func testAccCheckLdapEntryDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+func TestAccLdapEntryResource_ReadFilter(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			// The entry matches the restrictive filter, so it is read as usual and the plan stays empty
+			{
+				Config: testAccLdapEntryResourceConfigProviderOnly() + `
+resource "ldap_entry" "test" {
+  dn          = "cn=test-read-filter,dc=example,dc=com"
+  read_filter = "(&(objectClass=inetOrgPerson)(mail=test@example.com))"
+  attributes = {
+    objectClass = ["person", "organizationalPerson", "inetOrgPerson"]
+    cn          = ["test-read-filter"]
+    sn          = ["user"]
+    mail        = ["test@example.com"]
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_entry.test", "read_filter", "(&(objectClass=inetOrgPerson)(mail=test@example.com))"),
+					resource.TestCheckResourceAttr("ldap_entry.test", "attributes.mail.0", "test@example.com"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccLdapEntryResource_ReadFilterInvalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapEntryResourceConfigProviderOnly() + `
+resource "ldap_entry" "test" {
+  dn          = "cn=test-read-filter,dc=example,dc=com"
+  read_filter = "(objectClass=person"
+  attributes = {
+    objectClass = ["person"]
+    cn          = ["test-read-filter"]
+    sn          = ["user"]
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid filter`),
+			},
+		},
+	})
+}
@@ -4,7 +4,16 @@
 package provider
 
 import (
+	"context"
+	"encoding/base64"
+	"reflect"
 	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/text/unicode/norm"
 )
 
 func TestStringSlicesEqual(t *testing.T) {
@@ -191,3 +200,533 @@ func TestEncodeUnicodePwd(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessUnicodePwd(t *testing.T) {
+	expected, err := encodeUnicodePwd("S3cret!")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name     string
+		key      string
+		expected string
+	}{
+		{name: "unicodePwd", key: "unicodePwd", expected: expected},
+		{name: "different case", key: "unicodepwd", expected: expected},
+		{name: "with option", key: "unicodePwd;binary", expected: expected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Hybrid directories set both passwords in the same operation
+			attributes := map[string][]string{
+				tt.key:         {"S3cret!"},
+				"userPassword": {"{SSHA}hashed"},
+				"cn":           {"jdoe"},
+			}
+
+			if diags := ProcessUnicodePwd(attributes); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			if got := attributes[tt.key]; len(got) != 1 || got[0] != tt.expected {
+				t.Errorf("%s = %q, want UTF-16LE encoded password", tt.key, got)
+			}
+			if got := attributes["userPassword"]; len(got) != 1 || got[0] != "{SSHA}hashed" {
+				t.Errorf("userPassword = %q, want it passed through unchanged", got)
+			}
+			if got := attributes["cn"]; len(got) != 1 || got[0] != "jdoe" {
+				t.Errorf("cn = %q, want it passed through unchanged", got)
+			}
+		})
+	}
+}
+
+func TestLdapEntryResourceModelControls(t *testing.T) {
+	tests := []struct {
+		name          string
+		sdFlags       types.Int64
+		expectSDFlags bool
+		expectedValue int32
+	}{
+		{name: "unset", sdFlags: types.Int64Null(), expectSDFlags: false},
+		{name: "dacl only", sdFlags: types.Int64Value(4), expectSDFlags: true, expectedValue: 4},
+		{name: "owner group dacl", sdFlags: types.Int64Value(7), expectSDFlags: true, expectedValue: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controls := LdapEntryResourceModel{SDFlags: tt.sdFlags}.controls()
+
+			if !tt.expectSDFlags {
+				if len(controls) != 0 {
+					t.Errorf("expected no controls, got %v", controls)
+				}
+				return
+			}
+
+			if len(controls) != 1 {
+				t.Fatalf("expected 1 control, got %d", len(controls))
+			}
+
+			sdFlags, ok := controls[0].(*ldap.ControlMicrosoftSDFlags)
+			if !ok {
+				t.Fatalf("expected *ldap.ControlMicrosoftSDFlags, got %T", controls[0])
+			}
+			if sdFlags.ControlValue != tt.expectedValue {
+				t.Errorf("ControlValue = %d, want %d", sdFlags.ControlValue, tt.expectedValue)
+			}
+		})
+	}
+}
+
+func TestSameParentDN(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected bool
+	}{
+		{name: "rdn changed", a: "cn=Old,ou=users,dc=example,dc=com", b: "cn=New,ou=users,dc=example,dc=com", expected: true},
+		{name: "rdn attribute changed", a: "cn=jdoe,ou=users,dc=example,dc=com", b: "uid=jdoe,ou=users,dc=example,dc=com", expected: true},
+		{name: "parent case and spacing", a: "cn=Old,ou=users,dc=example,dc=com", b: "cn=New, OU=Users, DC=example, DC=com", expected: true},
+		{name: "escaped comma in rdn", a: `cn=Doe\, John,ou=users,dc=example,dc=com`, b: "cn=John Doe,ou=users,dc=example,dc=com", expected: true},
+		{name: "parent changed", a: "cn=Old,ou=users,dc=example,dc=com", b: "cn=Old,ou=groups,dc=example,dc=com", expected: false},
+		{name: "deeper parent", a: "cn=Old,ou=users,dc=example,dc=com", b: "cn=Old,ou=team,ou=users,dc=example,dc=com", expected: false},
+		{name: "top level", a: "dc=com", b: "dc=org", expected: true},
+		{name: "top level and nested", a: "dc=com", b: "dc=example,dc=com", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := sameParentDN(tt.a, tt.b); result != tt.expected {
+				t.Errorf("sameParentDN(%q, %q) = %v, want %v", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEqualDN(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected bool
+	}{
+		{name: "identical", a: "cn=John,ou=users,dc=example,dc=com", b: "cn=John,ou=users,dc=example,dc=com", expected: true},
+		{name: "attribute type case", a: "cn=John,ou=users,dc=example,dc=com", b: "CN=John,OU=users,DC=example,DC=com", expected: true},
+		{name: "value case", a: "cn=John,ou=users,dc=example,dc=com", b: "cn=JOHN,ou=Users,dc=Example,dc=COM", expected: true},
+		{name: "spaces after commas", a: "cn=John,ou=users,dc=example,dc=com", b: "cn=John, ou=users, dc=example, dc=com", expected: true},
+		{name: "escaped and hex values", a: `cn=Doe\, John,dc=example,dc=com`, b: `cn=Doe\2C John,dc=example,dc=com`, expected: true},
+		{name: "rdn value changed", a: "cn=John,ou=users,dc=example,dc=com", b: "cn=Jane,ou=users,dc=example,dc=com", expected: false},
+		{name: "parent changed", a: "cn=John,ou=users,dc=example,dc=com", b: "cn=John,ou=groups,dc=example,dc=com", expected: false},
+		{name: "unparseable", a: "not a dn", b: "NOT A DN", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := equalDN(tt.a, tt.b); result != tt.expected {
+				t.Errorf("equalDN(%q, %q) = %v, want %v", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMissingRDNValues(t *testing.T) {
+	tests := []struct {
+		name       string
+		dn         string
+		attributes map[string][]string
+		expected   []missingRDNValue
+	}{
+		{name: "present", dn: "cn=Old,ou=users,dc=example,dc=com", attributes: map[string][]string{"cn": {"Old"}}},
+		{name: "present among others", dn: "cn=Old,ou=users,dc=example,dc=com", attributes: map[string][]string{"cn": {"Alias", "Old"}}},
+		{name: "different case", dn: "CN=old,ou=users,dc=example,dc=com", attributes: map[string][]string{"cn": {"Old"}}},
+		{name: "not managed", dn: "cn=Old,ou=users,dc=example,dc=com", attributes: map[string][]string{"sn": {"Doe"}}},
+		{name: "escaped value", dn: `cn=Doe\, John,ou=users,dc=example,dc=com`, attributes: map[string][]string{"cn": {"Doe, John"}}},
+		{
+			name:       "value changed",
+			dn:         "cn=Old,ou=users,dc=example,dc=com",
+			attributes: map[string][]string{"cn": {"New"}},
+			expected:   []missingRDNValue{{Name: "cn", Type: "cn", Value: "Old"}},
+		},
+		{
+			name:       "emptied",
+			dn:         "cn=Old,ou=users,dc=example,dc=com",
+			attributes: map[string][]string{"CN": {}},
+			expected:   []missingRDNValue{{Name: "CN", Type: "cn", Value: "Old"}},
+		},
+		{
+			name:       "multi-valued rdn",
+			dn:         "cn=Old+uid=jdoe,ou=users,dc=example,dc=com",
+			attributes: map[string][]string{"cn": {"Old"}, "uid": {"john"}},
+			expected:   []missingRDNValue{{Name: "uid", Type: "uid", Value: "jdoe"}},
+		},
+		{name: "parent values ignored", dn: "cn=Old,ou=users,dc=example,dc=com", attributes: map[string][]string{"cn": {"Old"}, "ou": {"people"}}},
+		{name: "invalid dn", dn: "not a dn", attributes: map[string][]string{"cn": {"Old"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := missingRDNValues(tt.dn, tt.attributes); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("missingRDNValues(%q) = %v, want %v", tt.dn, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestKeepNonAuthoritativeValues(t *testing.T) {
+	list := func(values ...string) types.List {
+		elements := make([]attr.Value, len(values))
+		for i, v := range values {
+			elements[i] = types.StringValue(v)
+		}
+		return types.ListValueMust(types.StringType, elements)
+	}
+	read := types.MapValueMust(types.ListType{ElemType: types.StringType}, map[string]attr.Value{
+		"mail":        list("drifted@example.com"),
+		"description": list("changed elsewhere"),
+		"cn":          list("jdoe"),
+	})
+	prior := map[string]types.List{
+		"mail":        list("jdoe@example.com"),
+		"description": list("managed"),
+	}
+
+	tests := []struct {
+		name          string
+		authoritative []string
+		foldCase      bool
+		expected      map[string]types.List
+	}{
+		{
+			name:          "only mail is authoritative",
+			authoritative: []string{"mail"},
+			expected:      map[string]types.List{"mail": list("drifted@example.com"), "description": list("managed"), "cn": list("jdoe")},
+		},
+		{
+			name:          "none is authoritative",
+			authoritative: []string{},
+			expected:      map[string]types.List{"mail": list("jdoe@example.com"), "description": list("managed"), "cn": list("jdoe")},
+		},
+		{
+			name:          "different case, folded",
+			authoritative: []string{"MAIL"},
+			foldCase:      true,
+			expected:      map[string]types.List{"mail": list("drifted@example.com"), "description": list("managed"), "cn": list("jdoe")},
+		},
+		{
+			name:          "different case, exact",
+			authoritative: []string{"MAIL"},
+			foldCase:      false,
+			expected:      map[string]types.List{"mail": list("jdoe@example.com"), "description": list("managed"), "cn": list("jdoe")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, diags := keepNonAuthoritativeValues(read, prior, tt.authoritative, tt.foldCase)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+
+			expected := make(map[string]attr.Value, len(tt.expected))
+			for name, value := range tt.expected {
+				expected[name] = value
+			}
+			if want := types.MapValueMust(types.ListType{ElemType: types.StringType}, expected); !result.Equal(want) {
+				t.Errorf("keepNonAuthoritativeValues() = %s, want %s", result, want)
+			}
+		})
+	}
+}
+
+func TestKeepPriorValueOrder(t *testing.T) {
+	list := func(values ...string) types.List {
+		elements := make([]attr.Value, len(values))
+		for i, v := range values {
+			elements[i] = types.StringValue(v)
+		}
+		return types.ListValueMust(types.StringType, elements)
+	}
+	read := types.MapValueMust(types.ListType{ElemType: types.StringType}, map[string]attr.Value{
+		"objectClass":     list("top", "person", "inetOrgPerson"),
+		"mail":            list("b@example.com", "a@example.com", "c@example.com"),
+		"description":     list("changed elsewhere"),
+		"telephoneNumber": types.ListNull(types.StringType),
+		"cn":              list("jdoe"),
+	})
+	prior := map[string]types.List{
+		"objectClass":     list("inetOrgPerson", "person", "top"),
+		"mail":            list("a@example.com", "b@example.com"),
+		"description":     list("managed"),
+		"telephoneNumber": list("+1 555 0100"),
+	}
+
+	result, diags := keepPriorValueOrder(context.Background(), read, prior)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	want := types.MapValueMust(types.ListType{ElemType: types.StringType}, map[string]attr.Value{
+		// Reordered: unchanged
+		"objectClass": list("inetOrgPerson", "person", "top"),
+		// Changed: read values
+		"mail":            list("b@example.com", "a@example.com", "c@example.com"),
+		"description":     list("changed elsewhere"),
+		"telephoneNumber": types.ListNull(types.StringType),
+		// Not in prior state
+		"cn": list("jdoe"),
+	})
+	if !result.Equal(want) {
+		t.Errorf("keepPriorValueOrder() = %s, want %s", result, want)
+	}
+}
+
+// The same names as precomposed (NFC) and decomposed (NFD) characters.
+const (
+	nfcName = "Ren\u00e9e M\u00fcller"
+	nfdName = "Rene\u0301e Mu\u0308ller"
+)
+
+func TestNormalizeAttributeValues(t *testing.T) {
+	if nfcName == nfdName {
+		t.Fatal("expected the test strings to differ in their encoding")
+	}
+
+	tests := []struct {
+		form     norm.Form
+		expected string
+	}{
+		{form: norm.NFC, expected: nfcName},
+		{form: norm.NFD, expected: nfdName},
+	}
+
+	for _, tt := range tests {
+		attributes := map[string][]string{
+			"cn":        {nfcName, nfdName},
+			"jpegPhoto": {"/9j/4AAQSkZJRg=="},
+		}
+		normalizeAttributeValues(attributes, tt.form)
+
+		if !reflect.DeepEqual(attributes["cn"], []string{tt.expected, tt.expected}) {
+			t.Errorf("form %v: cn = %q, want both values as %q", tt.form, attributes["cn"], tt.expected)
+		}
+		if !reflect.DeepEqual(attributes["jpegPhoto"], []string{"/9j/4AAQSkZJRg=="}) {
+			t.Errorf("form %v: expected base64 values to be unchanged, got %q", tt.form, attributes["jpegPhoto"])
+		}
+	}
+}
+
+func TestKeepPriorNormalizedValues(t *testing.T) {
+	list := func(values ...string) types.List {
+		elements := make([]attr.Value, len(values))
+		for i, v := range values {
+			elements[i] = types.StringValue(v)
+		}
+		return types.ListValueMust(types.StringType, elements)
+	}
+	// The server stores NFC, the configuration uses NFD
+	read := types.MapValueMust(types.ListType{ElemType: types.StringType}, map[string]attr.Value{
+		"cn":          list(nfcName),
+		"sn":          list("M\u00fcller-Schmidt"),
+		"description": types.ListNull(types.StringType),
+	})
+	prior := map[string]types.List{
+		"cn":          list(nfdName),
+		"sn":          list("Mu\u0308ller"),
+		"description": list("managed"),
+	}
+
+	result, diags := keepPriorNormalizedValues(context.Background(), read, prior, norm.NFC)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	want := types.MapValueMust(types.ListType{ElemType: types.StringType}, map[string]attr.Value{
+		// Equivalent: the configured spelling
+		"cn": list(nfdName),
+		// Changed: read values
+		"sn":          list("M\u00fcller-Schmidt"),
+		"description": types.ListNull(types.StringType),
+	})
+	if !result.Equal(want) {
+		t.Errorf("keepPriorNormalizedValues() = %s, want %s", result, want)
+	}
+}
+
+func TestDropImplicitObjectClasses(t *testing.T) {
+	list := func(values ...string) types.List {
+		elements := make([]attr.Value, len(values))
+		for i, v := range values {
+			elements[i] = types.StringValue(v)
+		}
+		return types.ListValueMust(types.StringType, elements)
+	}
+	read := func(classes types.List) types.Map {
+		return types.MapValueMust(types.ListType{ElemType: types.StringType}, map[string]attr.Value{
+			"objectClass": classes,
+			"description": list("top"),
+		})
+	}
+
+	tests := []struct {
+		name     string
+		read     types.List
+		prior    map[string]types.List
+		implicit []string
+		expected types.List
+	}{
+		{
+			name:     "added by the server",
+			read:     list("top", "person"),
+			prior:    map[string]types.List{"objectClass": list("person")},
+			implicit: []string{"top"},
+			expected: list("person"),
+		},
+		{
+			name:     "configured",
+			read:     list("top", "person"),
+			prior:    map[string]types.List{"objectClass": list("Top", "person")},
+			implicit: []string{"top"},
+			expected: list("top", "person"),
+		},
+		{
+			name:     "case differs",
+			read:     list("TOP", "person"),
+			prior:    map[string]types.List{"objectClass": list("person")},
+			implicit: []string{"top"},
+			expected: list("person"),
+		},
+		{
+			name:     "import",
+			read:     list("top", "person"),
+			prior:    nil,
+			implicit: []string{"top"},
+			expected: list("person"),
+		},
+		{
+			name:     "disabled",
+			read:     list("top", "person"),
+			prior:    map[string]types.List{"objectClass": list("person")},
+			implicit: []string{},
+			expected: list("top", "person"),
+		},
+		{
+			name:     "null",
+			read:     types.ListNull(types.StringType),
+			prior:    map[string]types.List{"objectClass": list("person")},
+			implicit: []string{"top"},
+			expected: types.ListNull(types.StringType),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, diags := dropImplicitObjectClasses(context.Background(), read(tt.read), tt.prior, tt.implicit)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			// Only objectClass is affected
+			if want := read(tt.expected); !result.Equal(want) {
+				t.Errorf("dropImplicitObjectClasses() = %s, want %s", result, want)
+			}
+		})
+	}
+}
+
+func TestWithoutObjectClasses(t *testing.T) {
+	got := withoutObjectClasses([]string{"top", "person", "TOP", "posixAccount"}, []string{"Top", "posixaccount"})
+	if want := []string{"person"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("withoutObjectClasses() = %q, want %q", got, want)
+	}
+}
+
+func TestValidateUniqueAttributeNames(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes map[string][]string
+		errors     int
+	}{
+		{name: "unique", attributes: map[string][]string{"cn": {"a"}, "mail": {"b"}}, errors: 0},
+		{name: "case duplicate", attributes: map[string][]string{"mail": {"a"}, "Mail": {"b"}}, errors: 1},
+		{name: "three spellings", attributes: map[string][]string{"mail": {"a"}, "Mail": {"b"}, "MAIL": {"c"}}, errors: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attributes, diags := types.MapValueFrom(context.Background(), types.ListType{ElemType: types.StringType}, tt.attributes)
+			if diags.HasError() {
+				t.Fatalf("unable to build attributes: %v", diags)
+			}
+
+			if got := validateUniqueAttributeNames(attributes).ErrorsCount(); got != tt.errors {
+				t.Errorf("validateUniqueAttributeNames() reported %d errors, want %d", got, tt.errors)
+			}
+		})
+	}
+}
+
+func TestAttributesEqualAsSets(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string][]string
+		state    map[string][]string
+		foldCase bool
+		expected bool
+	}{
+		{name: "reordered values", config: map[string][]string{"cn": {"a", "b"}}, state: map[string][]string{"cn": {"b", "a"}}, expected: true},
+		{name: "different values", config: map[string][]string{"cn": {"a"}}, state: map[string][]string{"cn": {"b"}}, expected: false},
+		{name: "case differs, exact", config: map[string][]string{"objectclass": {"person"}}, state: map[string][]string{"objectClass": {"person"}}, expected: false},
+		{name: "case differs, folded", config: map[string][]string{"objectclass": {"person"}}, state: map[string][]string{"objectClass": {"person"}}, foldCase: true, expected: true},
+		{name: "case differs, folded, different values", config: map[string][]string{"objectclass": {"person"}}, state: map[string][]string{"objectClass": {"top"}}, foldCase: true, expected: false},
+		{name: "removed attribute, folded", config: map[string][]string{"cn": {"a"}}, state: map[string][]string{"cn": {"a"}, "sn": {"b"}}, foldCase: true, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, _ := types.MapValueFrom(context.Background(), types.ListType{ElemType: types.StringType}, tt.config)
+			state, _ := types.MapValueFrom(context.Background(), types.ListType{ElemType: types.StringType}, tt.state)
+
+			equal, diags := attributesEqualAsSets(context.Background(), config, state, tt.foldCase)
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+			if equal != tt.expected {
+				t.Errorf("attributesEqualAsSets() = %v, want %v", equal, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOversizedValues(t *testing.T) {
+	photo := base64.StdEncoding.EncodeToString([]byte("0123456789"))
+
+	tests := []struct {
+		name             string
+		attributes       map[string][]string
+		binaryAttributes []string
+		limit            int
+		errors           int
+	}{
+		{name: "within limit", attributes: map[string][]string{"cn": {"short"}, "sn": {"tenletters"}}, limit: 10, errors: 0},
+		{name: "too large", attributes: map[string][]string{"description": {"eleven char"}}, limit: 10, errors: 1},
+		{name: "each value counted", attributes: map[string][]string{"mail": {"a@example.com", "b@example.com", "c"}}, limit: 10, errors: 2},
+		{name: "binary measured decoded", attributes: map[string][]string{"jpegPhoto": {photo}}, binaryAttributes: []string{"jpegPhoto"}, limit: 10, errors: 0},
+		{name: "binary too large", attributes: map[string][]string{"jpegPhoto": {photo}}, binaryAttributes: []string{"jpegPhoto"}, limit: 9, errors: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attributes, diags := types.MapValueFrom(context.Background(), types.ListType{ElemType: types.StringType}, tt.attributes)
+			if diags.HasError() {
+				t.Fatalf("unable to build attributes: %v", diags)
+			}
+
+			if got := oversizedValues(path.Root("attributes"), attributes, tt.binaryAttributes, tt.limit).ErrorsCount(); got != tt.errors {
+				t.Errorf("oversizedValues() reported %d errors, want %d", got, tt.errors)
+			}
+		})
+	}
+}
@@ -4,12 +4,17 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"reflect"
+	"regexp"
 	"testing"
 
 	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/statecheck"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
@@ -125,6 +130,296 @@ resource "ldap_entry" "test_writeonly" {
 `, dn, password, version)
 }
 
+func TestAccLdapEntryResource_WriteOnlyAlways(t *testing.T) {
+	dn := "cn=writeonly-always,dc=example,dc=com"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapEntryResourceConfigWriteOnlyAlways(dn, "secret123"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("ldap_entry.test_writeonly_always", "attributes_wo"),
+					resource.TestCheckResourceAttrSet("ldap_entry.test_writeonly_always", "attributes_wo_written_at"),
+					testAccCheckLdapBind(dn, "secret123"),
+				),
+				// Every plan shows an update, including the one after apply
+				ExpectNonEmptyPlan: true,
+			},
+			// The password is sent again although neither it nor the version changed
+			{
+				PreConfig: func() {
+					testAccSetLdapAttribute(t, dn, "userPassword", "changed-elsewhere")
+				},
+				Config: testAccLdapEntryResourceConfigWriteOnlyAlways(dn, "secret123"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("ldap_entry.test_writeonly_always", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckLdapBind(dn, "secret123"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccLdapEntryResourceConfigWriteOnlyAlways(dn, password string) string {
+	return fmt.Sprintf(`
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+resource "ldap_entry" "test_writeonly_always" {
+  dn = %[1]q
+  attributes = {
+    objectClass = ["person", "organizationalPerson", "inetOrgPerson"]
+    cn = ["writeonly-always"]
+    sn = ["User"]
+  }
+  attributes_wo = {
+    userPassword = [%[2]q]
+  }
+  attributes_wo_always = true
+}
+`, dn, password)
+}
+
+func TestAccLdapEntryResource_WriteOnce(t *testing.T) {
+	dn := "cn=writeonce,dc=example,dc=com"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapEntryResourceConfigWriteOnce(dn, "initial123", "First", 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("ldap_entry.test_writeonce", "attributes_wo"),
+					resource.TestCheckResourceAttrSet("ldap_entry.test_writeonce", "attributes_wo_written_at"),
+					testAccCheckLdapBind(dn, "initial123"),
+				),
+			},
+			// The user changed their password: no drift is detected
+			{
+				PreConfig: func() {
+					testAccSetLdapAttribute(t, dn, "userPassword", "chosen-by-user")
+				},
+				Config: testAccLdapEntryResourceConfigWriteOnce(dn, "initial123", "First", 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			// A new version sends the other write-only attributes, but not the password
+			{
+				Config: testAccLdapEntryResourceConfigWriteOnce(dn, "initial456", "Second", 2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckLdapBind(dn, "chosen-by-user"),
+					testAccCheckLdapAttributeValues("ldap_entry.test_writeonce", "description", []string{"Second"}),
+				),
+			},
+		},
+	})
+}
+
+func testAccLdapEntryResourceConfigWriteOnce(dn, password, description string, version int) string {
+	return fmt.Sprintf(`
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+resource "ldap_entry" "test_writeonce" {
+  dn = %[1]q
+  attributes = {
+    objectClass = ["person", "organizationalPerson", "inetOrgPerson"]
+    cn = ["writeonce"]
+    sn = ["User"]
+  }
+  attributes_wo = {
+    userPassword = [%[2]q]
+    description = [%[3]q]
+  }
+  attributes_wo_version = %[4]d
+  write_once_attributes = ["userPassword"]
+}
+`, dn, password, description, version)
+}
+
+func TestAccLdapEntryResource_WriteOnceUnknownAttribute(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+resource "ldap_entry" "test_writeonce_unknown" {
+  dn = "cn=writeonce-unknown,dc=example,dc=com"
+  attributes = {
+    objectClass = ["person"]
+    cn = ["writeonce-unknown"]
+    sn = ["User"]
+  }
+  attributes_wo = {
+    userPassword = ["initial123"]
+  }
+  write_once_attributes = ["unicodePwd"]
+}
+`,
+				ExpectError: regexp.MustCompile(`Unknown write-once attribute`),
+			},
+		},
+	})
+}
+
+func TestRemoveWriteOnceAttributes(t *testing.T) {
+	ctx := context.Background()
+	attributes := map[string][]string{
+		"userPassword": {"initial123"},
+		"description":  {"Rotated"},
+	}
+
+	writeOnce, _ := types.ListValueFrom(ctx, types.StringType, []string{"USERPASSWORD"})
+	if diags := removeWriteOnceAttributes(ctx, writeOnce, attributes); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	expected := map[string][]string{"description": {"Rotated"}}
+	if !reflect.DeepEqual(attributes, expected) {
+		t.Errorf("attributes = %v, want %v", attributes, expected)
+	}
+
+	if diags := removeWriteOnceAttributes(ctx, types.ListNull(types.StringType), attributes); diags.HasError() || len(attributes) != 1 {
+		t.Errorf("expected a null list to leave attributes unchanged, got %v", attributes)
+	}
+}
+
+// testAccCheckLdapBind checks that dn can bind with password.
+func testAccCheckLdapBind(dn, password string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		conn, err := ldap.DialURL("ldap://localhost:3389")
+		if err != nil {
+			return fmt.Errorf("failed to connect to LDAP server: %w", err)
+		}
+		defer conn.Close()
+
+		if err := conn.Bind(dn, password); err != nil {
+			return fmt.Errorf("unable to bind as %s: %w", dn, err)
+		}
+		return nil
+	}
+}
+
+// testAccSetLdapAttribute replaces the values of an attribute outside Terraform.
+func testAccSetLdapAttribute(t *testing.T, dn, attrName string, values ...string) {
+	conn, err := ldap.DialURL("ldap://localhost:3389")
+	if err != nil {
+		t.Fatalf("failed to connect to LDAP server: %s", err)
+	}
+	defer conn.Close()
+
+	if err := conn.Bind("cn=Manager,dc=example,dc=com", "secret"); err != nil {
+		t.Fatalf("failed to bind to LDAP server: %s", err)
+	}
+
+	modifyReq := ldap.NewModifyRequest(dn, nil)
+	modifyReq.Replace(attrName, values)
+	if err := conn.Modify(modifyReq); err != nil {
+		t.Fatalf("failed to modify %s: %s", dn, err)
+	}
+}
+
+func TestAccLdapEntryResource_WriteOnlyConflict(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccLdapEntryResourceConfigWriteOnlyConflict(),
+				ExpectError: regexp.MustCompile(`Conflicting attribute`),
+			},
+		},
+	})
+}
+
+func testAccLdapEntryResourceConfigWriteOnlyConflict() string {
+	return `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+resource "ldap_entry" "test_conflict" {
+  dn = "cn=wo-conflict,dc=example,dc=com"
+  attributes = {
+    objectClass = ["person", "organizationalPerson", "inetOrgPerson"]
+    cn = ["wo-conflict"]
+    sn = ["User"]
+    userPassword = ["plain"]
+  }
+  attributes_wo = {
+    userPassword = ["secret"]
+  }
+  attributes_wo_version = 1
+}
+`
+}
+
+// The conflict is only known during apply, when config validation has already passed: no value
+// takes precedence, the apply fails before writing.
+func TestAccLdapEntryResource_WriteOnlyConflictUnknownUntilApply(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccLdapEntryResourceConfigWriteOnlyConflictUnknownUntilApply(),
+				ExpectError: regexp.MustCompile(`Conflicting attribute`),
+			},
+		},
+	})
+}
+
+func testAccLdapEntryResourceConfigWriteOnlyConflictUnknownUntilApply() string {
+	return testAccLdapEntryResourceConfigProviderOnly() + `
+resource "terraform_data" "attributes" {
+  input = {
+    objectClass = ["person", "organizationalPerson", "inetOrgPerson"]
+    cn = ["wo-conflict-apply"]
+    sn = ["User"]
+    userpassword = ["plain"]
+  }
+}
+
+resource "ldap_entry" "test_conflict" {
+  dn = "cn=wo-conflict-apply,dc=example,dc=com"
+  attributes = terraform_data.attributes.output
+  attributes_wo = {
+    userPassword = ["secret"]
+  }
+  attributes_wo_version = 1
+}
+`
+}
+
 // testAccCheckLdapAttributeExists checks if a specific attribute exists on an LDAP entry.
 func testAccCheckLdapAttributeExists(resourceName, attrName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
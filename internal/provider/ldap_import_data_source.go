@@ -0,0 +1,293 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &LdapImportDataSource{}
+
+func NewLdapImportDataSource() datasource.DataSource {
+	return &LdapImportDataSource{}
+}
+
+// LdapImportDataSource defines the data source implementation.
+type LdapImportDataSource struct {
+	conn *LdapClient
+}
+
+// LdapImportDataSourceModel describes the data source data model.
+type LdapImportDataSourceModel struct {
+	BaseDN       types.String `tfsdk:"basedn"`
+	Scope        types.String `tfsdk:"scope"`
+	Filter       types.String `tfsdk:"filter"`
+	Attributes   types.List   `tfsdk:"attributes"`
+	Entries      types.List   `tfsdk:"entries"`
+	ImportBlocks types.String `tfsdk:"import_blocks"`
+}
+
+// LdapImportEntryModel describes a single entry to import.
+type LdapImportEntryModel struct {
+	DN           types.String `tfsdk:"dn"`
+	ResourceName types.String `tfsdk:"resource_name"`
+	ImportID     types.String `tfsdk:"import_id"`
+	ImportBlock  types.String `tfsdk:"import_block"`
+}
+
+func (d *LdapImportDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_import"
+}
+
+func (d *LdapImportDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Generates `import` blocks for adopting existing entries into `ldap_entry` resources. " +
+			"Write `import_blocks` to a file, then run `terraform plan -generate-config-out=generated.tf` to have Terraform generate the matching resource configuration.",
+
+		Attributes: map[string]schema.Attribute{
+			"basedn": schema.StringAttribute{
+				MarkdownDescription: "Base DN of the subtree to import. Relative to the provider `base_dn` if it does not already end with it.",
+				Required:            true,
+			},
+			"scope": schema.StringAttribute{
+				MarkdownDescription: "Search scope, one of 'base', 'one', or 'sub'. Defaults to 'sub'.",
+				Optional:            true,
+			},
+			"filter": schema.StringAttribute{
+				MarkdownDescription: "Filter selecting the entries to import. Defaults to `(objectClass=*)`.",
+				Optional:            true,
+			},
+			"attributes": schema.ListAttribute{
+				MarkdownDescription: "Attributes to import for each entry. When set, `import_id` is the JSON form `{\"dn\": ..., \"attributes\": [...]}` accepted by `ldap_entry` import; otherwise it is the plain DN and only `objectClass` is imported.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"entries": schema.ListNestedAttribute{
+				MarkdownDescription: "Matching entries, sorted by DN.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"dn": schema.StringAttribute{
+							MarkdownDescription: "The distinguished name of the entry.",
+							Computed:            true,
+						},
+						"resource_name": schema.StringAttribute{
+							MarkdownDescription: "Suggested `ldap_entry` resource name, derived from the first RDN value and unique within `entries`.",
+							Computed:            true,
+						},
+						"import_id": schema.StringAttribute{
+							MarkdownDescription: "Import ID for the entry.",
+							Computed:            true,
+						},
+						"import_block": schema.StringAttribute{
+							MarkdownDescription: "An `import` block importing the entry into `ldap_entry.<resource_name>`.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"import_blocks": schema.StringAttribute{
+				MarkdownDescription: "The `import_block` of every entry, concatenated.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *LdapImportDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.conn = GetLdapConnection(req.ProviderData, &resp.Diagnostics, "Data Source")
+}
+
+func (d *LdapImportDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data LdapImportDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	scope := "sub"
+	if !data.Scope.IsNull() {
+		scope = data.Scope.ValueString()
+	}
+
+	filter := "(objectClass=*)"
+	if !data.Filter.IsNull() {
+		filter = data.Filter.ValueString()
+	}
+
+	var attributes []string
+	if !data.Attributes.IsNull() {
+		resp.Diagnostics.Append(data.Attributes.ElementsAs(ctx, &attributes, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	baseDN := d.conn.ResolveDN(data.BaseDN.ValueString())
+
+	sr, err := d.conn.CachedSearch(baseDN, scope, filter, []string{noAttributes}, LdapSearchOptions{})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to search for entries to import",
+			fmt.Sprintf("Unable to search %s with filter %s: %s", baseDN, filter, err),
+		)
+		return
+	}
+
+	dns := make([]string, 0, len(sr.Entries))
+	for _, entry := range sr.Entries {
+		dns = append(dns, entry.DN)
+	}
+	sort.Strings(dns)
+
+	entries := make([]LdapImportEntryModel, 0, len(dns))
+	var importBlocks strings.Builder
+	used := make(map[string]bool)
+
+	for _, dn := range dns {
+		importID, err := ldapImportID(dn, data.Attributes.IsNull(), attributes)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to build import ID", err.Error())
+			return
+		}
+
+		name := uniqueResourceName(importResourceName(dn), used)
+		block := fmt.Sprintf("import {\n  to = ldap_entry.%s\n  id = %s\n}\n", name, hclQuote(importID))
+
+		if importBlocks.Len() > 0 {
+			importBlocks.WriteString("\n")
+		}
+		importBlocks.WriteString(block)
+
+		entries = append(entries, LdapImportEntryModel{
+			DN:           types.StringValue(dn),
+			ResourceName: types.StringValue(name),
+			ImportID:     types.StringValue(importID),
+			ImportBlock:  types.StringValue(block),
+		})
+	}
+
+	entriesList, diags := types.ListValueFrom(ctx, types.ObjectType{
+		AttrTypes: map[string]attr.Type{
+			"dn":            types.StringType,
+			"resource_name": types.StringType,
+			"import_id":     types.StringType,
+			"import_block":  types.StringType,
+		},
+	}, entries)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Entries = entriesList
+	data.ImportBlocks = types.StringValue(importBlocks.String())
+	data.Scope = types.StringValue(scope)
+	data.Filter = types.StringValue(filter)
+
+	tflog.Trace(ctx, fmt.Sprintf("found %d entries to import below %s", len(entries), baseDN))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// ldapImportID returns the ldap_entry import ID for dn: the DN itself when allAttributes is
+// true, otherwise the JSON form naming the attributes to import.
+func ldapImportID(dn string, allAttributes bool, attributes []string) (string, error) {
+	if allAttributes {
+		return dn, nil
+	}
+
+	if attributes == nil {
+		attributes = []string{}
+	}
+
+	id, err := json.Marshal(struct {
+		DN         string   `json:"dn"`
+		Attributes []string `json:"attributes"`
+	}{DN: dn, Attributes: attributes})
+	if err != nil {
+		return "", fmt.Errorf("unable to encode import ID for %s: %s", dn, err)
+	}
+
+	return string(id), nil
+}
+
+// importResourceName derives a Terraform resource name from the first value of the leftmost
+// RDN of dn, e.g. "uid=jdoe,ou=users,dc=example,dc=com" becomes "jdoe".
+func importResourceName(dn string) string {
+	value := dn
+	if parsed, err := ldap.ParseDN(dn); err == nil && len(parsed.RDNs) > 0 && len(parsed.RDNs[0].Attributes) > 0 {
+		value = parsed.RDNs[0].Attributes[0].Value
+	}
+
+	var b strings.Builder
+	for _, c := range strings.ToLower(value) {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '_', c == '-':
+			b.WriteRune(c)
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	name := b.String()
+	// Identifiers must start with a letter or underscore
+	if name == "" || (name[0] >= '0' && name[0] <= '9') || name[0] == '-' {
+		name = "entry_" + name
+	}
+	return name
+}
+
+// uniqueResourceName returns name, or name suffixed with a counter if it is already used,
+// and records the result as used.
+func uniqueResourceName(name string, used map[string]bool) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// hclQuote returns s as an HCL quoted string literal, escaping template sequences so the
+// value is taken literally.
+func hclQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"':
+			b.WriteString(`\"`)
+		case c == '\\':
+			b.WriteString(`\\`)
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\r':
+			b.WriteString(`\r`)
+		case c == '\t':
+			b.WriteString(`\t`)
+		case (c == '$' || c == '%') && i+1 < len(s) && s[i+1] == '{':
+			b.WriteByte(c)
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
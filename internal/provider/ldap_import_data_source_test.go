@@ -0,0 +1,144 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestLdapImportID(t *testing.T) {
+	tests := []struct {
+		name          string
+		allAttributes bool
+		attributes    []string
+		expected      string
+	}{
+		{name: "plain dn", allAttributes: true, expected: "uid=jdoe,ou=users,dc=example,dc=com"},
+		{name: "attributes", attributes: []string{"objectClass", "cn"}, expected: `{"dn":"uid=jdoe,ou=users,dc=example,dc=com","attributes":["objectClass","cn"]}`},
+		{name: "empty attributes", attributes: []string{}, expected: `{"dn":"uid=jdoe,ou=users,dc=example,dc=com","attributes":[]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ldapImportID("uid=jdoe,ou=users,dc=example,dc=com", tt.allAttributes, tt.attributes)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if result != tt.expected {
+				t.Errorf("ldapImportID() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestImportResourceName(t *testing.T) {
+	tests := []struct {
+		dn       string
+		expected string
+	}{
+		{dn: "uid=jdoe,ou=users,dc=example,dc=com", expected: "jdoe"},
+		{dn: "cn=John Doe,ou=users,dc=example,dc=com", expected: "john_doe"},
+		{dn: `cn=Doe\, John,ou=users,dc=example,dc=com`, expected: "doe__john"},
+		{dn: "cn=John+uid=jdoe,ou=users,dc=example,dc=com", expected: "john"},
+		{dn: "uidNumber=1001,ou=users,dc=example,dc=com", expected: "entry_1001"},
+		{dn: "cn=Dev-Ops,ou=groups,dc=example,dc=com", expected: "dev-ops"},
+		{dn: "ou=Zürich,dc=example,dc=com", expected: "z_rich"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dn, func(t *testing.T) {
+			if result := importResourceName(tt.dn); result != tt.expected {
+				t.Errorf("importResourceName(%q) = %q, want %q", tt.dn, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUniqueResourceName(t *testing.T) {
+	used := make(map[string]bool)
+
+	for _, expected := range []string{"users", "users_2", "users_3"} {
+		if result := uniqueResourceName("users", used); result != expected {
+			t.Errorf("uniqueResourceName() = %q, want %q", result, expected)
+		}
+	}
+}
+
+func TestHclQuote(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "uid=jdoe,dc=example,dc=com", expected: `"uid=jdoe,dc=example,dc=com"`},
+		{input: `{"dn":"cn=a\2cb"}`, expected: `"{\"dn\":\"cn=a\\2cb\"}"`},
+		{input: "cost ${var} and %{if}", expected: `"cost $${var} and %%{if}"`},
+		{input: "$5 and 100%", expected: `"$5 and 100%"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if result := hclQuote(tt.input); result != tt.expected {
+				t.Errorf("hclQuote(%q) = %s, want %s", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAccLdapImportDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapImportDataSourceConfig(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.ldap_import.base",
+						tfjsonpath.New("entries"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.ObjectExact(map[string]knownvalue.Check{
+								"dn":            knownvalue.StringExact("dc=example,dc=com"),
+								"resource_name": knownvalue.StringExact("example"),
+								"import_id":     knownvalue.StringExact(`{"dn":"dc=example,dc=com","attributes":["objectClass","dc"]}`),
+								"import_block":  knownvalue.StringExact("import {\n  to = ldap_entry.example\n  id = \"{\\\"dn\\\":\\\"dc=example,dc=com\\\",\\\"attributes\\\":[\\\"objectClass\\\",\\\"dc\\\"]}\"\n}\n"),
+							}),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.ldap_import.ous",
+						tfjsonpath.New("entries").AtSliceIndex(0).AtMapKey("import_id"),
+						knownvalue.StringExact("ou=groups,dc=example,dc=com"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccLdapImportDataSourceConfig() string {
+	return `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+data "ldap_import" "base" {
+  basedn = "dc=example,dc=com"
+  scope = "base"
+  attributes = ["objectClass", "dc"]
+}
+
+data "ldap_import" "ous" {
+  basedn = "dc=example,dc=com"
+  scope = "one"
+  filter = "(objectClass=organizationalUnit)"
+}
+`
+}
@@ -4,8 +4,10 @@
 package provider
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/go-ldap/ldap/v3"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
 	"github.com/hashicorp/terraform-plugin-testing/statecheck"
@@ -116,6 +118,86 @@ func TestAccLdapIntegration_SearchScopes(t *testing.T) {
 	})
 }
 
+// testAccPagedEntries is the number of entries of the paged search test, more than the 1000
+// entries Active Directory returns for one search by default.
+const testAccPagedEntries = 1001
+
+func TestAccLdapIntegration_PagedSearch(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccCreatePagedEntries(t)
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapEntryResourceConfigProviderOnly() + `
+data "ldap_search" "paged" {
+  basedn               = "ou=paged,dc=example,dc=com"
+  scope                = "one"
+  filter               = "(objectClass=organizationalRole)"
+  requested_attributes = ["cn"]
+  page_size            = 100
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					// All pages are returned
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.paged",
+						tfjsonpath.New("results"),
+						knownvalue.ListSizeExact(testAccPagedEntries),
+					),
+				},
+			},
+		},
+	})
+}
+
+// testAccCreatePagedEntries creates ou=paged,dc=example,dc=com with testAccPagedEntries entries
+// directly on the server, and deletes them when the test finishes.
+func testAccCreatePagedEntries(t *testing.T) {
+	conn, err := testAccDialLdap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var dns []string
+	t.Cleanup(func() {
+		conn, err := testAccDialLdap()
+		if err != nil {
+			t.Errorf("unable to delete the paged entries: %s", err)
+			return
+		}
+		defer conn.Close()
+
+		for i := len(dns) - 1; i >= 0; i-- {
+			if err := conn.Del(ldap.NewDelRequest(dns[i], nil)); err != nil {
+				t.Errorf("unable to delete %s: %s", dns[i], err)
+			}
+		}
+	})
+
+	parent := ldap.NewAddRequest("ou=paged,dc=example,dc=com", nil)
+	parent.Attribute("objectClass", []string{"organizationalUnit"})
+	parent.Attribute("ou", []string{"paged"})
+	if err := conn.Add(parent); err != nil {
+		t.Fatalf("unable to add %s: %s", parent.DN, err)
+	}
+	dns = append(dns, parent.DN)
+
+	for i := 0; i < testAccPagedEntries; i++ {
+		cn := fmt.Sprintf("paged%04d", i)
+		entry := ldap.NewAddRequest("cn="+cn+",ou=paged,dc=example,dc=com", nil)
+		entry.Attribute("objectClass", []string{"organizationalRole"})
+		entry.Attribute("cn", []string{cn})
+		if err := conn.Add(entry); err != nil {
+			t.Fatalf("unable to add %s: %s", entry.DN, err)
+		}
+		dns = append(dns, entry.DN)
+	}
+}
+
 func TestAccLdapIntegration_ComplexFilters(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
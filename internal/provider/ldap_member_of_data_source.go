@@ -0,0 +1,152 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &LdapMemberOfDataSource{}
+
+// matchingRuleInChain is the Active Directory LDAP_MATCHING_RULE_IN_CHAIN OID, which matches
+// membership through nested groups.
+const matchingRuleInChain = "1.2.840.113556.1.4.1941"
+
+func NewLdapMemberOfDataSource() datasource.DataSource {
+	return &LdapMemberOfDataSource{}
+}
+
+// LdapMemberOfDataSource defines the data source implementation.
+type LdapMemberOfDataSource struct {
+	conn *LdapClient
+}
+
+// LdapMemberOfDataSourceModel describes the data source data model.
+type LdapMemberOfDataSourceModel struct {
+	UserDN      types.String `tfsdk:"user_dn"`
+	GroupBaseDN types.String `tfsdk:"group_base_dn"`
+	Transitive  types.Bool   `tfsdk:"transitive"`
+	Groups      types.List   `tfsdk:"groups"`
+}
+
+func (d *LdapMemberOfDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_member_of"
+}
+
+func (d *LdapMemberOfDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Resolves the groups an entry is a member of. Without `group_base_dn` the entry's `memberOf` attribute is read, which requires a server that maintains it (Active Directory, or OpenLDAP with the memberof overlay). " +
+			"With `group_base_dn` the groups below it are searched for `(member=<user_dn>)` instead.",
+
+		Attributes: map[string]schema.Attribute{
+			"user_dn": schema.StringAttribute{
+				MarkdownDescription: "DN of the entry whose group memberships are resolved. Relative to the provider `base_dn` if it does not already end with it.",
+				Required:            true,
+			},
+			"group_base_dn": schema.StringAttribute{
+				MarkdownDescription: "Base DN to search for groups whose `member` attribute contains `user_dn`. Relative to the provider `base_dn` if it does not already end with it. If not set, the `memberOf` attribute of `user_dn` is read instead.",
+				Optional:            true,
+			},
+			"transitive": schema.BoolAttribute{
+				MarkdownDescription: "Active Directory only. Whether to include groups the entry is a member of through nested groups, using the `" + matchingRuleInChain + "` (LDAP_MATCHING_RULE_IN_CHAIN) matching rule. Requires `group_base_dn`. Defaults to `false`.",
+				Optional:            true,
+			},
+			"groups": schema.ListAttribute{
+				MarkdownDescription: "Sorted list of DNs of the groups the entry is a member of.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+		},
+	}
+}
+
+func (d *LdapMemberOfDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.conn = GetLdapConnection(req.ProviderData, &resp.Diagnostics, "Data Source")
+}
+
+func (d *LdapMemberOfDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data LdapMemberOfDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	userDN := d.conn.ResolveDN(data.UserDN.ValueString())
+	transitive := data.Transitive.ValueBool()
+
+	var groups []string
+	if data.GroupBaseDN.IsNull() {
+		if transitive {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("transitive"),
+				"Missing group_base_dn",
+				"Transitive membership is resolved by searching groups, so group_base_dn must be set when transitive is true.",
+			)
+			return
+		}
+
+		sr, err := d.conn.CachedSearch(userDN, "base", "(objectClass=*)", []string{"memberOf"}, LdapSearchOptions{})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Failed to read memberOf",
+				fmt.Sprintf("Unable to read memberOf of %s: %s", userDN, err),
+			)
+			return
+		}
+
+		for _, entry := range sr.Entries {
+			groups = append(groups, entry.GetEqualFoldAttributeValues("memberOf")...)
+		}
+	} else {
+		filter := memberOfFilter(userDN, transitive)
+
+		groupBaseDN := d.conn.ResolveDN(data.GroupBaseDN.ValueString())
+
+		sr, err := d.conn.CachedSearch(groupBaseDN, "sub", filter, []string{noAttributes}, LdapSearchOptions{})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Failed to search for groups",
+				fmt.Sprintf("Unable to search %s with filter %s: %s", groupBaseDN, filter, err),
+			)
+			return
+		}
+
+		for _, entry := range sr.Entries {
+			groups = append(groups, entry.DN)
+		}
+	}
+
+	sort.Strings(groups)
+
+	groupsList, diags := types.ListValueFrom(ctx, types.StringType, groups)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Groups = groupsList
+
+	tflog.Trace(ctx, fmt.Sprintf("resolved %d group memberships for %s", len(groups), userDN))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// memberOfFilter returns a filter matching groups whose member attribute contains userDN,
+// directly or, if transitive, through nested groups.
+func memberOfFilter(userDN string, transitive bool) string {
+	if transitive {
+		return fmt.Sprintf("(member:%s:=%s)", matchingRuleInChain, ldap.EscapeFilter(userDN))
+	}
+	return fmt.Sprintf("(member=%s)", ldap.EscapeFilter(userDN))
+}
@@ -0,0 +1,129 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestMemberOfFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		userDN     string
+		transitive bool
+		expected   string
+	}{
+		{
+			name:     "direct",
+			userDN:   "uid=jdoe,ou=users,dc=example,dc=com",
+			expected: "(member=uid=jdoe,ou=users,dc=example,dc=com)",
+		},
+		{
+			name:       "transitive",
+			userDN:     "uid=jdoe,ou=users,dc=example,dc=com",
+			transitive: true,
+			expected:   "(member:1.2.840.113556.1.4.1941:=uid=jdoe,ou=users,dc=example,dc=com)",
+		},
+		{
+			name:     "special characters escaped",
+			userDN:   "cn=John (Contractor)*,ou=users,dc=example,dc=com",
+			expected: `(member=cn=John \28Contractor\29\2a,ou=users,dc=example,dc=com)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := memberOfFilter(tt.userDN, tt.transitive)
+			if result != tt.expected {
+				t.Errorf("memberOfFilter(%q, %v) = %q, want %q", tt.userDN, tt.transitive, result, tt.expected)
+			}
+			if _, err := ldap.CompileFilter(result); err != nil {
+				t.Errorf("memberOfFilter(%q, %v) produced an invalid filter: %s", tt.userDN, tt.transitive, err)
+			}
+		})
+	}
+}
+
+func TestAccLdapMemberOfDataSource_GroupSearch(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapMemberOfDataSourceConfig(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.ldap_member_of.test",
+						tfjsonpath.New("groups"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact("cn=memberof-a,ou=groups,dc=example,dc=com"),
+							knownvalue.StringExact("cn=memberof-b,ou=groups,dc=example,dc=com"),
+						}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccLdapMemberOfDataSourceConfig() string {
+	return `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+resource "ldap_entry" "user" {
+  dn = "uid=memberof,ou=users,dc=example,dc=com"
+  attributes = {
+    objectClass = ["inetOrgPerson"]
+    cn = ["Member Of"]
+    sn = ["Of"]
+    uid = ["memberof"]
+  }
+}
+
+resource "ldap_entry" "group_a" {
+  dn = "cn=memberof-a,ou=groups,dc=example,dc=com"
+  attributes = {
+    objectClass = ["groupOfNames"]
+    cn = ["memberof-a"]
+    member = [ldap_entry.user.dn]
+  }
+}
+
+resource "ldap_entry" "group_b" {
+  dn = "cn=memberof-b,ou=groups,dc=example,dc=com"
+  attributes = {
+    objectClass = ["groupOfNames"]
+    cn = ["memberof-b"]
+    member = [ldap_entry.user.dn]
+  }
+}
+
+resource "ldap_entry" "other_group" {
+  dn = "cn=memberof-other,ou=groups,dc=example,dc=com"
+  attributes = {
+    objectClass = ["groupOfNames"]
+    cn = ["memberof-other"]
+    member = ["cn=Manager,dc=example,dc=com"]
+  }
+}
+
+data "ldap_member_of" "test" {
+  user_dn = ldap_entry.user.dn
+  group_base_dn = "ou=groups,dc=example,dc=com"
+
+  depends_on = [ldap_entry.group_a, ldap_entry.group_b, ldap_entry.other_group]
+}
+`
+}
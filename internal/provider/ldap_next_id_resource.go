@@ -0,0 +1,196 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &LdapNextIDResource{}
+var _ resource.ResourceWithValidateConfig = &LdapNextIDResource{}
+
+func NewLdapNextIDResource() resource.Resource {
+	return &LdapNextIDResource{}
+}
+
+// LdapNextIDResource allocates a value from a counter attribute, e.g. a uidNumber for a POSIX account.
+type LdapNextIDResource struct {
+	client *LdapClient
+}
+
+// LdapNextIDResourceModel describes the resource data model for allocated IDs.
+type LdapNextIDResourceModel struct {
+	DN        types.String `tfsdk:"dn"`        // Counter entry
+	Attribute types.String `tfsdk:"attribute"` // Counter attribute holding the next free ID
+	Triggers  types.Map    `tfsdk:"triggers"`  // Map[String] - arbitrary values; changing them allocates a new ID
+	Value     types.Int64  `tfsdk:"value"`     // Allocated ID
+	Id        types.String `tfsdk:"id"`        // Allocated ID as a string
+}
+
+func (r *LdapNextIDResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_next_id"
+}
+
+func (r *LdapNextIDResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `Allocates an ID from a counter kept in the directory, e.g. a ` + "`uidNumber`" + ` or ` + "`gidNumber`" + ` for a POSIX account or group.
+Allocations are race-free: parallel applies, other Terraform configurations and other tools using the same counter never get the same value.
+
+### Counter entry
+The counter is an attribute of a designated entry holding the next free ID as a single integer, e.g. ` + "`uidNumber: 10000`" + ` on ` + "`cn=uidNumber,ou=counters,dc=example,dc=com`" + `.
+Creating the resource allocates the current value and advances the counter by one, in a single modify operation that deletes the value read and adds the next one.
+The server applies it atomically and rejects it if another client advanced the counter in between, in which case the counter is read again.
+
+The counter entry must exist before the resource is created. On OpenLDAP, any entry allowing the attribute will do, e.g. one with the ` + "`extensibleObject`" + ` object class:
+
+` + "```" + `
+dn: cn=uidNumber,ou=counters,dc=example,dc=com
+objectClass: device
+objectClass: extensibleObject
+cn: uidNumber
+uidNumber: 10000
+` + "```" + `
+
+With the Samba schema, the ` + "`sambaUnixIdPool`" + ` object class holds both ` + "`uidNumber`" + ` and ` + "`gidNumber`" + ` on one entry, as smbldap-tools use it.
+The bound identity needs read and write access to the counter attribute. If the counter entry is managed with ` + "`ldap_entry`" + `, ignore changes to its ` + "`attributes`" + `,
+so that applying it does not reset the counter.
+
+### Lifecycle
+The ID is allocated once, when the resource is created, and kept in the state; the counter is not read again.
+Changing ` + "`dn`" + `, ` + "`attribute`" + ` or ` + "`triggers`" + ` replaces the resource, which allocates a new ID.
+Destroying the resource only removes it from the state: IDs are not reclaimed, so a destroyed ID is never handed out again.
+`,
+
+		Attributes: map[string]schema.Attribute{
+			"dn": schema.StringAttribute{
+				MarkdownDescription: "The distinguished name (DN) of the counter entry. Relative to the provider `base_dn` if it does not already end with it.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"attribute": schema.StringAttribute{
+				MarkdownDescription: "The counter attribute of the entry, holding the next free ID, e.g. `uidNumber` or `gidNumber`.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				MarkdownDescription: "Arbitrary values that, when changed, allocate a new ID.",
+				Optional:            true,
+				ElementType:         types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"value": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The allocated ID.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The allocated ID as a string.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *LdapNextIDResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data LdapNextIDResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.DN.IsNull() && !data.DN.IsUnknown() {
+		if _, err := ldap.ParseDN(data.DN.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("dn"),
+				"Invalid DN",
+				fmt.Sprintf("Unable to parse DN %q: %s", data.DN.ValueString(), err),
+			)
+		}
+	}
+
+	if !data.Attribute.IsNull() && !data.Attribute.IsUnknown() {
+		if !isValidAttributeDescription(data.Attribute.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("attribute"),
+				"Invalid attribute name",
+				fmt.Sprintf("%q is not a valid attribute name or OID.", data.Attribute.ValueString()),
+			)
+		}
+	}
+}
+
+func (r *LdapNextIDResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = GetLdapConnection(req.ProviderData, &resp.Diagnostics, "Resource")
+}
+
+func (r *LdapNextIDResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan LdapNextIDResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dn := r.client.ResolveDN(plan.DN.ValueString())
+	value, err := AllocateNextID(r.client, dn, plan.Attribute.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error allocating ID",
+			fmt.Sprintf("Unable to allocate an ID from %s of %s: %s", plan.Attribute.ValueString(), dn, err),
+		)
+		return
+	}
+	tflog.Trace(ctx, fmt.Sprintf("allocated %s %d from %s", plan.Attribute.ValueString(), value, dn))
+
+	plan.Value = types.Int64Value(value)
+	plan.Id = types.StringValue(strconv.FormatInt(value, 10))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read keeps the state as it is: the ID was allocated once and the counter has moved on since.
+func (r *LdapNextIDResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+}
+
+// Update is never called with a changed configuration, as every change replaces the resource.
+func (r *LdapNextIDResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan LdapNextIDResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete removes the ID from the state only; IDs are not reclaimed.
+func (r *LdapNextIDResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
@@ -0,0 +1,77 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccLdapNextIDResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapEntryResourceConfigProviderOnly() + `
+resource "ldap_entry" "counter" {
+  dn = "cn=uidNumber,dc=example,dc=com"
+  attributes = {
+    objectClass = ["device", "extensibleObject"]
+    cn          = ["uidNumber"]
+    uidNumber   = ["10000"]
+  }
+
+  lifecycle {
+    ignore_changes = [attributes]
+  }
+}
+
+resource "ldap_next_id" "first" {
+  dn        = ldap_entry.counter.dn
+  attribute = "uidNumber"
+}
+
+resource "ldap_next_id" "second" {
+  dn        = ldap_entry.counter.dn
+  attribute = "uidNumber"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("ldap_next_id.first", "value", regexp.MustCompile(`^1000[01]$`)),
+					resource.TestMatchResourceAttr("ldap_next_id.second", "value", regexp.MustCompile(`^1000[01]$`)),
+					func(s *terraform.State) error {
+						first := s.RootModule().Resources["ldap_next_id.first"].Primary.ID
+						second := s.RootModule().Resources["ldap_next_id.second"].Primary.ID
+						if first == second {
+							return fmt.Errorf("both resources allocated %s", first)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccLdapNextIDResource_InvalidAttribute(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapEntryResourceConfigProviderOnly() + `
+resource "ldap_next_id" "test" {
+  dn        = "cn=uidNumber,dc=example,dc=com"
+  attribute = "uid Number"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid attribute name`),
+			},
+		},
+	})
+}
@@ -0,0 +1,104 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// randomRDNBytes is the number of random bytes in a random RDN value, hex encoded after the prefix.
+const randomRDNBytes = 8
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ ephemeral.EphemeralResource = &LdapRandomRDNEphemeralResource{}
+
+func NewLdapRandomRDNEphemeralResource() ephemeral.EphemeralResource {
+	return &LdapRandomRDNEphemeralResource{}
+}
+
+// LdapRandomRDNEphemeralResource defines the ephemeral resource implementation.
+type LdapRandomRDNEphemeralResource struct{}
+
+// LdapRandomRDNEphemeralResourceModel describes the ephemeral resource data model.
+type LdapRandomRDNEphemeralResourceModel struct {
+	Prefix types.String `tfsdk:"prefix"`
+	Value  types.String `tfsdk:"value"`
+}
+
+func (r *LdapRandomRDNEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_random_rdn"
+}
+
+func (r *LdapRandomRDNEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Generates a unique RDN value, e.g. for the `cn` of test fixtures and other temporary entries. " +
+			"Unlike `random_id`, the value only consists of letters, digits, `-`, `_` and `.`, so it can be used in a DN (e.g. `\"cn=${ephemeral.ldap_random_rdn.test.value},ou=test,dc=example,dc=com\"`) without escaping, " +
+			"and in search filters and LDAP URLs as well. " +
+			"A new value is generated each time Terraform opens the ephemeral resource, i.e. in every plan and apply, and it is never stored in state. " +
+			"Terraform only accepts ephemeral values where they are not persisted, such as provider configuration, write-only attributes, provisioners and other ephemeral resources; " +
+			"`ldap_entry.dn` is stored in state, so it cannot be built from this value.",
+
+		Attributes: map[string]schema.Attribute{
+			"prefix": schema.StringAttribute{
+				MarkdownDescription: "Text the value starts with, e.g. `test-`. May only contain letters, digits, `-`, `_` and `.`. Defaults to no prefix.",
+				Optional:            true,
+				Validators: []validator.String{
+					rdnSafeValidator{},
+				},
+			},
+			"value": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("`prefix` followed by %d random lowercase hexadecimal digits, e.g. `test-3f9a0c6e1b7d2a45`.", 2*randomRDNBytes),
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *LdapRandomRDNEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data LdapRandomRDNEphemeralResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	value, err := randomRDNValue(data.Prefix.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to generate random RDN value", err.Error())
+		return
+	}
+	data.Value = types.StringValue(value)
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+// randomRDNValue returns prefix followed by randomRDNBytes random bytes as lowercase hex.
+func randomRDNValue(prefix string) (string, error) {
+	random := make([]byte, randomRDNBytes)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return prefix + hex.EncodeToString(random), nil
+}
+
+// isRDNSafe reports whether s only consists of characters that never need escaping in a DN, a
+// search filter or an LDAP URL: ASCII letters, digits, '-', '_' and '.'.
+func isRDNSafe(s string) bool {
+	for _, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/echoprovider"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestRandomRDNValue(t *testing.T) {
+	seen := map[string]bool{}
+	for _, prefix := range []string{"", "test-", "Fixture_1.", "x"} {
+		for i := 0; i < 100; i++ {
+			value, err := randomRDNValue(prefix)
+			if err != nil {
+				t.Fatalf("randomRDNValue(%q) error = %s", prefix, err)
+			}
+
+			if !strings.HasPrefix(value, prefix) || len(value) != len(prefix)+2*randomRDNBytes {
+				t.Fatalf("randomRDNValue(%q) = %q, want the prefix followed by %d hex digits", prefix, value, 2*randomRDNBytes)
+			}
+			if seen[value] {
+				t.Fatalf("randomRDNValue(%q) returned %q twice", prefix, value)
+			}
+			seen[value] = true
+
+			if escaped := ldap.EscapeDN(value); escaped != value {
+				t.Errorf("randomRDNValue(%q) = %q, needs escaping in a DN as %q", prefix, value, escaped)
+			}
+			if escaped := ldap.EscapeFilter(value); escaped != value {
+				t.Errorf("randomRDNValue(%q) = %q, needs escaping in a filter as %q", prefix, value, escaped)
+			}
+			if escaped := url.PathEscape(value); escaped != value {
+				t.Errorf("randomRDNValue(%q) = %q, needs escaping in a URL as %q", prefix, value, escaped)
+			}
+
+			dn, err := ldap.ParseDN("cn=" + value + ",ou=test,dc=example,dc=com")
+			if err != nil {
+				t.Fatalf("unable to parse DN built from %q: %s", value, err)
+			}
+			if got := dn.RDNs[0].Attributes[0].Value; got != value {
+				t.Errorf("DN built from %q has RDN value %q", value, got)
+			}
+		}
+	}
+}
+
+func TestIsRDNSafe(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{value: "", want: true},
+		{value: "test-Fixture_1.2", want: true},
+		{value: "a b", want: false},
+		{value: "a,b", want: false},
+		{value: "a+b", want: false},
+		{value: "a=b", want: false},
+		{value: "#a", want: false},
+		{value: "a*", want: false},
+		{value: "a\\", want: false},
+		{value: "é", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := isRDNSafe(tt.value); got != tt.want {
+			t.Errorf("isRDNSafe(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestAccLdapRandomRDNEphemeralResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() { testAccPreCheck(t) },
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"ldap": testAccProtoV6ProviderFactories["ldap"],
+			"echo": echoprovider.NewProviderServer(),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapEntryResourceConfigProviderOnly() + `
+ephemeral "ldap_random_rdn" "test" {
+  prefix = "test-"
+}
+
+provider "echo" {
+  data = ephemeral.ldap_random_rdn.test.value
+}
+
+resource "echo" "test" {}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"echo.test",
+						tfjsonpath.New("data"),
+						knownvalue.StringRegexp(regexp.MustCompile(`^test-[0-9a-f]{16}$`)),
+					),
+				},
+			},
+			{
+				Config: testAccLdapEntryResourceConfigProviderOnly() + `
+ephemeral "ldap_random_rdn" "test" {
+  prefix = "test,"
+}
+`,
+				ExpectError: regexp.MustCompile(`may only contain letters, digits`),
+			},
+		},
+	})
+}
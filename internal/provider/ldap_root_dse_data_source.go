@@ -0,0 +1,180 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &LdapRootDSEDataSource{}
+
+func NewLdapRootDSEDataSource() datasource.DataSource {
+	return &LdapRootDSEDataSource{}
+}
+
+// LdapRootDSEDataSource defines the data source implementation.
+type LdapRootDSEDataSource struct {
+	conn *LdapClient
+}
+
+// LdapRootDSEDataSourceModel describes the data source data model.
+type LdapRootDSEDataSourceModel struct {
+	DefaultNamingContext types.String `tfsdk:"default_naming_context"`
+	NamingContexts       types.List   `tfsdk:"naming_contexts"`
+	Attributes           types.Map    `tfsdk:"attributes"`
+	CurrentTime          types.String `tfsdk:"current_time"`
+	ClockSkewSeconds     types.Int64  `tfsdk:"clock_skew_seconds"`
+}
+
+func (d *LdapRootDSEDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_root_dse"
+}
+
+func (d *LdapRootDSEDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reads the Root DSE, the entry with the empty DN describing the server, e.g. to compose DNs from the server's naming contexts " +
+			"instead of hardcoding `dc=example,dc=com`, so the same module works against directories with different base DNs. " +
+			"The Root DSE is read even when the provider sets `base_dn`.",
+
+		Attributes: map[string]schema.Attribute{
+			"default_naming_context": schema.StringAttribute{
+				MarkdownDescription: "The `defaultNamingContext` of the server, i.e. the DN of the domain on Active Directory. " +
+					"Null if the server does not announce one, as OpenLDAP does not; use `naming_contexts` there.",
+				Computed: true,
+			},
+			"naming_contexts": schema.ListAttribute{
+				MarkdownDescription: "The `namingContexts` of the server: the base DNs of the directory trees it holds, in the order returned by the server.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"attributes": schema.MapAttribute{
+				MarkdownDescription: "All user and operational attributes of the Root DSE the server returns, such as `supportedControl`, `supportedLDAPVersion` or `vendorName`.",
+				Computed:            true,
+				ElementType:         types.ListType{ElemType: types.StringType},
+			},
+			"current_time": schema.StringAttribute{
+				MarkdownDescription: "The server's clock, from the `currentTime` attribute of the Root DSE, as an RFC 3339 timestamp in UTC. " +
+					"Read with a separate search, so it is current even with `search_cache`. " +
+					"Null if the server does not announce its time; Active Directory does, OpenLDAP does not.",
+				Computed: true,
+			},
+			"clock_skew_seconds": schema.Int64Attribute{
+				MarkdownDescription: "How many seconds the server's clock is ahead of the clock of the host running Terraform (negative if it is behind), " +
+					"e.g. to diagnose Kerberos or other time-based authentication failures, which Active Directory rejects beyond 5 minutes of skew by default. " +
+					"Accurate to about a second plus the network round trip. Null if `current_time` is null.",
+				Computed: true,
+			},
+		},
+	}
+}
+
+func (d *LdapRootDSEDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.conn = GetLdapConnection(req.ProviderData, &resp.Diagnostics, "Data Source")
+}
+
+func (d *LdapRootDSEDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data LdapRootDSEDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The Root DSE holds mostly operational attributes, which are only returned with "+"
+	sr, err := d.conn.CachedSearch("", "base", "(objectClass=*)", []string{"*", "+"}, LdapSearchOptions{})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read Root DSE", err.Error())
+		return
+	}
+	if len(sr.Entries) == 0 {
+		resp.Diagnostics.AddError(
+			"Failed to read Root DSE",
+			"The server returned no Root DSE. It may not allow the bound identity to read it.",
+		)
+		return
+	}
+	entry := sr.Entries[0]
+
+	results, err := MarshalLdapResults(ctx, sr, nil, MarshalOptions{
+		FoldAttributeNames: d.conn.FoldsAttributeNames(),
+		Lenient:            d.conn.LenientReads(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to convert LDAP search results", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(marshalWarnings(results)...)
+
+	data.DefaultNamingContext = types.StringNull()
+	if values := entry.GetEqualFoldAttributeValues("defaultNamingContext"); len(values) > 0 {
+		data.DefaultNamingContext = types.StringValue(values[0])
+	}
+
+	contexts := entry.GetEqualFoldAttributeValues("namingContexts")
+	if contexts == nil {
+		contexts = []string{}
+	}
+	namingContexts, diags := types.ListValueFrom(ctx, types.StringType, contexts)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.NamingContexts = namingContexts
+	data.Attributes = results[0].Attributes
+
+	data.CurrentTime = types.StringNull()
+	data.ClockSkewSeconds = types.Int64Null()
+	if len(entry.GetEqualFoldAttributeValues("currentTime")) > 0 {
+		serverTime, skew, err := readServerClock(d.conn, time.Now)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to read server time", err.Error())
+			return
+		}
+		data.CurrentTime = types.StringValue(serverTime.UTC().Format(time.RFC3339))
+		data.ClockSkewSeconds = types.Int64Value(int64(math.Round(skew.Seconds())))
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("read Root DSE with %d naming contexts", len(namingContexts.Elements())))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// readServerClock reads the currentTime of the Root DSE and returns it with how far it is ahead of
+// the local clock now. The server time is compared to the local time halfway through the search,
+// which cancels out the network round trip as long as it is symmetric.
+func readServerClock(conn LdapSearcher, now func() time.Time) (time.Time, time.Duration, error) {
+	before := now()
+	sr, err := LdapSearch(conn, "", "base", "(objectClass=*)", []string{"currentTime"}, LdapSearchOptions{})
+	after := now()
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	var values []string
+	if len(sr.Entries) > 0 {
+		values = sr.Entries[0].GetEqualFoldAttributeValues("currentTime")
+	}
+	if len(values) == 0 {
+		return time.Time{}, 0, errors.New("the Root DSE has no currentTime")
+	}
+
+	serverTime, err := ber.ParseGeneralizedTime([]byte(values[0]))
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("invalid currentTime %q: %s", values[0], err)
+	}
+
+	local := before.Add(after.Sub(before) / 2)
+	return serverTime, serverTime.Sub(local), nil
+}
@@ -0,0 +1,128 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccLdapRootDSEDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapRootDSEDataSourceConfig(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.ldap_root_dse.test",
+						tfjsonpath.New("naming_contexts"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("dc=example,dc=com")}),
+					),
+					// OpenLDAP has no default naming context
+					statecheck.ExpectKnownValue(
+						"data.ldap_root_dse.test",
+						tfjsonpath.New("default_naming_context"),
+						knownvalue.Null(),
+					),
+					statecheck.ExpectKnownValue(
+						"data.ldap_root_dse.test",
+						tfjsonpath.New("attributes").AtMapKey("supportedLDAPVersion"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("3")}),
+					),
+					// OpenLDAP does not announce its time
+					statecheck.ExpectKnownValue(
+						"data.ldap_root_dse.test",
+						tfjsonpath.New("current_time"),
+						knownvalue.Null(),
+					),
+					statecheck.ExpectKnownValue(
+						"data.ldap_root_dse.test",
+						tfjsonpath.New("clock_skew_seconds"),
+						knownvalue.Null(),
+					),
+					// DNs composed from the naming context resolve like hardcoded ones
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.composed",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("dn"),
+						knownvalue.StringExact("ou=users,dc=example,dc=com"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccLdapRootDSEDataSourceConfig() string {
+	return `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+  base_dn = "dc=example,dc=com"
+}
+
+data "ldap_root_dse" "test" {}
+
+data "ldap_search" "composed" {
+  basedn = "ou=users,${data.ldap_root_dse.test.naming_contexts[0]}"
+  scope = "base"
+  filter = "(objectClass=*)"
+}
+`
+}
+
+func TestReadServerClock(t *testing.T) {
+	local := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	// The search takes two seconds: the server time is compared to the local time after one
+	clock := []time.Time{local, local.Add(2 * time.Second)}
+	now := func() time.Time {
+		t := clock[0]
+		clock = clock[1:]
+		return t
+	}
+
+	searcher := &staticSearcher{result: &ldap.SearchResult{Entries: []*ldap.Entry{
+		ldap.NewEntry("", map[string][]string{"currentTime": {"20250301120131.0Z"}}),
+	}}}
+
+	serverTime, skew, err := readServerClock(searcher, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := time.Date(2025, 3, 1, 12, 1, 31, 0, time.UTC); !serverTime.Equal(expected) {
+		t.Errorf("server time = %s, want %s", serverTime, expected)
+	}
+	if skew != 90*time.Second {
+		t.Errorf("skew = %s, want 1m30s", skew)
+	}
+}
+
+func TestReadServerClock_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		searcher *staticSearcher
+	}{
+		{name: "search fails", searcher: &staticSearcher{err: ldap.NewError(ldap.LDAPResultUnavailable, nil)}},
+		{name: "no current time", searcher: &staticSearcher{result: &ldap.SearchResult{Entries: []*ldap.Entry{ldap.NewEntry("", nil)}}}},
+		{name: "invalid current time", searcher: &staticSearcher{result: &ldap.SearchResult{Entries: []*ldap.Entry{
+			ldap.NewEntry("", map[string][]string{"currentTime": {"yesterday"}}),
+		}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := readServerClock(tt.searcher, time.Now); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
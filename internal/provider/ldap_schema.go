@@ -0,0 +1,263 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// defaultSubschemaDN is used when the root DSE does not name the subschema subentry.
+const defaultSubschemaDN = "cn=Subschema"
+
+// LdapAttributeType is the subset of an RFC 4512 AttributeTypeDescription used by the provider.
+type LdapAttributeType struct {
+	OID                string
+	Names              []string
+	Sup                string
+	Syntax             string
+	SingleValue        bool
+	NoUserModification bool
+	Usage              string
+}
+
+// LdapSchema holds the attribute types published in a server's subschema subentry.
+type LdapSchema struct {
+	attributeTypes map[string]*LdapAttributeType
+}
+
+// AttributeType looks up an attribute type by name or OID, ignoring case and attribute options.
+// Returns nil if the schema does not define it.
+func (s *LdapSchema) AttributeType(name string) *LdapAttributeType {
+	if s == nil {
+		return nil
+	}
+	return s.attributeTypes[strings.ToLower(attributeType(name))]
+}
+
+// IsOperational reports whether the attribute type is operational, i.e. maintained by the server
+// (USAGE other than userApplications) or not modifiable by users (NO-USER-MODIFICATION).
+func (at *LdapAttributeType) IsOperational() bool {
+	return at.NoUserModification || (at.Usage != "" && !strings.EqualFold(at.Usage, "userApplications"))
+}
+
+// IsSingleValued reports whether the schema declares the attribute type of name SINGLE-VALUE.
+func (s *LdapSchema) IsSingleValued(name string) bool {
+	at := s.AttributeType(name)
+	return at != nil && at.SingleValue
+}
+
+// Syntax OIDs of attribute types whose values typed_values converts.
+const (
+	syntaxBoolean = "1.3.6.1.4.1.1466.115.121.1.7"
+	syntaxInteger = "1.3.6.1.4.1.1466.115.121.1.27"
+	// syntaxLargeInteger is the Active Directory syntax of 64-bit integers such as pwdLastSet.
+	syntaxLargeInteger = "1.2.840.113556.1.4.906"
+)
+
+// syntaxDN is the syntax OID of attribute types holding DNs, such as member.
+const syntaxDN = "1.3.6.1.4.1.1466.115.121.1.12"
+
+// maxSupDepth bounds how many SUP references Syntax follows, guarding against cyclic definitions.
+const maxSupDepth = 16
+
+// Syntax returns the syntax OID of the attribute type of name, inherited from its superior types if
+// it declares none. Returns "" if the schema does not define it or none of them declares a syntax.
+func (s *LdapSchema) Syntax(name string) string {
+	at := s.AttributeType(name)
+	for depth := 0; at != nil && depth < maxSupDepth; depth++ {
+		if at.Syntax != "" {
+			return at.Syntax
+		}
+		if at.Sup == "" {
+			return ""
+		}
+		at = s.AttributeType(at.Sup)
+	}
+	return ""
+}
+
+// Schema returns the server schema, reading it on first use and caching it for the lifetime of the
+// provider instance. Failed reads are not cached.
+func (c *LdapClient) Schema(ctx context.Context) (*LdapSchema, error) {
+	c.schemaMu.Lock()
+	defer c.schemaMu.Unlock()
+
+	if c.schema != nil {
+		return c.schema, nil
+	}
+
+	schema, err := readLdapSchema(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	c.schema = schema
+	return schema, nil
+}
+
+// readLdapSchema locates the subschema subentry through the root DSE and parses its attributeTypes.
+func readLdapSchema(ctx context.Context, conn LdapSearcher) (*LdapSchema, error) {
+	subschemaDN := defaultSubschemaDN
+
+	sr, err := LdapSearch(conn, "", "base", "(objectClass=*)", []string{"subschemaSubentry"}, LdapSearchOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read subschemaSubentry from the root DSE: %w", err)
+	}
+	if len(sr.Entries) > 0 {
+		if dn := sr.Entries[0].GetEqualFoldAttributeValue("subschemaSubentry"); dn != "" {
+			subschemaDN = dn
+		}
+	}
+
+	sr, err = LdapSearch(conn, subschemaDN, "base", "(objectClass=subschema)", []string{"attributeTypes"}, LdapSearchOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read schema from %s: %w", subschemaDN, err)
+	}
+	if len(sr.Entries) == 0 {
+		return nil, fmt.Errorf("subschema subentry %s not found", subschemaDN)
+	}
+
+	schema, err := ParseLdapSchema(sr.Entries[0].GetEqualFoldAttributeValues("attributeTypes"))
+	if err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Ignoring unparseable attribute types in %s: %s", subschemaDN, err))
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("read %d attribute type definitions from %s", len(sr.Entries[0].GetEqualFoldAttributeValues("attributeTypes")), subschemaDN))
+
+	return schema, nil
+}
+
+// ParseLdapSchema builds a schema from the values of a subschema subentry's attributeTypes attribute.
+// Definitions that cannot be parsed are skipped and reported together in the returned error, which
+// does not prevent the parsed ones from being used.
+func ParseLdapSchema(attributeTypes []string) (*LdapSchema, error) {
+	schema := &LdapSchema{attributeTypes: make(map[string]*LdapAttributeType)}
+
+	var errs []error
+	for _, definition := range attributeTypes {
+		at, err := parseAttributeTypeDescription(definition)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		schema.attributeTypes[strings.ToLower(at.OID)] = at
+		for _, name := range at.Names {
+			schema.attributeTypes[strings.ToLower(name)] = at
+		}
+	}
+
+	return schema, errors.Join(errs...)
+}
+
+// parseAttributeTypeDescription parses an RFC 4512 section 4.1.2 AttributeTypeDescription such as
+// "( 2.5.4.3 NAME ( 'cn' 'commonName' ) SUP name )".
+func parseAttributeTypeDescription(definition string) (*LdapAttributeType, error) {
+	tokens, err := tokenizeSchemaDescription(definition)
+	if err != nil {
+		return nil, fmt.Errorf("invalid attribute type %q: %w", definition, err)
+	}
+
+	if len(tokens) < 3 || tokens[0] != "(" || tokens[len(tokens)-1] != ")" {
+		return nil, fmt.Errorf("invalid attribute type %q: must be enclosed in parentheses", definition)
+	}
+
+	at := &LdapAttributeType{OID: tokens[1]}
+	rest := tokens[2 : len(tokens)-1]
+
+	for len(rest) > 0 {
+		keyword := rest[0]
+		rest = rest[1:]
+
+		switch keyword {
+		case "OBSOLETE", "COLLECTIVE":
+			continue
+		case "SINGLE-VALUE":
+			at.SingleValue = true
+			continue
+		case "NO-USER-MODIFICATION":
+			at.NoUserModification = true
+			continue
+		}
+
+		var values []string
+		values, rest, err = schemaDescriptionValues(rest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attribute type %q: %s: %w", definition, keyword, err)
+		}
+
+		switch keyword {
+		case "NAME":
+			at.Names = values
+		case "SUP":
+			at.Sup = values[0]
+		case "SYNTAX":
+			// Strip an optional length bound, e.g. "1.3.6.1.4.1.1466.115.121.1.15{256}"
+			at.Syntax, _, _ = strings.Cut(values[0], "{")
+		case "USAGE":
+			at.Usage = values[0]
+		}
+	}
+
+	return at, nil
+}
+
+// schemaDescriptionValues consumes a single value or a parenthesized list of values from tokens.
+// Quoted strings arrive without their quotes; "$" separators in oid lists are dropped.
+func schemaDescriptionValues(tokens []string) ([]string, []string, error) {
+	if len(tokens) == 0 || tokens[0] == ")" {
+		return nil, nil, errors.New("missing value")
+	}
+
+	if tokens[0] != "(" {
+		return tokens[:1], tokens[1:], nil
+	}
+
+	var values []string
+	for i := 1; i < len(tokens); i++ {
+		switch tokens[i] {
+		case ")":
+			return values, tokens[i+1:], nil
+		case "$":
+		default:
+			values = append(values, tokens[i])
+		}
+	}
+
+	return nil, nil, errors.New("unterminated list")
+}
+
+// tokenizeSchemaDescription splits a schema description into parentheses, "$" separators,
+// quoted strings (unquoted) and bare words.
+func tokenizeSchemaDescription(s string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == '$':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, errors.New("unterminated quoted string")
+			}
+			tokens = append(tokens, s[i+1:i+1+end])
+			i += end + 2
+		default:
+			start := i
+			for i < len(s) && !strings.ContainsRune(" \t\n\r()$'", rune(s[i])) {
+				i++
+			}
+			tokens = append(tokens, s[start:i])
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,286 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseAttributeTypeDescription(t *testing.T) {
+	tests := []struct {
+		name        string
+		definition  string
+		expected    LdapAttributeType
+		expectError bool
+	}{
+		{
+			name:       "multiple names with sup",
+			definition: "( 2.5.4.3 NAME ( 'cn' 'commonName' ) DESC 'RFC4519: common name(s) for which the entity is known by' SUP name )",
+			expected:   LdapAttributeType{OID: "2.5.4.3", Names: []string{"cn", "commonName"}, Sup: "name"},
+		},
+		{
+			name:       "single-valued with syntax length",
+			definition: "( 1.3.6.1.1.1.1.0 NAME 'uidNumber' DESC 'RFC2307: An integer uniquely identifying a user in an administrative domain' EQUALITY integerMatch ORDERING integerOrderingMatch SYNTAX 1.3.6.1.4.1.1466.115.121.1.27{16} SINGLE-VALUE )",
+			expected:   LdapAttributeType{OID: "1.3.6.1.1.1.1.0", Names: []string{"uidNumber"}, Syntax: "1.3.6.1.4.1.1466.115.121.1.27", SingleValue: true},
+		},
+		{
+			name:       "operational",
+			definition: "( 2.5.18.1 NAME 'createTimestamp' EQUALITY generalizedTimeMatch ORDERING generalizedTimeOrderingMatch SYNTAX 1.3.6.1.4.1.1466.115.121.1.24 SINGLE-VALUE NO-USER-MODIFICATION USAGE directoryOperation )",
+			expected:   LdapAttributeType{OID: "2.5.18.1", Names: []string{"createTimestamp"}, Syntax: "1.3.6.1.4.1.1466.115.121.1.24", SingleValue: true, NoUserModification: true, Usage: "directoryOperation"},
+		},
+		{
+			name:       "extensions and obsolete",
+			definition: "( 1.2.3.4 NAME 'legacy' OBSOLETE X-ORIGIN ( 'one' 'two' ) X-NOTE 'unused' )",
+			expected:   LdapAttributeType{OID: "1.2.3.4", Names: []string{"legacy"}},
+		},
+		{
+			name:        "unbalanced parentheses",
+			definition:  "( 1.2.3.4 NAME 'broken'",
+			expectError: true,
+		},
+		{
+			name:        "unterminated quote",
+			definition:  "( 1.2.3.4 NAME 'broken )",
+			expectError: true,
+		},
+		{
+			name:        "keyword without value",
+			definition:  "( 1.2.3.4 NAME )",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at, err := parseAttributeTypeDescription(tt.definition)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error, got %+v", at)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if at.OID != tt.expected.OID || at.Sup != tt.expected.Sup || at.Syntax != tt.expected.Syntax ||
+				at.SingleValue != tt.expected.SingleValue || at.NoUserModification != tt.expected.NoUserModification ||
+				at.Usage != tt.expected.Usage || !stringSlicesEqual(at.Names, tt.expected.Names) {
+				t.Errorf("parseAttributeTypeDescription() = %+v, want %+v", *at, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLdapSchemaLookup(t *testing.T) {
+	schema, err := ParseLdapSchema([]string{
+		"( 2.5.4.3 NAME ( 'cn' 'commonName' ) SUP name )",
+		"( 1.3.6.1.1.1.1.0 NAME 'uidNumber' SYNTAX 1.3.6.1.4.1.1466.115.121.1.27 SINGLE-VALUE )",
+		"( garbage",
+	})
+	if err == nil {
+		t.Error("expected an error for the unparseable definition")
+	}
+
+	tests := []struct {
+		name         string
+		attribute    string
+		found        bool
+		singleValued bool
+	}{
+		{name: "by name", attribute: "uidNumber", found: true, singleValued: true},
+		{name: "case insensitive", attribute: "UIDNUMBER", found: true, singleValued: true},
+		{name: "by oid", attribute: "1.3.6.1.1.1.1.0", found: true, singleValued: true},
+		{name: "alias", attribute: "commonName", found: true, singleValued: false},
+		{name: "with options", attribute: "cn;lang-en", found: true, singleValued: false},
+		{name: "unknown", attribute: "mail", found: false, singleValued: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if found := schema.AttributeType(tt.attribute) != nil; found != tt.found {
+				t.Errorf("AttributeType(%q) found = %v, want %v", tt.attribute, found, tt.found)
+			}
+			if sv := schema.IsSingleValued(tt.attribute); sv != tt.singleValued {
+				t.Errorf("IsSingleValued(%q) = %v, want %v", tt.attribute, sv, tt.singleValued)
+			}
+		})
+	}
+}
+
+func TestLdapSchemaSyntax(t *testing.T) {
+	schema, _ := ParseLdapSchema([]string{
+		"( 2.5.4.41 NAME 'name' SYNTAX 1.3.6.1.4.1.1466.115.121.1.15{32768} )",
+		"( 2.5.4.3 NAME ( 'cn' 'commonName' ) SUP name )",
+		"( 1.3.6.1.1.1.1.0 NAME 'uidNumber' SYNTAX 1.3.6.1.4.1.1466.115.121.1.27 SINGLE-VALUE )",
+		"( 1.1.1 NAME 'loopA' SUP loopB )",
+		"( 1.1.2 NAME 'loopB' SUP loopA )",
+		"( 1.1.3 NAME 'orphan' SUP missing )",
+	})
+
+	tests := []struct {
+		attribute string
+		expected  string
+	}{
+		{attribute: "uidNumber", expected: syntaxInteger},
+		{attribute: "name", expected: "1.3.6.1.4.1.1466.115.121.1.15"},
+		{attribute: "commonName", expected: "1.3.6.1.4.1.1466.115.121.1.15"},
+		{attribute: "loopA", expected: ""},
+		{attribute: "orphan", expected: ""},
+		{attribute: "mail", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.attribute, func(t *testing.T) {
+			if got := schema.Syntax(tt.attribute); got != tt.expected {
+				t.Errorf("Syntax(%q) = %q, want %q", tt.attribute, got, tt.expected)
+			}
+		})
+	}
+}
+
+// schemaSearcher serves a root DSE and a subschema subentry.
+type schemaSearcher struct {
+	searches int
+}
+
+func (s *schemaSearcher) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	s.searches++
+
+	switch req.BaseDN {
+	case "":
+		return &ldap.SearchResult{Entries: []*ldap.Entry{
+			ldap.NewEntry("", map[string][]string{"subschemaSubentry": {"cn=Subschema"}}),
+		}}, nil
+	case "cn=Subschema":
+		return &ldap.SearchResult{Entries: []*ldap.Entry{
+			ldap.NewEntry("cn=Subschema", map[string][]string{"attributeTypes": {
+				"( 1.3.6.1.1.1.1.0 NAME 'uidNumber' SINGLE-VALUE )",
+			}}),
+		}}, nil
+	}
+
+	return nil, ldap.NewError(ldap.LDAPResultNoSuchObject, nil)
+}
+
+func TestReadLdapSchema(t *testing.T) {
+	searcher := &schemaSearcher{}
+
+	schema, err := readLdapSchema(context.Background(), searcher)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if searcher.searches != 2 {
+		t.Errorf("expected 2 searches (root DSE and subschema), got %d", searcher.searches)
+	}
+
+	if !schema.IsSingleValued("uidNumber") {
+		t.Error("expected uidNumber to be single-valued")
+	}
+}
+
+func TestFlattenSingleValuedAttributes(t *testing.T) {
+	schema, _ := ParseLdapSchema([]string{
+		"( 2.5.4.3 NAME 'cn' )",
+		"( 1.3.6.1.1.1.1.0 NAME 'uidNumber' SINGLE-VALUE )",
+		"( 1.3.6.1.1.1.1.1 NAME 'gidNumber' SINGLE-VALUE )",
+	})
+
+	attributes, diags := types.MapValueFrom(context.Background(), types.ListType{ElemType: types.StringType}, map[string][]string{
+		"cn":        {"Test User"},
+		"uidNumber": {"10001"},
+		"gidNumber": {},
+	})
+	if diags.HasError() {
+		t.Fatalf("unable to build attributes: %v", diags)
+	}
+
+	flattened, diags := flattenSingleValuedAttributes(context.Background(), attributes, schema)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	var result map[string]string
+	if diags := flattened.ElementsAs(context.Background(), &result, false); diags.HasError() {
+		t.Fatalf("unable to read flattened attributes: %v", diags)
+	}
+
+	if len(result) != 1 || result["uidNumber"] != "10001" {
+		t.Errorf("flattened = %v, want map[uidNumber:10001]", result)
+	}
+}
+
+func TestTypedAttributeValues(t *testing.T) {
+	schema, _ := ParseLdapSchema([]string{
+		"( 2.5.4.3 NAME 'cn' SYNTAX 1.3.6.1.4.1.1466.115.121.1.15 )",
+		"( 1.3.6.1.1.1.1.0 NAME 'uidNumber' SYNTAX 1.3.6.1.4.1.1466.115.121.1.27 SINGLE-VALUE )",
+		"( 1.3.6.1.4.1.42.2.27.8.1.22 NAME 'pwdReset' SYNTAX 1.3.6.1.4.1.1466.115.121.1.7 SINGLE-VALUE )",
+		"( 1.2.840.113556.1.4.96 NAME 'pwdLastSet' SYNTAX 1.2.840.113556.1.4.906 SINGLE-VALUE )",
+		"( 1.2.840.113556.1.4.159 NAME 'accountExpires' SYNTAX 1.2.840.113556.1.4.906 SINGLE-VALUE )",
+		"( 1.3.6.1.1.1.1.1 NAME 'gidNumber' SYNTAX 1.3.6.1.4.1.1466.115.121.1.27 )",
+	})
+
+	attributes, diags := types.MapValueFrom(context.Background(), types.ListType{ElemType: types.StringType}, map[string][]string{
+		"cn":         {"Test User"},
+		"uidNumber":  {"10001"},
+		"pwdReset":   {"TRUE"},
+		"pwdLastSet": {"133860000000000000"},
+		// Converted by filetime_attributes: kept as strings
+		"accountExpires": {"never"},
+		"gidNumber":      {},
+		"description":    nil,
+	})
+	if diags.HasError() {
+		t.Fatalf("unable to build attributes: %v", diags)
+	}
+
+	typed, diags := typedAttributeValues(attributes, schema)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	number := func(s string) attr.Value {
+		v, _ := parseIntegerValue(s)
+		return v
+	}
+	want := map[string]attr.Value{
+		"cn":             types.ListValueMust(types.StringType, []attr.Value{types.StringValue("Test User")}),
+		"uidNumber":      types.ListValueMust(types.NumberType, []attr.Value{number("10001")}),
+		"pwdReset":       types.ListValueMust(types.BoolType, []attr.Value{types.BoolValue(true)}),
+		"pwdLastSet":     types.ListValueMust(types.NumberType, []attr.Value{number("133860000000000000")}),
+		"accountExpires": types.ListValueMust(types.StringType, []attr.Value{types.StringValue("never")}),
+		"gidNumber":      types.ListValueMust(types.NumberType, []attr.Value{}),
+		// Absent with missing_as_null
+		"description": types.ListNull(types.StringType),
+	}
+	for name, expected := range want {
+		if got := typed.Attributes()[name]; !expected.Equal(got) {
+			t.Errorf("%s = %s, want %s", name, got, expected)
+		}
+	}
+	if got := typed.Attributes()["uidNumber"].(types.List).Elements()[0].(types.Number).ValueBigFloat().String(); got != "10001" {
+		t.Errorf("uidNumber value = %s, want 10001", got)
+	}
+}
+
+func TestParseBooleanValue(t *testing.T) {
+	for input, expected := range map[string]attr.Value{"TRUE": types.BoolValue(true), "FALSE": types.BoolValue(false)} {
+		if got, ok := parseBooleanValue(input); !ok || !got.Equal(expected) {
+			t.Errorf("parseBooleanValue(%q) = %v, %t, want %v", input, got, ok, expected)
+		}
+	}
+	for _, input := range []string{"true", "yes", "1", ""} {
+		if _, ok := parseBooleanValue(input); ok {
+			t.Errorf("parseBooleanValue(%q) parsed, want error", input)
+		}
+	}
+}
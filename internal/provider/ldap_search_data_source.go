@@ -6,11 +6,17 @@ package provider
 import (
 	"context"
 	"fmt"
+	"math"
+	"math/big"
+	"strings"
 
 	"github.com/go-ldap/ldap/v3"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -24,7 +30,7 @@ func NewLdapSearchDataSource() datasource.DataSource {
 
 // LdapSearchDataSource defines the data source implementation.
 type LdapSearchDataSource struct {
-	conn *ldap.Conn
+	conn *LdapClient
 }
 
 // LdapSearchDataSourceModel describes the data source data model.
@@ -33,13 +39,31 @@ type LdapSearchDataSourceModel struct {
 	Scope               types.String `tfsdk:"scope"`
 	Filter              types.String `tfsdk:"filter"`
 	RequestedAttributes types.List   `tfsdk:"requested_attributes"`
+	BinaryAttributes    types.List   `tfsdk:"binary_attributes"`
+	SIDAttributes       types.List   `tfsdk:"sid_attributes"`
+	FileTimeAttributes  types.List   `tfsdk:"filetime_attributes"`
+	MissingAsNull       types.Bool   `tfsdk:"missing_as_null"`
+	FlattenSingleValued types.Bool   `tfsdk:"flatten_single_valued"`
+	AttributesOnly      types.Bool   `tfsdk:"attributes_only"`
+	IncludeOperational  types.Bool   `tfsdk:"include_operational"`
+	WarnIfOver          types.Int64  `tfsdk:"warn_if_over"`
+	PageSize            types.Int64  `tfsdk:"page_size"`
+	SortValues          types.Bool   `tfsdk:"sort_values"`
+	CountAttributes     types.List   `tfsdk:"count_attributes"`
+	TypedValues         types.Bool   `tfsdk:"typed_values"`
+	Bind                types.String `tfsdk:"bind"`
 	Results             types.List   `tfsdk:"results"`
+	AttributeNames      types.List   `tfsdk:"attribute_names"`
+
+	TypedResults types.Dynamic `tfsdk:"typed_results"`
 }
 
 // LdapSearchResultModel describes a single search result.
 type LdapSearchResultModel struct {
-	DN         types.String `tfsdk:"dn"`
-	Attributes types.Map    `tfsdk:"attributes"`
+	DN                  types.String `tfsdk:"dn"`
+	Attributes          types.Map    `tfsdk:"attributes"`
+	FlattenedAttributes types.Map    `tfsdk:"flattened_attributes"`
+	AttributeCounts     types.Map    `tfsdk:"attribute_counts"`
 }
 
 func (d *LdapSearchDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -52,7 +76,7 @@ func (d *LdapSearchDataSource) Schema(ctx context.Context, req datasource.Schema
 
 		Attributes: map[string]schema.Attribute{
 			"basedn": schema.StringAttribute{
-				MarkdownDescription: "Specifies the base DN that should be used for the search.",
+				MarkdownDescription: "Specifies the base DN that should be used for the search. Relative to the provider `base_dn` if it does not already end with it.",
 				Required:            true,
 			},
 			"scope": schema.StringAttribute{
@@ -64,10 +88,105 @@ func (d *LdapSearchDataSource) Schema(ctx context.Context, req datasource.Schema
 				Required:            true,
 			},
 			"requested_attributes": schema.ListAttribute{
-				MarkdownDescription: "Specifies which attribute(s) should be included in entries that match the search criteria. The value may be an attribute name or OID, a special token like '*' to indicate all user attributes or '+' to indicate all operational attributes, or an object class name prefixed by an '@' symbol to indicate all attributes associated with the specified object class. Multiple attributes may be requested.",
+				MarkdownDescription: "Specifies which attribute(s) should be included in entries that match the search criteria. The value may be an attribute name or OID, a special token like '*' to indicate all user attributes or '+' to indicate all operational attributes, or an object class name prefixed by an '@' symbol to indicate all attributes associated with the specified object class. An attribute name followed by `;*`, such as `description;*`, requests every option variant of the attribute, each returned under its own name (e.g. `description;lang-en` and `description;lang-de`). Multiple attributes may be requested. Operational attributes such as `entryDN` (the normalized DN on OpenLDAP) are only returned when named or when '+' is requested. " +
+					"Values that match none of these forms, such as `all` or `+all`, produce a warning, as the server silently ignores attributes it does not know.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					requestedAttributesValidator{},
+				},
+			},
+			"binary_attributes": schema.ListAttribute{
+				MarkdownDescription: "List of attribute types holding binary data, such as `jpegPhoto`, `userCertificate` or `objectGUID`. Values of these attributes are returned base64-encoded. Matching ignores case and attribute options, so `userCertificate` also covers `userCertificate;binary`.",
 				Optional:            true,
 				ElementType:         types.StringType,
 			},
+			"filetime_attributes": schema.ListAttribute{
+				MarkdownDescription: "List of attribute types holding Windows FILETIME timestamps (100-nanosecond intervals since 1601), such as `accountExpires`, `pwdLastSet`, `lastLogonTimestamp` or `msDS-UserPasswordExpiryTimeComputed`. " +
+					"Values of these attributes are returned as RFC 3339 timestamps in UTC, e.g. `2025-03-01T12:00:00Z`, or as `never` for the largest value (`9223372036854775807`), which Active Directory uses for passwords and accounts that do not expire. " +
+					"Note that `0` is returned as `1601-01-01T00:00:00Z`: depending on the attribute it means \"never set\" (`pwdLastSet`, `msDS-UserPasswordExpiryTimeComputed` of a user who must change the password) or \"never\" (`accountExpires`). " +
+					"Matching ignores case and attribute options. " +
+					"Constructed attributes such as `msDS-UserPasswordExpiryTimeComputed` are only returned for searches with `scope = \"base\"` that request them by name.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"sid_attributes": schema.ListAttribute{
+				MarkdownDescription: "List of attribute types holding binary Windows security identifiers, such as `objectSid` or `tokenGroups`. Values of these attributes are returned in string form, e.g. `S-1-5-21-1004336348-1177238915-682003330-512`, instead of raw bytes. " +
+					"Matching ignores case and attribute options. Takes precedence over `binary_attributes`. " +
+					"Note that Active Directory only returns constructed attributes such as `tokenGroups` for searches with `scope = \"base\"` that request them by name.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"missing_as_null": schema.BoolAttribute{
+				MarkdownDescription: "Whether attributes listed in `requested_attributes` but absent from an entry are returned as `null` instead of an empty list, distinguishing \"not present\" from \"empty\". Defaults to `false`.",
+				Optional:            true,
+			},
+			"flatten_single_valued": schema.BoolAttribute{
+				MarkdownDescription: "Whether to populate `flattened_attributes` in each result. The server schema is read from the subschema subentry named by the root DSE (once per provider instance) to find attribute types declared `SINGLE-VALUE`. Defaults to `false`.",
+				Optional:            true,
+			},
+			"typed_values": schema.BoolAttribute{
+				MarkdownDescription: "Whether to populate `typed_results`. The server schema is read from the subschema subentry named by the root DSE (once per provider instance) to find the syntax of each attribute type. Defaults to `false`.",
+				Optional:            true,
+			},
+			"attributes_only": schema.BoolAttribute{
+				MarkdownDescription: "Whether to request only attribute names, without values (the search `typesOnly` flag). Every attribute in `results` is then an empty list, and `attribute_names` summarizes which attributes the matching entries use. Defaults to `false`.",
+				Optional:            true,
+			},
+			"include_operational": schema.BoolAttribute{
+				MarkdownDescription: "Whether to also request all operational attributes, such as `entryDN`, `createTimestamp` or `memberOf` on some servers, by adding `+` to `requested_attributes`. " +
+					"Without `requested_attributes`, both `*` and `+` are requested, i.e. all user and all operational attributes. Defaults to `false`.",
+				Optional: true,
+			},
+			"warn_if_over": schema.Int64Attribute{
+				MarkdownDescription: "Number of entries above which the search produces a warning, to notice a filter or base DN that matches more than intended before it becomes a performance problem. " +
+					"The results are returned all the same. Defaults to no warning.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64BetweenValidator{min: 0, max: math.MaxInt32},
+				},
+			},
+			"page_size": schema.Int64Attribute{
+				MarkdownDescription: "Number of entries to fetch per page with the paged results control ([RFC 2696](https://www.rfc-editor.org/rfc/rfc2696)). " +
+					"Set it to read more entries than the server returns for one search, such as the 1000 entries of Active Directory's default `MaxPageSize`; " +
+					"without it, such searches fail with a size limit error. All pages are fetched and returned in `results`. " +
+					"Servers may return fewer entries per page than requested. The progress of the search is logged at `INFO` level after each page, e.g. with `TF_LOG_PROVIDER=INFO`. Defaults to no paging.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64BetweenValidator{min: 1, max: math.MaxInt32},
+				},
+			},
+			"sort_values": schema.BoolAttribute{
+				MarkdownDescription: "Whether to sort the values of each attribute in `results`, e.g. for readable `member` lists in outputs. LDAP attribute values are unordered, so this only changes presentation. Binary attributes are sorted by their base64 encoding. Defaults to `false`, keeping the order returned by the server.",
+				Optional:            true,
+			},
+			"count_attributes": schema.ListAttribute{
+				MarkdownDescription: "Attributes whose number of values is returned in `attribute_counts` of each result instead of the values themselves, e.g. `[\"member\"]` to monitor the size of large groups. " +
+					"They are requested in addition to `requested_attributes` and left out of `attributes`, so the values never reach the state. " +
+					"LDAP has no standard way to ask a server for the number of values only: the values are still transferred and counted by the provider (on Active Directory in ranges of 1500 values), which keeps state small but does not reduce the traffic. " +
+					"Names are matched ignoring case. Counts are `0` with `attributes_only`.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"bind": schema.StringAttribute{
+				MarkdownDescription: "Set to `anonymous` to run this search on a new, unbound connection instead of the provider's, e.g. to check what an unauthenticated client can see even though the provider binds as a privileged account. The connection is closed after the search. The search cache is not used. Defaults to the provider's connection.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringOneOfValidator{values: []string{"anonymous"}},
+				},
+			},
+			"attribute_names": schema.ListAttribute{
+				MarkdownDescription: "Sorted union of the names of the attributes returned for all results, listing names that differ only in case once.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"typed_results": schema.DynamicAttribute{
+				MarkdownDescription: "The results with attribute values typed by the syntax the server schema declares for the attribute type: a list of objects with `dn` and `attributes`, in the order of `results`. " +
+					"Like in `results`, every attribute is a list of values, but values of attributes with Integer syntax (e.g. `uidNumber`) or the Active Directory Large Integer syntax (e.g. `pwdLastSet`) are numbers, " +
+					"and values of attributes with Boolean syntax (`TRUE` or `FALSE`) are bools, so that e.g. `typed_results[0].attributes.uidNumber[0] + 1` needs no `tonumber()`. " +
+					"Attributes of any other or an unknown syntax, and attributes with a value that does not parse (e.g. a value converted by `filetime_attributes`), are lists of strings. Null unless `typed_values` is `true`.",
+				Computed: true,
+			},
 			"results": schema.ListNestedAttribute{
 				MarkdownDescription: "A list of search results. Each result contains the DN and attributes.",
 				Computed:            true,
@@ -82,6 +201,16 @@ func (d *LdapSearchDataSource) Schema(ctx context.Context, req datasource.Schema
 							Computed:            true,
 							ElementType:         types.ListType{ElemType: types.StringType},
 						},
+						"flattened_attributes": schema.MapAttribute{
+							MarkdownDescription: "The attributes whose type the server schema declares `SINGLE-VALUE`, mapped to their value as a plain string (e.g. `uidNumber`, but not `cn`). Null unless `flatten_single_valued` is `true`.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+						"attribute_counts": schema.MapAttribute{
+							MarkdownDescription: "The number of values of each attribute in `count_attributes`, keyed by its name as listed there; `0` if the entry does not have it. Null unless `count_attributes` is set.",
+							Computed:            true,
+							ElementType:         types.Int64Type,
+						},
 					},
 				},
 			},
@@ -118,31 +247,173 @@ func (d *LdapSearchDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		}
 	}
 
-	searchResult, err := LdapSearch(d.conn, data.BaseDN.ValueString(), scope, data.Filter.ValueString(), attributes)
+	if data.IncludeOperational.ValueBool() {
+		attributes = withOperationalAttributes(attributes)
+	}
+
+	var binaryAttributes []string
+	if !data.BinaryAttributes.IsNull() {
+		resp.Diagnostics.Append(data.BinaryAttributes.ElementsAs(ctx, &binaryAttributes, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var fileTimeAttributes []string
+	if !data.FileTimeAttributes.IsNull() {
+		resp.Diagnostics.Append(data.FileTimeAttributes.ElementsAs(ctx, &fileTimeAttributes, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var sidAttributes []string
+	if !data.SIDAttributes.IsNull() {
+		resp.Diagnostics.Append(data.SIDAttributes.ElementsAs(ctx, &sidAttributes, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var countAttributes []string
+	if !data.CountAttributes.IsNull() {
+		resp.Diagnostics.Append(data.CountAttributes.ElementsAs(ctx, &countAttributes, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Counted attributes are requested in addition to the others; without requested attributes
+	// the search returns all user attributes, which "*" keeps
+	searchAttributes := attributes
+	if len(countAttributes) > 0 {
+		if len(attributes) == 0 {
+			searchAttributes = []string{"*"}
+		}
+		searchAttributes = append(append([]string(nil), searchAttributes...), countAttributes...)
+	}
+
+	baseDN := d.conn.ResolveDN(data.BaseDN.ValueString())
+	searchOptions := LdapSearchOptions{
+		TypesOnly: data.AttributesOnly.ValueBool(),
+		PageSize:  uint32(data.PageSize.ValueInt64()),
+		OnPage: func(page int, entries int) {
+			tflog.Info(ctx, fmt.Sprintf("LDAP search below %s: fetched page %d, %d entries so far", baseDN, page, entries))
+		},
+	}
+
+	var searchResult *ldap.SearchResult
+	var err error
+	if data.Bind.ValueString() == "anonymous" {
+		anonymous, derr := d.conn.DialAnonymous()
+		if derr != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("bind"),
+				"Unable to connect to LDAP server",
+				fmt.Sprintf("Error opening an anonymous connection: %s", derr),
+			)
+			return
+		}
+		searchResult, err = LdapSearch(anonymous, baseDN, scope, data.Filter.ValueString(), searchAttributes, searchOptions)
+		anonymous.Close()
+	} else {
+		searchResult, err = d.conn.CachedSearch(baseDN, scope, data.Filter.ValueString(), searchAttributes, searchOptions)
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to perform LDAP search", err.Error())
 		return
 	}
 
-	results, err := MarshalLdapResults(ctx, searchResult, attributes)
+	counts, countedResult := countAttributeValues(searchResult, countAttributes)
+
+	results, err := MarshalLdapResults(ctx, countedResult, attributes, MarshalOptions{
+		BinaryAttributes:   binaryAttributes,
+		SIDAttributes:      sidAttributes,
+		FileTimeAttributes: fileTimeAttributes,
+		MissingAsNull:      data.MissingAsNull.ValueBool(),
+		FoldAttributeNames: d.conn.FoldsAttributeNames(),
+		SortValues:         data.SortValues.ValueBool(),
+		Lenient:            d.conn.LenientReads(),
+	})
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to convert LDAP search results", err.Error())
 		return
 	}
+	resp.Diagnostics.Append(marshalWarnings(results)...)
+	if !data.WarnIfOver.IsNull() {
+		resp.Diagnostics.Append(resultCountWarnings(len(results), data.WarnIfOver.ValueInt64())...)
+	}
+
+	var serverSchema *LdapSchema
+	if data.FlattenSingleValued.ValueBool() || data.TypedValues.ValueBool() {
+		serverSchema, err = d.conn.Schema(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to read LDAP schema", err.Error())
+			return
+		}
+	}
+
+	resultModels := make([]LdapSearchResultModel, 0, len(results))
+	for i, result := range results {
+		model := LdapSearchResultModel{
+			DN:                  result.DN,
+			Attributes:          result.Attributes,
+			FlattenedAttributes: types.MapNull(types.StringType),
+			AttributeCounts:     types.MapNull(types.Int64Type),
+		}
+
+		if counts != nil {
+			var diags diag.Diagnostics
+			model.AttributeCounts, diags = types.MapValueFrom(ctx, types.Int64Type, counts[i])
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+
+		if data.FlattenSingleValued.ValueBool() {
+			var diags diag.Diagnostics
+			model.FlattenedAttributes, diags = flattenSingleValuedAttributes(ctx, result.Attributes, serverSchema)
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+
+		resultModels = append(resultModels, model)
+	}
 
 	resultsList, diags := types.ListValueFrom(ctx, types.ObjectType{
 		AttrTypes: map[string]attr.Type{
-			"dn":         types.StringType,
-			"attributes": types.MapType{ElemType: types.ListType{ElemType: types.StringType}},
+			"dn":                   types.StringType,
+			"attributes":           types.MapType{ElemType: types.ListType{ElemType: types.StringType}},
+			"flattened_attributes": types.MapType{ElemType: types.StringType},
+			"attribute_counts":     types.MapType{ElemType: types.Int64Type},
 		},
-	}, results)
+	}, resultModels)
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
+	attributeNames, diags := types.ListValueFrom(ctx, types.StringType, AttributeNames(countedResult))
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	data.TypedResults = types.DynamicNull()
+	if data.TypedValues.ValueBool() {
+		data.TypedResults, diags = typedSearchResults(ctx, results, serverSchema)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	data.Results = resultsList
+	data.AttributeNames = attributeNames
 	data.Scope = types.StringValue(scope)
 
 	tflog.Trace(ctx, fmt.Sprintf("performed LDAP search with base DN: %s, scope: %s, filter: %s",
@@ -150,3 +421,208 @@ func (d *LdapSearchDataSource) Read(ctx context.Context, req datasource.ReadRequ
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
+
+// resultCountWarnings returns a warning if count, the number of entries a search returned, exceeds
+// threshold.
+func resultCountWarnings(count int, threshold int64) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if int64(count) > threshold {
+		diags.AddAttributeWarning(
+			path.Root("warn_if_over"),
+			"LDAP search returned many entries",
+			fmt.Sprintf("The search returned %d entries, more than warn_if_over (%d). Check whether filter and basedn only match the intended entries.", count, threshold),
+		)
+	}
+	return diags
+}
+
+// withOperationalAttributes returns the requested attributes with "+" (all operational attributes,
+// RFC 3673) added. Without requested attributes, which return all user attributes, "*" is added too
+// to keep them.
+func withOperationalAttributes(attributes []string) []string {
+	if len(attributes) == 0 {
+		return []string{"*", "+"}
+	}
+	for _, attribute := range attributes {
+		if attribute == "+" {
+			return attributes
+		}
+	}
+	return append(append([]string(nil), attributes...), "+")
+}
+
+// countAttributeValues returns, for each entry of sr, the number of values of each of
+// countAttributes, and sr without those attributes. Names are matched ignoring case. counts is nil
+// without countAttributes. sr itself, which may be cached, is not modified.
+func countAttributeValues(sr *ldap.SearchResult, countAttributes []string) ([]map[string]int64, *ldap.SearchResult) {
+	if len(countAttributes) == 0 {
+		return nil, sr
+	}
+
+	counts := make([]map[string]int64, len(sr.Entries))
+	stripped := &ldap.SearchResult{
+		Entries:   make([]*ldap.Entry, len(sr.Entries)),
+		Referrals: sr.Referrals,
+		Controls:  sr.Controls,
+	}
+	for i, entry := range sr.Entries {
+		counts[i] = make(map[string]int64, len(countAttributes))
+		for _, name := range countAttributes {
+			counts[i][name] = 0
+		}
+
+		kept := make([]*ldap.EntryAttribute, 0, len(entry.Attributes))
+		for _, a := range entry.Attributes {
+			counted := false
+			for _, name := range countAttributes {
+				if strings.EqualFold(a.Name, name) {
+					counts[i][name] += int64(len(a.Values))
+					counted = true
+				}
+			}
+			if !counted {
+				kept = append(kept, a)
+			}
+		}
+		stripped.Entries[i] = &ldap.Entry{DN: entry.DN, Attributes: kept}
+	}
+	return counts, stripped
+}
+
+// typedSearchResults returns results as a tuple of objects with dn and attributes, with the values of
+// attributes typed by typedAttributeValues.
+func typedSearchResults(ctx context.Context, results []LdapEntry, serverSchema *LdapSchema) (types.Dynamic, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	elementTypes := make([]attr.Type, 0, len(results))
+	elements := make([]attr.Value, 0, len(results))
+	for _, result := range results {
+		attributes, d := typedAttributeValues(result.Attributes, serverSchema)
+		diags.Append(d...)
+		if diags.HasError() {
+			return types.DynamicNull(), diags
+		}
+
+		objectTypes := map[string]attr.Type{
+			"dn":         types.StringType,
+			"attributes": attributes.Type(ctx),
+		}
+		object, d := types.ObjectValue(objectTypes, map[string]attr.Value{
+			"dn":         result.DN,
+			"attributes": attributes,
+		})
+		diags.Append(d...)
+		if diags.HasError() {
+			return types.DynamicNull(), diags
+		}
+
+		elementTypes = append(elementTypes, types.ObjectType{AttrTypes: objectTypes})
+		elements = append(elements, object)
+	}
+
+	tuple, d := types.TupleValue(elementTypes, elements)
+	diags.Append(d...)
+	return types.DynamicValue(tuple), diags
+}
+
+// typedAttributeValues returns attributes as an object whose attributes with Integer or Boolean
+// syntax in serverSchema are lists of numbers or bools. Other attributes, and those with a value
+// that does not parse as their syntax, remain lists of strings.
+func typedAttributeValues(attributes types.Map, serverSchema *LdapSchema) (types.Object, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	attributeTypes := make(map[string]attr.Type, len(attributes.Elements()))
+	attributeValues := make(map[string]attr.Value, len(attributes.Elements()))
+	for name, value := range attributes.Elements() {
+		attributeTypes[name] = value.Type(context.Background())
+		attributeValues[name] = value
+
+		list, ok := value.(types.List)
+		if !ok || list.IsNull() || list.IsUnknown() {
+			continue
+		}
+
+		var typed []attr.Value
+		var elementType attr.Type
+		switch serverSchema.Syntax(name) {
+		case syntaxInteger, syntaxLargeInteger:
+			typed, ok = typedListValues(list, parseIntegerValue)
+			elementType = types.NumberType
+		case syntaxBoolean:
+			typed, ok = typedListValues(list, parseBooleanValue)
+			elementType = types.BoolType
+		default:
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		typedList, d := types.ListValue(elementType, typed)
+		diags.Append(d...)
+		attributeTypes[name] = types.ListType{ElemType: elementType}
+		attributeValues[name] = typedList
+	}
+
+	result, d := types.ObjectValue(attributeTypes, attributeValues)
+	diags.Append(d...)
+	return result, diags
+}
+
+// typedListValues converts each string of list with parse. ok is false if any of them does not parse.
+func typedListValues(list types.List, parse func(string) (attr.Value, bool)) (values []attr.Value, ok bool) {
+	values = make([]attr.Value, 0, len(list.Elements()))
+	for _, element := range list.Elements() {
+		s, isString := element.(types.String)
+		if !isString || s.IsNull() || s.IsUnknown() {
+			return nil, false
+		}
+		value, parsed := parse(s.ValueString())
+		if !parsed {
+			return nil, false
+		}
+		values = append(values, value)
+	}
+	return values, true
+}
+
+// parseIntegerValue parses an RFC 4517 Integer, which may exceed 64 bits.
+func parseIntegerValue(s string) (attr.Value, bool) {
+	i, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, false
+	}
+	return types.NumberValue(new(big.Float).SetInt(i)), true
+}
+
+// parseBooleanValue parses an RFC 4517 Boolean, "TRUE" or "FALSE".
+func parseBooleanValue(s string) (attr.Value, bool) {
+	switch s {
+	case "TRUE":
+		return types.BoolValue(true), true
+	case "FALSE":
+		return types.BoolValue(false), true
+	}
+	return nil, false
+}
+
+// flattenSingleValuedAttributes returns the attributes declared SINGLE-VALUE by serverSchema that hold
+// exactly one value, mapped to that value.
+func flattenSingleValuedAttributes(ctx context.Context, attributes types.Map, serverSchema *LdapSchema) (types.Map, diag.Diagnostics) {
+	var values map[string][]string
+	diags := attributes.ElementsAs(ctx, &values, false)
+	if diags.HasError() {
+		return types.MapNull(types.StringType), diags
+	}
+
+	flattened := make(map[string]string)
+	for name, v := range values {
+		if len(v) == 1 && serverSchema.IsSingleValued(name) {
+			flattened[name] = v[0]
+		}
+	}
+
+	result, d := types.MapValueFrom(ctx, types.StringType, flattened)
+	diags.Append(d...)
+	return result, diags
+}
@@ -4,8 +4,12 @@
 package provider
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/go-ldap/ldap/v3"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
 	"github.com/hashicorp/terraform-plugin-testing/statecheck"
@@ -48,3 +52,669 @@ data "ldap_search" "base_search" {
 }
 `
 }
+
+func TestAccLdapSearchDataSource_BaseDNWithSpaces(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+data "ldap_search" "spaces" {
+  basedn = " OU=users , DC=example,  DC=com "
+  scope = "base"
+  filter = "(objectClass=*)"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					// The configured spelling is kept, the search finds the one entry
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.spaces",
+						tfjsonpath.New("basedn"),
+						knownvalue.StringExact(" OU=users , DC=example,  DC=com "),
+					),
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.spaces",
+						tfjsonpath.New("results"),
+						knownvalue.ListSizeExact(1),
+					),
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.spaces",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("dn"),
+						knownvalue.StringExact("ou=users,dc=example,dc=com"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccLdapSearchDataSource_MissingAsNull(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapSearchDataSourceConfigMissingAsNull(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.default",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("attributes").AtMapKey("description"),
+						knownvalue.ListSizeExact(0),
+					),
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.missing_as_null",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("attributes").AtMapKey("description"),
+						knownvalue.Null(),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccLdapSearchDataSourceConfigMissingAsNull() string {
+	return `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+# The base entry has no description
+data "ldap_search" "default" {
+  basedn = "dc=example,dc=com"
+  scope = "base"
+  filter = "(objectClass=*)"
+  requested_attributes = ["dc", "description"]
+}
+
+data "ldap_search" "missing_as_null" {
+  basedn = "dc=example,dc=com"
+  scope = "base"
+  filter = "(objectClass=*)"
+  requested_attributes = ["dc", "description"]
+  missing_as_null = true
+}
+`
+}
+
+func TestAccLdapSearchDataSource_FlattenSingleValued(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapSearchDataSourceConfigFlatten(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					// uidNumber is SINGLE-VALUE in the nis schema, cn is not
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.flattened",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("flattened_attributes"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							"uidNumber": knownvalue.StringExact("10001"),
+							"gidNumber": knownvalue.StringExact("10001"),
+						}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccLdapSearchDataSourceConfigFlatten() string {
+	return `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+resource "ldap_entry" "posix_user" {
+  dn = "uid=flatten,ou=users,dc=example,dc=com"
+  attributes = {
+    objectClass = ["inetOrgPerson", "posixAccount"]
+    cn = ["Flatten User"]
+    sn = ["User"]
+    uid = ["flatten"]
+    uidNumber = ["10001"]
+    gidNumber = ["10001"]
+    homeDirectory = ["/home/flatten"]
+  }
+}
+
+data "ldap_search" "flattened" {
+  basedn = ldap_entry.posix_user.dn
+  scope = "base"
+  filter = "(objectClass=*)"
+  requested_attributes = ["cn", "uidNumber", "gidNumber"]
+  flatten_single_valued = true
+}
+`
+}
+
+func TestAccLdapSearchDataSource_TypedValues(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapSearchDataSourceConfigTypedValues(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					// uidNumber has Integer syntax in the nis schema, cn has Directory String syntax
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.typed",
+						tfjsonpath.New("typed_results").AtSliceIndex(0).AtMapKey("attributes").AtMapKey("uidNumber"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.Int64Exact(10002)}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.typed",
+						tfjsonpath.New("typed_results").AtSliceIndex(0).AtMapKey("attributes").AtMapKey("cn"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("Typed User")}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.typed",
+						tfjsonpath.New("typed_results").AtSliceIndex(0).AtMapKey("dn"),
+						knownvalue.StringExact("uid=typed,ou=users,dc=example,dc=com"),
+					),
+					// Without typed_values
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.untyped",
+						tfjsonpath.New("typed_results"),
+						knownvalue.Null(),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccLdapSearchDataSourceConfigTypedValues() string {
+	return `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+resource "ldap_entry" "posix_user" {
+  dn = "uid=typed,ou=users,dc=example,dc=com"
+  attributes = {
+    objectClass = ["inetOrgPerson", "posixAccount"]
+    cn = ["Typed User"]
+    sn = ["User"]
+    uid = ["typed"]
+    uidNumber = ["10002"]
+    gidNumber = ["10002"]
+    homeDirectory = ["/home/typed"]
+  }
+}
+
+data "ldap_search" "typed" {
+  basedn = ldap_entry.posix_user.dn
+  scope = "base"
+  filter = "(objectClass=*)"
+  requested_attributes = ["cn", "uidNumber"]
+  typed_values = true
+}
+
+data "ldap_search" "untyped" {
+  basedn = ldap_entry.posix_user.dn
+  scope = "base"
+  filter = "(objectClass=*)"
+  requested_attributes = ["cn", "uidNumber"]
+}
+`
+}
+
+func TestAccLdapSearchDataSource_EntryDN(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapSearchDataSourceConfigEntryDN(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					// entryDN holds the normalized DN, regardless of how basedn was written
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.entry_dn",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("attributes"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							"entryDN": knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("dc=example,dc=com")}),
+						}),
+					),
+					// "+" returns operational attributes, including entryDN, without a phantom "+" attribute
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.operational",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("attributes").AtMapKey("entryDN"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("dc=example,dc=com")}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccLdapSearchDataSourceConfigEntryDN() string {
+	return `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+data "ldap_search" "entry_dn" {
+  basedn = "DC=Example, DC=Com"
+  scope = "base"
+  filter = "(objectClass=*)"
+  requested_attributes = ["entryDN"]
+}
+
+data "ldap_search" "operational" {
+  basedn = "dc=example,dc=com"
+  scope = "base"
+  filter = "(objectClass=*)"
+  requested_attributes = ["+"]
+}
+`
+}
+
+func TestAccLdapSearchDataSource_IncludeOperational(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+data "ldap_search" "test" {
+  basedn = "dc=example,dc=com"
+  scope = "base"
+  filter = "(objectClass=*)"
+  include_operational = true
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					// Both user and operational attributes, without phantom "*" and "+" attributes
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.test",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("attributes"),
+						knownvalue.MapPartial(map[string]knownvalue.Check{
+							"dc":      knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("example")}),
+							"entryDN": knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("dc=example,dc=com")}),
+						}),
+					),
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("data.ldap_search.test", "results.0.attributes.*"),
+					resource.TestCheckNoResourceAttr("data.ldap_search.test", "results.0.attributes.+"),
+				),
+			},
+		},
+	})
+}
+
+func TestResultCountWarnings(t *testing.T) {
+	tests := []struct {
+		name      string
+		count     int
+		threshold int64
+		expected  bool
+	}{
+		{name: "below", count: 5, threshold: 10},
+		{name: "at threshold", count: 10, threshold: 10},
+		{name: "above", count: 11, threshold: 10, expected: true},
+		{name: "any entry over zero", count: 1, threshold: 0, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := resultCountWarnings(tt.count, tt.threshold)
+			if diags.HasError() {
+				t.Fatalf("expected no error, got %v", diags)
+			}
+			if got := diags.WarningsCount() > 0; got != tt.expected {
+				t.Errorf("warning for %d entries over %d = %t, want %t", tt.count, tt.threshold, got, tt.expected)
+			}
+			if tt.expected && !strings.Contains(diags[0].Detail(), fmt.Sprintf("returned %d entries", tt.count)) {
+				t.Errorf("expected the warning to name the number of entries, got %q", diags[0].Detail())
+			}
+		})
+	}
+}
+
+func TestWithOperationalAttributes(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested []string
+		expected  []string
+	}{
+		{name: "none", requested: nil, expected: []string{"*", "+"}},
+		{name: "all user attributes", requested: []string{"*"}, expected: []string{"*", "+"}},
+		{name: "named attributes", requested: []string{"cn", "mail"}, expected: []string{"cn", "mail", "+"}},
+		{name: "already requested", requested: []string{"*", "+"}, expected: []string{"*", "+"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withOperationalAttributes(tt.requested); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("withOperationalAttributes(%q) = %q, want %q", tt.requested, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAccLdapSearchDataSource_AttributesOnly(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapSearchDataSourceConfigAttributesOnly(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.types_only",
+						tfjsonpath.New("attribute_names"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact("dc"),
+							knownvalue.StringExact("o"),
+							knownvalue.StringExact("objectClass"),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.types_only",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("attributes").AtMapKey("objectClass"),
+						knownvalue.ListSizeExact(0),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccLdapSearchDataSourceConfigAttributesOnly() string {
+	return `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+data "ldap_search" "types_only" {
+  basedn = "dc=example,dc=com"
+  scope = "base"
+  filter = "(objectClass=*)"
+  attributes_only = true
+}
+`
+}
+
+func TestAccLdapSearchDataSource_SortValues(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapSearchDataSourceConfigSortValues(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.sorted",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("attributes").AtMapKey("member"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact("uid=alice,ou=users,dc=example,dc=com"),
+							knownvalue.StringExact("uid=bob,ou=users,dc=example,dc=com"),
+							knownvalue.StringExact("uid=carol,ou=users,dc=example,dc=com"),
+						}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccLdapSearchDataSourceConfigSortValues() string {
+	return `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+resource "ldap_entry" "group" {
+  dn = "cn=sorted,ou=groups,dc=example,dc=com"
+  attributes = {
+    objectClass = ["groupOfNames"]
+    cn = ["sorted"]
+    member = [
+      "uid=carol,ou=users,dc=example,dc=com",
+      "uid=alice,ou=users,dc=example,dc=com",
+      "uid=bob,ou=users,dc=example,dc=com",
+    ]
+  }
+}
+
+data "ldap_search" "sorted" {
+  basedn = ldap_entry.group.dn
+  scope = "base"
+  filter = "(objectClass=*)"
+  requested_attributes = ["member"]
+  sort_values = true
+}
+`
+}
+
+func TestAccLdapSearchDataSource_CountAttributes(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapSearchDataSourceConfigCountAttributes(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.counted",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("attribute_counts"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							"member":      knownvalue.Int64Exact(3),
+							"description": knownvalue.Int64Exact(0),
+						}),
+					),
+					// Counted values are not returned
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.counted",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("attributes"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							"cn": knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("counted")}),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.uncounted",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("attribute_counts"),
+						knownvalue.Null(),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccLdapSearchDataSourceConfigCountAttributes() string {
+	return `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+resource "ldap_entry" "group" {
+  dn = "cn=counted,ou=groups,dc=example,dc=com"
+  attributes = {
+    objectClass = ["groupOfNames"]
+    cn = ["counted"]
+    member = [
+      "uid=alice,ou=users,dc=example,dc=com",
+      "uid=bob,ou=users,dc=example,dc=com",
+      "uid=carol,ou=users,dc=example,dc=com",
+    ]
+  }
+}
+
+data "ldap_search" "counted" {
+  basedn = ldap_entry.group.dn
+  scope = "base"
+  filter = "(objectClass=*)"
+  requested_attributes = ["cn"]
+  count_attributes = ["member", "description"]
+}
+
+data "ldap_search" "uncounted" {
+  basedn = ldap_entry.group.dn
+  scope = "base"
+  filter = "(objectClass=*)"
+  requested_attributes = ["cn"]
+}
+`
+}
+
+func TestCountAttributeValues(t *testing.T) {
+	sr := &ldap.SearchResult{
+		Entries: []*ldap.Entry{
+			ldap.NewEntry("cn=big,dc=example,dc=com", map[string][]string{
+				"cn":     {"big"},
+				"Member": {"uid=a", "uid=b", "uid=c"},
+			}),
+			ldap.NewEntry("cn=empty,dc=example,dc=com", map[string][]string{
+				"cn": {"empty"},
+			}),
+		},
+	}
+
+	counts, stripped := countAttributeValues(sr, []string{"member"})
+
+	wantCounts := []map[string]int64{{"member": 3}, {"member": 0}}
+	if !reflect.DeepEqual(counts, wantCounts) {
+		t.Errorf("counts = %v, want %v", counts, wantCounts)
+	}
+	for i, entry := range stripped.Entries {
+		if entry.DN != sr.Entries[i].DN {
+			t.Errorf("entry %d DN = %q, want %q", i, entry.DN, sr.Entries[i].DN)
+		}
+		if len(entry.GetEqualFoldAttributeValues("member")) != 0 {
+			t.Errorf("entry %d still has member values", i)
+		}
+		if got := entry.GetAttributeValue("cn"); got != sr.Entries[i].GetAttributeValue("cn") {
+			t.Errorf("entry %d cn = %q, want %q", i, got, sr.Entries[i].GetAttributeValue("cn"))
+		}
+	}
+	// The original result, which may be cached, is unchanged
+	if got := len(sr.Entries[0].GetAttributeValues("Member")); got != 3 {
+		t.Errorf("original entry has %d member values, want 3", got)
+	}
+
+	if counts, unchanged := countAttributeValues(sr, nil); counts != nil || unchanged != sr {
+		t.Errorf("countAttributeValues(nil) = %v, %p, want nil, %p", counts, unchanged, sr)
+	}
+}
+
+func TestAccLdapSearchDataSource_SearchCache(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapSearchDataSourceConfigSearchCache(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.first",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("dn"),
+						knownvalue.StringExact("dc=example,dc=com"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.second",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("dn"),
+						knownvalue.StringExact("dc=example,dc=com"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccLdapSearchDataSourceConfigSearchCache() string {
+	return `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+  search_cache = true
+}
+
+data "ldap_search" "first" {
+  basedn = "dc=example,dc=com"
+  scope = "base"
+  filter = "(objectClass=*)"
+}
+
+data "ldap_search" "second" {
+  basedn = "dc=example,dc=com"
+  scope = "base"
+  filter = "(objectClass=*)"
+}
+`
+}
+
+func TestAccLdapSearchDataSource_BindAnonymous(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapSearchDataSourceConfigBindAnonymous(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.test",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("dn"),
+						knownvalue.StringExact("dc=example,dc=com"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.test",
+						tfjsonpath.New("bind"),
+						knownvalue.StringExact("anonymous"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccLdapSearchDataSourceConfigBindAnonymous() string {
+	return `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+data "ldap_search" "test" {
+  basedn = "dc=example,dc=com"
+  scope = "base"
+  filter = "(objectClass=*)"
+  bind = "anonymous"
+}
+`
+}
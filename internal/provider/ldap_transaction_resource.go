@@ -0,0 +1,313 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &LdapTransactionResource{}
+var _ resource.ResourceWithConfigValidators = &LdapTransactionResource{}
+
+func NewLdapTransactionResource() resource.Resource {
+	return &LdapTransactionResource{}
+}
+
+// LdapTransactionResource applies a group of entry operations atomically in one LDAP transaction.
+type LdapTransactionResource struct {
+	client *LdapClient
+}
+
+// LdapTransactionResourceModel describes the resource data model for LDAP transactions.
+type LdapTransactionResourceModel struct {
+	Operations []LdapTransactionOperationModel `tfsdk:"operations"` // Operations applied in order within the transaction
+	Triggers   types.Map                       `tfsdk:"triggers"`   // Map[String] - arbitrary values; changing them applies the operations again
+	Id         types.String                    `tfsdk:"id"`         // Digest of the applied operations
+}
+
+// LdapTransactionOperationModel describes one operation of an LDAP transaction.
+type LdapTransactionOperationModel struct {
+	DN         types.String `tfsdk:"dn"`         // Entry the operation applies to
+	Action     types.String `tfsdk:"action"`     // One of transactionActions
+	Attributes types.Map    `tfsdk:"attributes"` // Map of List[String] - attributes of the entry or values to change
+}
+
+func (r *LdapTransactionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_transaction"
+}
+
+func (r *LdapTransactionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `Applies several entry operations atomically in one LDAP transaction ([RFC 5805](https://www.rfc-editor.org/rfc/rfc5805)): either all of them take effect or none does,
+e.g. to create a user and add it to a group without ever leaving a user outside the group.
+
+### Server support
+The server must support LDAP transactions, i.e. list ` + "`1.3.6.1.1.21.1`" + ` in the ` + "`supportedExtension`" + ` attribute of the Root DSE (see the ` + "`ldap_root_dse`" + ` data source).
+OpenLDAP supports them from version 2.5 on with the ` + "`mdb`" + ` backend; all entries of a transaction must be in the same database. Active Directory does not support them.
+
+### Lifecycle
+The operations are applied once, when the resource is created. They are not read back, so changes made outside Terraform are not detected.
+Changing ` + "`operations`" + ` or ` + "`triggers`" + ` replaces the resource, which applies the new operations as a new transaction.
+Destroying the resource only removes it from the state; the changes it applied are kept. Use ` + "`ldap_entry`" + ` to manage the lifecycle of entries.
+
+### Failures
+If an operation or the commit fails, the transaction is aborted and none of the operations is applied. The error names the failing operation where the server reports it before the commit.
+`,
+
+		Attributes: map[string]schema.Attribute{
+			"operations": schema.ListNestedAttribute{
+				MarkdownDescription: "Operations to apply, in order.",
+				Required:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"dn": schema.StringAttribute{
+							MarkdownDescription: "The distinguished name (DN) of the entry. Relative to the provider `base_dn` if it does not already end with it.",
+							Required:            true,
+						},
+						"action": schema.StringAttribute{
+							MarkdownDescription: "What to do with the entry: `add` creates it with `attributes`, `delete` deletes it, " +
+								"`add_values`, `delete_values` and `replace_values` add, delete or replace the values in `attributes`. " +
+								"With `delete_values`, an empty list deletes all values of the attribute.",
+							Required: true,
+							Validators: []validator.String{
+								stringOneOfValidator{values: transactionActions},
+							},
+						},
+						"attributes": schema.MapAttribute{
+							MarkdownDescription: "Attributes of the new entry for `add`, or the values to change for the other actions except `delete`, which takes none. " +
+								"Values are sent as given.",
+							Optional:    true,
+							ElementType: types.ListType{ElemType: types.StringType},
+						},
+					},
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				MarkdownDescription: "Arbitrary values that, when changed, apply the operations again as a new transaction.",
+				Optional:            true,
+				ElementType:         types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Digest of the applied operations.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *LdapTransactionResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		transactionOperationsValidator{},
+	}
+}
+
+func (r *LdapTransactionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = GetLdapConnection(req.ProviderData, &resp.Diagnostics, "Resource")
+}
+
+func (r *LdapTransactionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan LdapTransactionResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	operations := make([]transactionOperation, len(plan.Operations))
+	for i, op := range plan.Operations {
+		attributes := make(map[string][]string)
+		if !op.Attributes.IsNull() {
+			resp.Diagnostics.Append(unmarshalTerraformAttributes(ctx, &op.Attributes, attributes)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+
+		operations[i] = transactionOperation{
+			Action:     op.Action.ValueString(),
+			DN:         r.client.ResolveDN(op.DN.ValueString()),
+			Attributes: attributes,
+		}
+	}
+
+	if err := r.client.RunTransaction(operations); err != nil {
+		resp.Diagnostics.Append(transactionErrorDiagnostic(operations, err))
+		return
+	}
+	tflog.Trace(ctx, fmt.Sprintf("committed an LDAP transaction with %d operations", len(operations)))
+
+	plan.Id = types.StringValue(transactionID(operations))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read keeps the state as it is: the operations were applied once and are not read back.
+func (r *LdapTransactionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+}
+
+// Update is never called with a changed configuration, as every change replaces the resource.
+func (r *LdapTransactionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan LdapTransactionResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete removes the transaction from the state only; the changes it applied are kept.
+func (r *LdapTransactionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+
+// transactionErrorDiagnostic describes a failed transaction, naming the failed operation if known.
+func transactionErrorDiagnostic(operations []transactionOperation, err error) diag.Diagnostic {
+	var txnErr *transactionError
+	if !errors.As(err, &txnErr) {
+		return diag.NewErrorDiagnostic(
+			"Error running LDAP transaction",
+			fmt.Sprintf("Unable to run LDAP transaction: %s", err),
+		)
+	}
+
+	switch txnErr.Stage {
+	case transactionStageStart:
+		if d, ok := unsupportedExtendedOperationDiagnostic(txnErr.Err); ok {
+			return d
+		}
+		return diag.NewErrorDiagnostic(
+			"Unable to start LDAP transaction",
+			fmt.Sprintf("The server did not start a transaction, so no operation was applied. "+
+				"Check that it supports LDAP transactions (1.3.6.1.1.21.1 in the supportedExtension attribute of the Root DSE).\n\n"+
+				"Server response: %s", txnErr.Err),
+		)
+	case transactionStageOperation:
+		op := operations[txnErr.Operation]
+		detail := fmt.Sprintf("Operation %d (%s %s) failed, so the transaction was aborted and no operation was applied.\n\n"+
+			"Server response: %s", txnErr.Operation, op.Action, op.DN, txnErr.Err)
+		if txnErr.AbortErr != nil {
+			detail = fmt.Sprintf("Operation %d (%s %s) failed and the transaction could not be aborted: %s. "+
+				"No operation was committed; the server discards the transaction at the latest when the connection is closed.\n\n"+
+				"Server response: %s", txnErr.Operation, op.Action, op.DN, txnErr.AbortErr, txnErr.Err)
+		}
+		return diag.NewAttributeErrorDiagnostic(
+			path.Root("operations").AtListIndex(txnErr.Operation),
+			"LDAP transaction aborted",
+			detail,
+		)
+	default:
+		return diag.NewErrorDiagnostic(
+			"LDAP transaction not committed",
+			fmt.Sprintf("The server refused to commit the transaction, so no operation was applied. "+
+				"One of the operations may conflict with the directory, e.g. an entry to add already exists.\n\n"+
+				"Server response: %s", txnErr.Err),
+		)
+	}
+}
+
+// transactionID returns a digest identifying the operations of a transaction.
+func transactionID(operations []transactionOperation) string {
+	hash := sha256.New()
+	for _, op := range operations {
+		fmt.Fprintf(hash, "%s\x00%s\x00", op.Action, op.DN)
+		for _, name := range sortedAttributeNames(op.Attributes) {
+			fmt.Fprintf(hash, "%s\x00%s\x00", name, strings.Join(op.Attributes[name], "\x00"))
+		}
+		hash.Write([]byte{0xff})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// transactionOperationsValidator checks that each ldap_transaction operation has the attributes
+// its action needs, and that every DN parses.
+type transactionOperationsValidator struct{}
+
+var _ resource.ConfigValidator = transactionOperationsValidator{}
+
+func (v transactionOperationsValidator) Description(ctx context.Context) string {
+	return "each operation must have attributes matching its action and a valid dn"
+}
+
+func (v transactionOperationsValidator) MarkdownDescription(ctx context.Context) string {
+	return "each operation must have `attributes` matching its `action` and a valid `dn`"
+}
+
+func (v transactionOperationsValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var operations types.List
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("operations"), &operations)...)
+	if resp.Diagnostics.HasError() || operations.IsNull() || operations.IsUnknown() {
+		return
+	}
+
+	var models []LdapTransactionOperationModel
+	resp.Diagnostics.Append(operations.ElementsAs(ctx, &models, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for i, op := range models {
+		opPath := path.Root("operations").AtListIndex(i)
+
+		if !op.DN.IsNull() && !op.DN.IsUnknown() {
+			if _, err := ldap.ParseDN(op.DN.ValueString()); err != nil {
+				resp.Diagnostics.AddAttributeError(
+					opPath.AtName("dn"),
+					"Invalid DN",
+					fmt.Sprintf("Unable to parse DN %q: %s", op.DN.ValueString(), err),
+				)
+			}
+		}
+
+		if op.Action.IsNull() || op.Action.IsUnknown() || op.Attributes.IsUnknown() {
+			continue
+		}
+		action := op.Action.ValueString()
+		hasAttributes := !op.Attributes.IsNull() && len(op.Attributes.Elements()) > 0
+
+		switch {
+		case action == transactionActionDelete && hasAttributes:
+			resp.Diagnostics.AddAttributeError(
+				opPath.AtName("attributes"),
+				"Unexpected attributes",
+				"The delete action deletes the whole entry and takes no attributes.",
+			)
+		case action != transactionActionDelete && !hasAttributes:
+			resp.Diagnostics.AddAttributeError(
+				opPath.AtName("attributes"),
+				"Missing attributes",
+				fmt.Sprintf("The %s action needs at least one attribute.", action),
+			)
+		}
+	}
+}
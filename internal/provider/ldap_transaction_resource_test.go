@@ -0,0 +1,169 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccLdapTransactionResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			// Destroying the transaction keeps the user it added
+			t.Cleanup(func() {
+				if err := testAccDeleteLdapEntry("uid=txn-jdoe,ou=users,dc=example,dc=com"); err != nil {
+					t.Errorf("unable to delete the user added by the transaction: %s", err)
+				}
+			})
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			// The user is added and made a member of the group in one transaction
+			{
+				Config: testAccLdapTransactionResourceConfig("txn-jdoe", "ldap_entry.developers.dn"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("ldap_transaction.onboard", "id"),
+					testAccCheckLdapAttributeValues("ldap_entry.developers", "member", []string{
+						"cn=Manager,dc=example,dc=com",
+						"uid=txn-jdoe,ou=users,dc=example,dc=com",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccLdapTransactionResource_Rollback(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			// Adding the user to a group that does not exist fails the whole transaction
+			{
+				Config:      testAccLdapTransactionResourceConfig("txn-rollback", `"cn=txn-missing,ou=groups,dc=example,dc=com"`),
+				ExpectError: regexp.MustCompile(`LDAP transaction (aborted|not committed)`),
+			},
+			// ...so the user added first was never applied
+			{
+				Config: testAccLdapTransactionResourceConfigGroupOnly(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckLdapEntryAbsent("uid=txn-rollback,ou=users,dc=example,dc=com"),
+					testAccCheckLdapAttributeValues("ldap_entry.developers", "member", []string{"cn=Manager,dc=example,dc=com"}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccLdapTransactionResource_InvalidOperations(t *testing.T) {
+	config := func(operation string) string {
+		return testAccLdapEntryResourceConfigProviderOnly() + `
+resource "ldap_transaction" "test" {
+  operations = [` + operation + `]
+}
+`
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(`{ dn = "uid=jdoe,dc=example,dc=com", action = "delete", attributes = { uid = ["jdoe"] } }`),
+				ExpectError: regexp.MustCompile(`Unexpected attributes`),
+			},
+			{
+				Config:      config(`{ dn = "uid=jdoe,dc=example,dc=com", action = "add" }`),
+				ExpectError: regexp.MustCompile(`Missing attributes`),
+			},
+			{
+				Config:      config(`{ dn = "uid=jdoe,dc=example,dc=com", action = "replace_values", attributes = {} }`),
+				ExpectError: regexp.MustCompile(`Missing attributes`),
+			},
+			{
+				Config:      config(`{ dn = "not a dn", action = "delete" }`),
+				ExpectError: regexp.MustCompile(`Invalid DN`),
+			},
+			{
+				Config:      config(`{ dn = "uid=jdoe,dc=example,dc=com", action = "rename" }`),
+				ExpectError: regexp.MustCompile(`Invalid value`),
+			},
+		},
+	})
+}
+
+// testAccLdapTransactionResourceConfigGroupOnly returns the group the transactions add members to.
+// Its members are left to the transactions.
+func testAccLdapTransactionResourceConfigGroupOnly() string {
+	return testAccLdapEntryResourceConfigProviderOnly() + `
+resource "ldap_entry" "developers" {
+  dn = "cn=txn-developers,ou=groups,dc=example,dc=com"
+  attributes = {
+    objectClass = ["groupOfNames"]
+    cn          = ["txn-developers"]
+    member      = ["cn=Manager,dc=example,dc=com"]
+  }
+
+  lifecycle {
+    ignore_changes = [attributes]
+  }
+}
+`
+}
+
+// testAccLdapTransactionResourceConfig returns a transaction adding the user uid and making it a
+// member of the group group, an HCL expression.
+func testAccLdapTransactionResourceConfig(uid string, group string) string {
+	return testAccLdapTransactionResourceConfigGroupOnly() + fmt.Sprintf(`
+resource "ldap_transaction" "onboard" {
+  operations = [
+    {
+      dn     = "uid=%[1]s,ou=users,dc=example,dc=com"
+      action = "add"
+      attributes = {
+        objectClass = ["inetOrgPerson"]
+        uid         = [%[1]q]
+        cn          = ["John Doe"]
+        sn          = ["Doe"]
+      }
+    },
+    {
+      dn     = %[2]s
+      action = "add_values"
+      attributes = {
+        member = ["uid=%[1]s,ou=users,dc=example,dc=com"]
+      }
+    },
+  ]
+}
+`, uid, group)
+}
+
+// testAccCheckLdapEntryAbsent verifies on the server that the entry dn does not exist.
+func testAccCheckLdapEntryAbsent(dn string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		conn, err := testAccDialLdap()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		_, err = conn.Search(ldap.NewSearchRequest(dn, ldap.ScopeBaseObject, ldap.NeverDerefAliases, 0, 0, false, "(objectClass=*)", []string{"1.1"}, nil))
+		if err == nil {
+			return fmt.Errorf("LDAP entry %s exists", dn)
+		}
+		if !ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return fmt.Errorf("error searching for entry %s: %w", dn, err)
+		}
+		return nil
+	}
+}
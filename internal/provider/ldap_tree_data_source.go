@@ -0,0 +1,310 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &LdapTreeDataSource{}
+
+func NewLdapTreeDataSource() datasource.DataSource {
+	return &LdapTreeDataSource{}
+}
+
+// LdapTreeDataSource defines the data source implementation.
+type LdapTreeDataSource struct {
+	conn *LdapClient
+}
+
+// LdapTreeDataSourceModel describes the data source data model.
+type LdapTreeDataSourceModel struct {
+	BaseDN              types.String  `tfsdk:"basedn"`
+	Filter              types.String  `tfsdk:"filter"`
+	RequestedAttributes types.List    `tfsdk:"requested_attributes"`
+	MaxDepth            types.Int64   `tfsdk:"max_depth"`
+	Tree                types.Dynamic `tfsdk:"tree"`
+}
+
+func (d *LdapTreeDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tree"
+}
+
+func (d *LdapTreeDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reads a subtree into a nested structure mirroring the directory hierarchy, e.g. for exporting a small OU as structured data. " +
+			"Every node of `tree` is an object with the entry's `dn`, its `attributes` and its `children`, keyed by RDN, so " +
+			"`data.ldap_tree.example.tree.children[\"ou=users\"].children[\"uid=jdoe\"].attributes.mail` reaches an entry two levels below `basedn`. " +
+			"A directory is a tree, so every entry has exactly one place and cycles cannot occur. " +
+			"The whole subtree is read in a single search and held in state, so keep it to subtrees of modest size.",
+
+		Attributes: map[string]schema.Attribute{
+			"basedn": schema.StringAttribute{
+				MarkdownDescription: "Base DN of the subtree, which becomes the root of `tree`. Relative to the provider `base_dn` if it does not already end with it.",
+				Required:            true,
+			},
+			"filter": schema.StringAttribute{
+				MarkdownDescription: "Filter selecting the entries to include. An entry whose parent does not match is attached to its nearest matching ancestor, keyed by its DN relative to that ancestor (e.g. `uid=jdoe,ou=users`). Defaults to `(objectClass=*)`.",
+				Optional:            true,
+			},
+			"requested_attributes": schema.ListAttribute{
+				MarkdownDescription: "Attributes to include for each entry, as for `ldap_search`. Defaults to all user attributes.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"max_depth": schema.Int64Attribute{
+				MarkdownDescription: "Number of levels below `basedn` to include: `0` reads only the base entry, `1` also its immediate children, and so on. Defaults to no limit.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64BetweenValidator{min: 0, max: math.MaxInt32},
+				},
+			},
+			"tree": schema.DynamicAttribute{
+				MarkdownDescription: "Root node of the subtree: an object with `dn` (string), `attributes` (map of lists of strings, `null` if the base entry does not match `filter`) and `children` (object of nodes keyed by RDN, normalized with the attribute type in lower case, e.g. `OU=Users` becomes `ou=Users`).",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *LdapTreeDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.conn = GetLdapConnection(req.ProviderData, &resp.Diagnostics, "Data Source")
+}
+
+func (d *LdapTreeDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data LdapTreeDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filter := "(objectClass=*)"
+	if !data.Filter.IsNull() {
+		filter = data.Filter.ValueString()
+	}
+
+	var attributes []string
+	if !data.RequestedAttributes.IsNull() {
+		resp.Diagnostics.Append(data.RequestedAttributes.ElementsAs(ctx, &attributes, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	maxDepth := -1
+	if !data.MaxDepth.IsNull() {
+		maxDepth = int(data.MaxDepth.ValueInt64())
+	}
+
+	// Shallow trees need no subtree search
+	scope := "sub"
+	switch maxDepth {
+	case 0:
+		scope = "base"
+	case 1:
+		scope = "one"
+	}
+
+	baseDN := d.conn.ResolveDN(data.BaseDN.ValueString())
+	parsedBaseDN, err := ldap.ParseDN(baseDN)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("basedn"),
+			"Invalid base DN",
+			fmt.Sprintf("Unable to parse base DN %q: %s", baseDN, err),
+		)
+		return
+	}
+
+	sr, err := d.conn.CachedSearch(baseDN, scope, filter, attributes, LdapSearchOptions{})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to search subtree",
+			fmt.Sprintf("Unable to search %s with filter %s: %s", baseDN, filter, err),
+		)
+		return
+	}
+
+	// A one-level search does not return the base entry itself
+	if scope == "one" {
+		base, err := d.conn.CachedSearch(baseDN, "base", filter, attributes, LdapSearchOptions{})
+		if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			resp.Diagnostics.AddError(
+				"Failed to read base entry",
+				fmt.Sprintf("Unable to read %s: %s", baseDN, err),
+			)
+			return
+		}
+		if base != nil {
+			sr = &ldap.SearchResult{Entries: append(append([]*ldap.Entry{}, base.Entries...), sr.Entries...)}
+		}
+	}
+
+	results, err := MarshalLdapResults(ctx, sr, attributes, MarshalOptions{
+		FoldAttributeNames: d.conn.FoldsAttributeNames(),
+		Lenient:            d.conn.LenientReads(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to convert LDAP search results", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(marshalWarnings(results)...)
+
+	root, err := buildLdapTree(baseDN, parsedBaseDN, results, maxDepth)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to build tree", err.Error())
+		return
+	}
+
+	tree, diags := root.value()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Tree = types.DynamicValue(tree)
+	data.Filter = types.StringValue(filter)
+
+	tflog.Trace(ctx, fmt.Sprintf("read %d entries below %s into a tree", len(results), baseDN))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// ldapTreeNode is an entry of an ldap_tree together with its children.
+type ldapTreeNode struct {
+	dn         string
+	attributes types.Map
+	children   map[string]*ldapTreeNode
+}
+
+// buildLdapTree nests entries below the node for baseDN. Entries are attached to their nearest
+// ancestor among entries, keyed by their DN relative to it, and are processed from the shallowest
+// up so ancestors are always in place first. Entries deeper than maxDepth (if not negative) or
+// outside baseDN are skipped.
+func buildLdapTree(baseDN string, parsedBaseDN *ldap.DN, entries []LdapEntry, maxDepth int) (*ldapTreeNode, error) {
+	type parsedEntry struct {
+		entry LdapEntry
+		dn    *ldap.DN
+	}
+
+	parsed := make([]parsedEntry, 0, len(entries))
+	for _, entry := range entries {
+		dn, err := ldap.ParseDN(entry.DN.ValueString())
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse DN %q: %s", entry.DN.ValueString(), err)
+		}
+		parsed = append(parsed, parsedEntry{entry: entry, dn: dn})
+	}
+	sort.SliceStable(parsed, func(i, j int) bool {
+		return len(parsed[i].dn.RDNs) < len(parsed[j].dn.RDNs)
+	})
+
+	root := &ldapTreeNode{
+		dn:         baseDN,
+		attributes: types.MapNull(types.ListType{ElemType: types.StringType}),
+		children:   make(map[string]*ldapTreeNode),
+	}
+	baseDepth := len(parsedBaseDN.RDNs)
+	nodes := map[string]*ldapTreeNode{treeNodeKey(parsedBaseDN.RDNs): root}
+
+	for _, p := range parsed {
+		depth := len(p.dn.RDNs) - baseDepth
+		if depth < 0 || (maxDepth >= 0 && depth > maxDepth) || !(depth == 0 || parsedBaseDN.AncestorOfFold(p.dn)) {
+			continue
+		}
+
+		if depth == 0 {
+			if p.dn.EqualFold(parsedBaseDN) {
+				root.dn = p.entry.DN.ValueString()
+				root.attributes = p.entry.Attributes
+			}
+			continue
+		}
+
+		node := &ldapTreeNode{
+			dn:         p.entry.DN.ValueString(),
+			attributes: p.entry.Attributes,
+			children:   make(map[string]*ldapTreeNode),
+		}
+		nodes[treeNodeKey(p.dn.RDNs)] = node
+
+		// Find the nearest ancestor in the tree; the base node always matches
+		for i := 1; i <= depth; i++ {
+			if parent, ok := nodes[treeNodeKey(p.dn.RDNs[i:])]; ok {
+				parent.children[relativeDNString(p.dn.RDNs[:i])] = node
+				break
+			}
+		}
+	}
+
+	return root, nil
+}
+
+// treeNodeKey identifies a DN by its RDNs, ignoring case.
+func treeNodeKey(rdns []*ldap.RelativeDN) string {
+	return strings.ToLower(relativeDNString(rdns))
+}
+
+// relativeDNString formats rdns as a DN string.
+func relativeDNString(rdns []*ldap.RelativeDN) string {
+	parts := make([]string, len(rdns))
+	for i, rdn := range rdns {
+		parts[i] = rdn.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// value converts the node and its descendants into a Terraform object.
+func (n *ldapTreeNode) value() (types.Object, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	childTypes := make(map[string]attr.Type, len(n.children))
+	childValues := make(map[string]attr.Value, len(n.children))
+	for key, child := range n.children {
+		value, childDiags := child.value()
+		diags.Append(childDiags...)
+		if diags.HasError() {
+			return types.ObjectNull(nil), diags
+		}
+		childTypes[key] = value.Type(context.Background())
+		childValues[key] = value
+	}
+
+	children, childDiags := types.ObjectValue(childTypes, childValues)
+	diags.Append(childDiags...)
+	if diags.HasError() {
+		return types.ObjectNull(nil), diags
+	}
+
+	node, nodeDiags := types.ObjectValue(
+		map[string]attr.Type{
+			"dn":         types.StringType,
+			"attributes": types.MapType{ElemType: types.ListType{ElemType: types.StringType}},
+			"children":   children.Type(context.Background()),
+		},
+		map[string]attr.Value{
+			"dn":         types.StringValue(n.dn),
+			"attributes": n.attributes,
+			"children":   children,
+		},
+	)
+	diags.Append(nodeDiags...)
+	return node, diags
+}
@@ -0,0 +1,173 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+// treeShape renders a tree as sorted "parent key -> child key" lines.
+func treeShape(node *ldapTreeNode, prefix string, lines *[]string) {
+	for key, child := range node.children {
+		*lines = append(*lines, prefix+key+" ("+child.dn+")")
+		treeShape(child, prefix+key+"/", lines)
+	}
+}
+
+func TestBuildLdapTree(t *testing.T) {
+	tests := []struct {
+		name     string
+		dns      []string
+		maxDepth int
+		expected []string
+		baseRead bool
+	}{
+		{
+			name:     "nested",
+			dns:      []string{"uid=jdoe,ou=users,dc=example,dc=com", "dc=example,dc=com", "ou=users,dc=example,dc=com", "ou=groups,dc=example,dc=com"},
+			maxDepth: -1,
+			baseRead: true,
+			expected: []string{
+				"ou=groups (ou=groups,dc=example,dc=com)",
+				"ou=users (ou=users,dc=example,dc=com)",
+				"ou=users/uid=jdoe (uid=jdoe,ou=users,dc=example,dc=com)",
+			},
+		},
+		{
+			name:     "missing intermediate entry",
+			dns:      []string{"uid=jdoe,ou=users,dc=example,dc=com"},
+			maxDepth: -1,
+			expected: []string{
+				"uid=jdoe,ou=users (uid=jdoe,ou=users,dc=example,dc=com)",
+			},
+		},
+		{
+			name:     "depth limit",
+			dns:      []string{"uid=jdoe,ou=users,dc=example,dc=com", "ou=users,dc=example,dc=com"},
+			maxDepth: 1,
+			expected: []string{
+				"ou=users (ou=users,dc=example,dc=com)",
+			},
+		},
+		{
+			name:     "case differences",
+			dns:      []string{"UID=jdoe,OU=Users,DC=Example,DC=Com", "OU=Users,DC=Example,DC=Com"},
+			maxDepth: -1,
+			expected: []string{
+				"ou=Users (OU=Users,DC=Example,DC=Com)",
+				"ou=Users/uid=jdoe (UID=jdoe,OU=Users,DC=Example,DC=Com)",
+			},
+		},
+		{
+			name:     "outside base",
+			dns:      []string{"ou=users,dc=other,dc=com"},
+			maxDepth: -1,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := &ldap.SearchResult{}
+			for _, dn := range tt.dns {
+				sr.Entries = append(sr.Entries, ldap.NewEntry(dn, map[string][]string{"objectClass": {"top"}}))
+			}
+			entries, err := MarshalLdapResults(context.Background(), sr, nil, MarshalOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			baseDN, _ := ldap.ParseDN("dc=example,dc=com")
+			root, err := buildLdapTree("dc=example,dc=com", baseDN, entries, tt.maxDepth)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			var lines []string
+			treeShape(root, "", &lines)
+			sort.Strings(lines)
+			if strings.Join(lines, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("tree =\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(tt.expected, "\n"))
+			}
+
+			if root.attributes.IsNull() == tt.baseRead {
+				t.Errorf("base attributes null = %t, want %t", root.attributes.IsNull(), !tt.baseRead)
+			}
+
+			if _, diags := root.value(); diags.HasError() {
+				t.Errorf("unable to convert tree: %v", diags)
+			}
+		})
+	}
+}
+
+func TestAccLdapTreeDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapTreeDataSourceConfig(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.ldap_tree.test",
+						tfjsonpath.New("tree").AtMapKey("dn"),
+						knownvalue.StringExact("ou=tree,dc=example,dc=com"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.ldap_tree.test",
+						tfjsonpath.New("tree").AtMapKey("children").AtMapKey("ou=team").AtMapKey("children").AtMapKey("cn=alice").AtMapKey("attributes").AtMapKey("sn"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("Alice")}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccLdapTreeDataSourceConfig() string {
+	return testAccLdapEntryResourceConfigProviderOnly() + `
+resource "ldap_entry" "tree" {
+  dn = "ou=tree,dc=example,dc=com"
+  attributes = {
+    objectClass = ["organizationalUnit"]
+    ou = ["tree"]
+  }
+}
+
+resource "ldap_entry" "team" {
+  dn = "ou=team,${ldap_entry.tree.dn}"
+  attributes = {
+    objectClass = ["organizationalUnit"]
+    ou = ["team"]
+  }
+}
+
+resource "ldap_entry" "alice" {
+  dn = "cn=alice,${ldap_entry.team.dn}"
+  attributes = {
+    objectClass = ["person"]
+    cn = ["alice"]
+    sn = ["Alice"]
+  }
+}
+
+data "ldap_tree" "test" {
+  basedn               = ldap_entry.tree.dn
+  requested_attributes = ["sn"]
+
+  depends_on = [ldap_entry.alice]
+}
+`
+}
@@ -0,0 +1,156 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &LdapURLFunction{}
+
+func NewLdapURLFunction() function.Function {
+	return &LdapURLFunction{}
+}
+
+// LdapURLFunction assembles an RFC 4516 LDAP URL.
+type LdapURLFunction struct{}
+
+func (f *LdapURLFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "ldap_url"
+}
+
+func (f *LdapURLFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Build an LDAP URL",
+		MarkdownDescription: "Assembles an RFC 4516 LDAP URL of the form `ldap://host/dn?attributes?scope?filter`, percent-encoding the DN, attributes and filter. " +
+			"For example `ldap_url(\"ldap.example.com\", \"ou=Sales & Marketing,dc=example,dc=com\", [\"cn\", \"mail\"], \"sub\", \"(cn=Jane Doe)\")` returns " +
+			"`ldap://ldap.example.com/ou=Sales%20&%20Marketing,dc=example,dc=com?cn,mail?sub?(cn=Jane%20Doe)`. " +
+			"Trailing empty components are omitted. Errors if `dn`, `scope` or `filter` is invalid.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "host",
+				MarkdownDescription: "Server as `host` or `host:port`, optionally prefixed with `ldap://` or `ldaps://` (the default scheme is `ldap`). May be empty to refer to a server known from context.",
+			},
+			function.StringParameter{
+				Name:                "dn",
+				MarkdownDescription: "Base DN, or empty.",
+			},
+			function.ListParameter{
+				Name:                "attributes",
+				MarkdownDescription: "Attributes to return, or an empty list for all user attributes.",
+				ElementType:         types.StringType,
+			},
+			function.StringParameter{
+				Name:                "scope",
+				MarkdownDescription: "One of `base`, `one` or `sub`, or empty for the default (`base`).",
+			},
+			function.StringParameter{
+				Name:                "filter",
+				MarkdownDescription: "Search filter, or empty for the default (`(objectClass=*)`).",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *LdapURLFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var host, dn, scope, filter string
+	var attributes []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &host, &dn, &attributes, &scope, &filter))
+	if resp.Error != nil {
+		return
+	}
+
+	scheme := "ldap"
+	if i := strings.Index(host, "://"); i >= 0 {
+		scheme = strings.ToLower(host[:i])
+		host = host[i+3:]
+		if scheme != "ldap" && scheme != "ldaps" {
+			resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("scheme must be ldap or ldaps, got %q", scheme))
+			return
+		}
+	}
+	if strings.ContainsAny(host, "/?#") {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("host must be a host name with an optional port, got %q", host))
+		return
+	}
+
+	if dn != "" {
+		if err := validateDN(dn); err != nil {
+			resp.Error = function.NewArgumentFuncError(1, err.Error())
+			return
+		}
+	}
+
+	for _, attr := range attributes {
+		if attr == "" {
+			resp.Error = function.NewArgumentFuncError(2, "attributes must not contain empty names")
+			return
+		}
+	}
+
+	if scope != "" {
+		if _, err := ConvertHumanReadableLDAPScope(scope); err != nil {
+			resp.Error = function.NewArgumentFuncError(3, err.Error())
+			return
+		}
+	}
+
+	if filter != "" {
+		if _, err := ldap.CompileFilter(filter); err != nil {
+			resp.Error = function.NewArgumentFuncError(4, fmt.Sprintf("invalid filter %q: %s", filter, err))
+			return
+		}
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, formatLdapURL(scheme, host, dn, attributes, scope, filter)))
+}
+
+// formatLdapURL assembles an RFC 4516 LDAP URL, dropping trailing empty components.
+func formatLdapURL(scheme, host, dn string, attributes []string, scope, filter string) string {
+	encodedAttributes := make([]string, len(attributes))
+	for i, attr := range attributes {
+		encodedAttributes[i] = ldapURLEscape(attr, true)
+	}
+
+	components := []string{ldapURLEscape(dn, false), strings.Join(encodedAttributes, ","), scope, ldapURLEscape(filter, false)}
+	for len(components) > 1 && components[len(components)-1] == "" {
+		components = components[:len(components)-1]
+	}
+
+	url := scheme + "://" + host
+	if len(components) > 1 || components[0] != "" {
+		url += "/" + strings.Join(components, "?")
+	}
+	return url
+}
+
+// ldapURLEscape percent-encodes s for use in an LDAP URL component. Unreserved characters and the
+// sub-delimiters allowed by RFC 3986 are kept, except "," when escapeComma is set (it separates
+// attributes). "?" (the component separator), "/", "%", "\", spaces and non-ASCII bytes are encoded.
+func ldapURLEscape(s string, escapeComma bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+			b.WriteByte(c)
+		case strings.IndexByte("-._~!$&'()*+;=:@", c) >= 0:
+			b.WriteByte(c)
+		case c == ',' && !escapeComma:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,157 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestLdapURLFunction_Run(t *testing.T) {
+	tests := []struct {
+		name        string
+		host        string
+		dn          string
+		attributes  []string
+		scope       string
+		filter      string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:       "rfc 4516 example",
+			host:       "ldap1.example.net",
+			dn:         "o=University of Michigan,c=US",
+			attributes: []string{},
+			scope:      "sub",
+			filter:     "(cn=Babs Jensen)",
+			expected:   "ldap://ldap1.example.net/o=University%20of%20Michigan,c=US??sub?(cn=Babs%20Jensen)",
+		},
+		{
+			name:       "attributes",
+			host:       "ldap.example.com:389",
+			dn:         "dc=example,dc=com",
+			attributes: []string{"cn", "mail"},
+			scope:      "one",
+			filter:     "",
+			expected:   "ldap://ldap.example.com:389/dc=example,dc=com?cn,mail?one",
+		},
+		{
+			name:       "dn only",
+			host:       "ldaps://ldap.example.com",
+			dn:         "uid=jdoe,ou=users,dc=example,dc=com",
+			attributes: []string{},
+			expected:   "ldaps://ldap.example.com/uid=jdoe,ou=users,dc=example,dc=com",
+		},
+		{
+			name:       "host only",
+			host:       "ldap.example.com",
+			attributes: []string{},
+			expected:   "ldap://ldap.example.com",
+		},
+		{
+			name:       "empty host",
+			host:       "",
+			dn:         "dc=example,dc=com",
+			attributes: []string{},
+			expected:   "ldap:///dc=example,dc=com",
+		},
+		{
+			name:       "special characters in dn",
+			host:       "ldap.example.com",
+			dn:         `cn=Doe\, John?,ou=R/D #1,dc=example,dc=com`,
+			attributes: []string{},
+			expected:   "ldap://ldap.example.com/cn=Doe%5C,%20John%3F,ou=R%2FD%20%231,dc=example,dc=com",
+		},
+		{
+			name:       "special characters in filter",
+			host:       "ldap.example.com",
+			dn:         "dc=example,dc=com",
+			attributes: []string{"cn"},
+			scope:      "sub",
+			filter:     `(&(cn=100%\2a)(description=a?b))`,
+			expected:   "ldap://ldap.example.com/dc=example,dc=com?cn?sub?(&(cn=100%25%5C2a)(description=a%3Fb))",
+		},
+		{
+			name:       "non-ascii",
+			host:       "ldap.example.com",
+			dn:         "ou=Zürich,dc=example,dc=com",
+			attributes: []string{},
+			expected:   "ldap://ldap.example.com/ou=Z%C3%BCrich,dc=example,dc=com",
+		},
+		{
+			name:        "invalid scheme",
+			host:        "https://ldap.example.com",
+			attributes:  []string{},
+			expectError: true,
+		},
+		{
+			name:        "host with path",
+			host:        "ldap.example.com/dc=com",
+			attributes:  []string{},
+			expectError: true,
+		},
+		{
+			name:        "invalid dn",
+			host:        "ldap.example.com",
+			dn:          "not a dn",
+			attributes:  []string{},
+			expectError: true,
+		},
+		{
+			name:        "invalid scope",
+			host:        "ldap.example.com",
+			attributes:  []string{},
+			scope:       "subtree",
+			expectError: true,
+		},
+		{
+			name:        "invalid filter",
+			host:        "ldap.example.com",
+			attributes:  []string{},
+			filter:      "(cn=a",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attributes, _ := types.ListValueFrom(context.Background(), types.StringType, tt.attributes)
+
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue(tt.host),
+					types.StringValue(tt.dn),
+					attributes,
+					types.StringValue(tt.scope),
+					types.StringValue(tt.filter),
+				}),
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.StringUnknown()),
+			}
+
+			NewLdapURLFunction().Run(context.Background(), req, resp)
+
+			if tt.expectError {
+				if resp.Error == nil {
+					t.Errorf("expected error, got result %s", resp.Result.Value())
+				}
+				return
+			}
+
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+
+			if !resp.Result.Value().Equal(types.StringValue(tt.expected)) {
+				t.Errorf("result = %s, want %q", resp.Result.Value(), tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/base64"
+	"sort"
+	"strings"
+)
+
+// ldifLineLength is the length after which LDIF lines are folded, as recommended by RFC 2849.
+const ldifLineLength = 76
+
+// ldifAddRecord returns the LDIF (RFC 2849) change record that adds the entry dn with attributes.
+// objectClass comes first, the other attributes follow sorted by name, each value on its own line.
+// Values that cannot be written as they are, such as binary values, are base64-encoded.
+func ldifAddRecord(dn string, attributes map[string][]string) string {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		iObjectClass := strings.EqualFold(names[i], "objectClass")
+		jObjectClass := strings.EqualFold(names[j], "objectClass")
+		if iObjectClass != jObjectClass {
+			return iObjectClass
+		}
+		return names[i] < names[j]
+	})
+
+	var b strings.Builder
+	b.WriteString(ldifLine("dn", dn))
+	b.WriteString(ldifLine("changetype", "add"))
+	for _, name := range names {
+		for _, value := range attributes[name] {
+			b.WriteString(ldifLine(name, value))
+		}
+	}
+	return b.String()
+}
+
+// ldifLine returns the line "name: value", or "name:: <base64 of value>" if value is not a safe
+// string, folded after ldifLineLength characters and terminated by a newline.
+func ldifLine(name string, value string) string {
+	line := name + ": " + value
+	if !ldifSafeString(value) {
+		line = name + ":: " + base64.StdEncoding.EncodeToString([]byte(value))
+	}
+
+	// Continuation lines start with a space, which leaves room for one character less
+	var b strings.Builder
+	for width := ldifLineLength; len(line) > width; width = ldifLineLength - 1 {
+		b.WriteString(line[:width])
+		b.WriteString("\n ")
+		line = line[width:]
+	}
+	b.WriteString(line)
+	b.WriteString("\n")
+	return b.String()
+}
+
+// ldifSafeString reports whether value can be written in LDIF as it is: a SAFE-STRING of RFC 2849,
+// i.e. ASCII without NUL, LF and CR, not starting with a space, ':' or '<'. Values ending with a
+// space are not safe either, as RFC 2849 recommends, so that the space is not lost.
+func ldifSafeString(value string) bool {
+	if value == "" {
+		return true
+	}
+	switch value[0] {
+	case ' ', ':', '<':
+		return false
+	}
+	if value[len(value)-1] == ' ' {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c == 0 || c == '\n' || c == '\r' || c > 127 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLdifAddRecord(t *testing.T) {
+	tests := []struct {
+		name       string
+		dn         string
+		attributes map[string][]string
+		expected   string
+	}{
+		{
+			name: "multi-valued attribute",
+			dn:   "cn=developers,ou=groups,dc=example,dc=com",
+			attributes: map[string][]string{
+				"member":      {"uid=jdoe,ou=users,dc=example,dc=com", "uid=asmith,ou=users,dc=example,dc=com"},
+				"cn":          {"developers"},
+				"objectClass": {"top", "groupOfNames"},
+			},
+			expected: "dn: cn=developers,ou=groups,dc=example,dc=com\n" +
+				"changetype: add\n" +
+				"objectClass: top\n" +
+				"objectClass: groupOfNames\n" +
+				"cn: developers\n" +
+				"member: uid=jdoe,ou=users,dc=example,dc=com\n" +
+				"member: uid=asmith,ou=users,dc=example,dc=com\n",
+		},
+		{
+			name: "unsafe values",
+			dn:   "cn=Jürgen,dc=example,dc=com",
+			attributes: map[string][]string{
+				"objectclass":     {"person"},
+				"description":     {"line one\nline two"},
+				"sn":              {" leading space"},
+				"jpegPhoto":       {"\xff\xd8\xff\x00"},
+				"telephoneNumber": {""},
+			},
+			expected: "dn:: Y249SsO8cmdlbixkYz1leGFtcGxlLGRjPWNvbQ==\n" +
+				"changetype: add\n" +
+				"objectclass: person\n" +
+				"description:: bGluZSBvbmUKbGluZSB0d28=\n" +
+				"jpegPhoto:: /9j/AA==\n" +
+				"sn:: IGxlYWRpbmcgc3BhY2U=\n" +
+				"telephoneNumber: \n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ldifAddRecord(tt.dn, tt.attributes); got != tt.expected {
+				t.Errorf("ldifAddRecord() =\n%s\nwant\n%s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLdifLine_Folding(t *testing.T) {
+	value := strings.Repeat("x", 200)
+
+	line := ldifLine("description", value)
+
+	lines := strings.Split(strings.TrimSuffix(line, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected the line to be folded into 3 lines, got %q", lines)
+	}
+	unfolded := lines[0]
+	for _, continuation := range lines[1:] {
+		if !strings.HasPrefix(continuation, " ") {
+			t.Errorf("continuation line %q does not start with a space", continuation)
+		}
+		unfolded += continuation[1:]
+	}
+	for _, l := range lines {
+		if len(l) > ldifLineLength {
+			t.Errorf("line %q is longer than %d characters", l, ldifLineLength)
+		}
+	}
+	if unfolded != "description: "+value {
+		t.Errorf("unfolded line = %q", unfolded)
+	}
+}
+
+func TestLdifSafeString(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected bool
+	}{
+		{"", true},
+		{"John Doe", true},
+		{"a:b<c", true},
+		{" leading", false},
+		{"trailing ", false},
+		{":colon", false},
+		{"<less", false},
+		{"with\nnewline", false},
+		{"with\rreturn", false},
+		{"with\x00nul", false},
+		{"Jürgen", false},
+	}
+
+	for _, tt := range tests {
+		if got := ldifSafeString(tt.value); got != tt.expected {
+			t.Errorf("ldifSafeString(%q) = %t, want %t", tt.value, got, tt.expected)
+		}
+	}
+}
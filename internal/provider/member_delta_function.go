@@ -0,0 +1,77 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &MemberDeltaFunction{}
+
+func NewMemberDeltaFunction() function.Function {
+	return &MemberDeltaFunction{}
+}
+
+// MemberDeltaFunction computes the values ldap_entry adds and removes to turn one value list into another.
+type MemberDeltaFunction struct{}
+
+// memberDelta is the result of member_delta.
+type memberDelta struct {
+	Add    []string `tfsdk:"add"`    // Values in desired but not in current
+	Remove []string `tfsdk:"remove"` // Values in current but not in desired
+}
+
+func (f *MemberDeltaFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "member_delta"
+}
+
+func (f *MemberDeltaFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Compute the values to add and remove between two value lists",
+		MarkdownDescription: "Returns the values an update from `current` to `desired` adds and removes, as an object with the sorted lists `add` and `remove`, " +
+			"e.g. to log or review group membership changes before they are applied. " +
+			"These are the values `ldap_entry` sends when it updates a multi-valued attribute such as `member` incrementally. " +
+			"Values are compared as sets, so order and duplicates do not matter, and exactly, including case: " +
+			"`uid=jdoe,dc=example,dc=com` and `UID=jdoe,dc=example,dc=com` are different values.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:                "current",
+				MarkdownDescription: "Current values, e.g. the members read from the directory. `null` is treated as an empty list.",
+				ElementType:         types.StringType,
+				AllowNullValue:      true,
+			},
+			function.ListParameter{
+				Name:                "desired",
+				MarkdownDescription: "Desired values. `null` is treated as an empty list.",
+				ElementType:         types.StringType,
+				AllowNullValue:      true,
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: map[string]attr.Type{
+				"add":    attributeValuesType,
+				"remove": attributeValuesType,
+			},
+		},
+	}
+}
+
+func (f *MemberDeltaFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var current, desired []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &current, &desired))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, memberDelta{
+		Add:    uniqueSortedValues(valuesNotIn(desired, current)),
+		Remove: uniqueSortedValues(valuesNotIn(current, desired)),
+	}))
+}
@@ -0,0 +1,76 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestMemberDeltaFunction_Run(t *testing.T) {
+	const (
+		alice = "uid=alice,ou=users,dc=example,dc=com"
+		bob   = "uid=bob,ou=users,dc=example,dc=com"
+		carol = "uid=carol,ou=users,dc=example,dc=com"
+		dave  = "uid=dave,ou=users,dc=example,dc=com"
+	)
+
+	tests := []struct {
+		name           string
+		current        []string
+		desired        []string
+		expectedAdd    []string
+		expectedRemove []string
+	}{
+		{name: "equal", current: []string{alice, bob}, desired: []string{bob, alice}, expectedAdd: []string{}, expectedRemove: []string{}},
+		{name: "overlapping", current: []string{alice, bob, carol}, desired: []string{bob, carol, dave}, expectedAdd: []string{dave}, expectedRemove: []string{alice}},
+		{name: "disjoint", current: []string{carol, alice}, desired: []string{dave, bob}, expectedAdd: []string{bob, dave}, expectedRemove: []string{alice, carol}},
+		{name: "subset", current: []string{alice}, desired: []string{alice, bob}, expectedAdd: []string{bob}, expectedRemove: []string{}},
+		{name: "superset", current: []string{alice, bob}, desired: []string{alice}, expectedAdd: []string{}, expectedRemove: []string{bob}},
+		{name: "duplicates", current: []string{alice, alice}, desired: []string{bob, bob, alice}, expectedAdd: []string{bob}, expectedRemove: []string{}},
+		{name: "null current", current: nil, desired: []string{alice}, expectedAdd: []string{alice}, expectedRemove: []string{}},
+		{name: "null desired", current: []string{alice}, desired: nil, expectedAdd: []string{}, expectedRemove: []string{alice}},
+		{name: "both null", current: nil, desired: nil, expectedAdd: []string{}, expectedRemove: []string{}},
+		{name: "case sensitive", current: []string{alice}, desired: []string{"UID=alice,ou=users,dc=example,dc=com"}, expectedAdd: []string{"UID=alice,ou=users,dc=example,dc=com"}, expectedRemove: []string{alice}},
+	}
+
+	definition := &function.DefinitionResponse{}
+	NewMemberDeltaFunction().Definition(context.Background(), function.DefinitionRequest{}, definition)
+	returnType := definition.Definition.Return.GetType().(types.ObjectType)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					testAttrValuesList(tt.current),
+					testAttrValuesList(tt.desired),
+				}),
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.ObjectUnknown(returnType.AttrTypes)),
+			}
+
+			NewMemberDeltaFunction().Run(context.Background(), req, resp)
+
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+
+			result, ok := resp.Result.Value().(types.Object)
+			if !ok {
+				t.Fatalf("result = %T, want object", resp.Result.Value())
+			}
+			if got := result.Attributes()["add"]; !got.Equal(testAttrValuesList(tt.expectedAdd)) {
+				t.Errorf("add = %s, want %v", got, tt.expectedAdd)
+			}
+			if got := result.Attributes()["remove"]; !got.Equal(testAttrValuesList(tt.expectedRemove)) {
+				t.Errorf("remove = %s, want %v", got, tt.expectedRemove)
+			}
+		})
+	}
+}
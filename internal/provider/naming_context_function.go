@@ -0,0 +1,98 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &NamingContextFunction{}
+
+func NewNamingContextFunction() function.Function {
+	return &NamingContextFunction{}
+}
+
+// NamingContextFunction finds the naming context, or partition, an entry lives in.
+type NamingContextFunction struct{}
+
+func (f *NamingContextFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "naming_context"
+}
+
+func (f *NamingContextFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Find the naming context containing a DN",
+		MarkdownDescription: "Returns the naming context in `naming_contexts` that contains `dn`, as written in `naming_contexts`, or `null` if none does. " +
+			"Pass the `naming_contexts` of the `ldap_root_dse` data source to find the partition an entry lives in, e.g. to route entries of a multi-domain Active Directory forest. " +
+			"When naming contexts are nested, such as `DC=example,DC=com` and `DC=emea,DC=example,DC=com`, the most specific one is returned. " +
+			"DNs are compared per RDN, ignoring case and spaces around separators.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "dn",
+				MarkdownDescription: "Distinguished name of the entry.",
+			},
+			function.ListParameter{
+				Name:                "naming_contexts",
+				MarkdownDescription: "Naming contexts to choose from, e.g. `data.ldap_root_dse.server.naming_contexts`.",
+				ElementType:         types.StringType,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *NamingContextFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var dn string
+	var namingContexts []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &dn, &namingContexts))
+	if resp.Error != nil {
+		return
+	}
+
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("invalid DN %q: %s", dn, err))
+		return
+	}
+
+	namingContext, err := containingNamingContext(parsed, namingContexts)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, err.Error())
+		return
+	}
+	if namingContext == "" {
+		resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, types.StringNull()))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, namingContext))
+}
+
+// containingNamingContext returns the naming context that equals dn or is its closest ancestor,
+// or "" if none of namingContexts contains dn.
+func containingNamingContext(dn *ldap.DN, namingContexts []string) (string, error) {
+	match := ""
+	matchDepth := -1
+	for _, namingContext := range namingContexts {
+		parsed, err := ldap.ParseDN(namingContext)
+		if err != nil {
+			return "", fmt.Errorf("invalid naming context %q: %s", namingContext, err)
+		}
+		if !parsed.EqualFold(dn) && !parsed.AncestorOfFold(dn) {
+			continue
+		}
+		if len(parsed.RDNs) > matchDepth {
+			match = namingContext
+			matchDepth = len(parsed.RDNs)
+		}
+	}
+	return match, nil
+}
@@ -0,0 +1,134 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestNamingContextFunction_Run(t *testing.T) {
+	forest := []string{
+		"DC=example,DC=com",
+		"CN=Configuration,DC=example,DC=com",
+		"CN=Schema,CN=Configuration,DC=example,DC=com",
+		"DC=emea,DC=example,DC=com",
+		"DC=DomainDnsZones,DC=example,DC=com",
+	}
+
+	tests := []struct {
+		name           string
+		dn             string
+		namingContexts []string
+		expected       types.String
+		expectError    bool
+	}{
+		{
+			name:           "root domain",
+			dn:             "CN=jdoe,CN=Users,DC=example,DC=com",
+			namingContexts: forest,
+			expected:       types.StringValue("DC=example,DC=com"),
+		},
+		{
+			name:           "child domain",
+			dn:             "CN=jdoe,OU=Staff,DC=emea,DC=example,DC=com",
+			namingContexts: forest,
+			expected:       types.StringValue("DC=emea,DC=example,DC=com"),
+		},
+		{
+			name:           "doubly nested context",
+			dn:             "CN=User,CN=Schema,CN=Configuration,DC=example,DC=com",
+			namingContexts: forest,
+			expected:       types.StringValue("CN=Schema,CN=Configuration,DC=example,DC=com"),
+		},
+		{
+			name:           "nested context listed first",
+			dn:             "CN=Sites,CN=Configuration,DC=example,DC=com",
+			namingContexts: []string{"CN=Configuration,DC=example,DC=com", "DC=example,DC=com"},
+			expected:       types.StringValue("CN=Configuration,DC=example,DC=com"),
+		},
+		{
+			name:           "naming context itself",
+			dn:             "DC=emea,DC=example,DC=com",
+			namingContexts: forest,
+			expected:       types.StringValue("DC=emea,DC=example,DC=com"),
+		},
+		{
+			name:           "case and spacing differ",
+			dn:             "cn=jdoe, dc=EMEA, dc=example, dc=com",
+			namingContexts: forest,
+			expected:       types.StringValue("DC=emea,DC=example,DC=com"),
+		},
+		{
+			name:           "sibling with common suffix",
+			dn:             "CN=jdoe,DC=emea2,DC=example,DC=org",
+			namingContexts: []string{"DC=emea,DC=example,DC=org"},
+			expected:       types.StringNull(),
+		},
+		{
+			name:           "outside every context",
+			dn:             "uid=jdoe,ou=users,dc=example,dc=org",
+			namingContexts: forest,
+			expected:       types.StringNull(),
+		},
+		{
+			name:           "no naming contexts",
+			dn:             "CN=jdoe,DC=example,DC=com",
+			namingContexts: []string{},
+			expected:       types.StringNull(),
+		},
+		{
+			name:           "invalid dn",
+			dn:             "CN=jdoe,Users",
+			namingContexts: forest,
+			expectError:    true,
+		},
+		{
+			name:           "invalid naming context",
+			dn:             "CN=jdoe,DC=example,DC=com",
+			namingContexts: []string{"example.com"},
+			expectError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namingContexts := make([]attr.Value, len(tt.namingContexts))
+			for i, namingContext := range tt.namingContexts {
+				namingContexts[i] = types.StringValue(namingContext)
+			}
+
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue(tt.dn),
+					types.ListValueMust(types.StringType, namingContexts),
+				}),
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.StringUnknown()),
+			}
+
+			NewNamingContextFunction().Run(context.Background(), req, resp)
+
+			if tt.expectError {
+				if resp.Error == nil {
+					t.Errorf("expected error, got result %s", resp.Result.Value())
+				}
+				return
+			}
+
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+
+			if !resp.Result.Value().Equal(tt.expected) {
+				t.Errorf("result = %s, want %s", resp.Result.Value(), tt.expected)
+			}
+		})
+	}
+}
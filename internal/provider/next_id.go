@@ -0,0 +1,84 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// nextIDAttempts bounds how often AllocateNextID reads the counter again after another client
+// allocated the value it read.
+const nextIDAttempts = 10
+
+// idCounter is the subset of *ldap.Conn used to allocate IDs from a counter entry.
+type idCounter interface {
+	LdapSearcher
+	LdapModifier
+}
+
+// AllocateNextID allocates the value held by the counter attribute of the entry dn and advances
+// the counter by one. The counter holds the next free ID, as the counter entries of smbldap-tools
+// and Samba's sambaUnixIdPool do.
+//
+// The counter is advanced with a single modify operation that deletes the value read and adds the
+// next one. The server applies it atomically, and it fails with noSuchAttribute if another client
+// advanced the counter in between, so no value is allocated twice. AllocateNextID then reads the
+// counter again, up to nextIDAttempts times.
+func AllocateNextID(conn idCounter, dn string, attribute string) (int64, error) {
+	for attempt := 1; ; attempt++ {
+		current, err := readCounter(conn, dn, attribute)
+		if err != nil {
+			return 0, err
+		}
+		if current == math.MaxInt64 {
+			return 0, fmt.Errorf("counter %s of %s is exhausted", attribute, dn)
+		}
+
+		modifyReq := ldap.NewModifyRequest(dn, nil)
+		modifyReq.Delete(attribute, []string{strconv.FormatInt(current, 10)})
+		modifyReq.Add(attribute, []string{strconv.FormatInt(current+1, 10)})
+		err = conn.Modify(modifyReq)
+		if err == nil {
+			return current, nil
+		}
+		if !ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchAttribute) {
+			return 0, fmt.Errorf("unable to advance counter %s of %s: %w", attribute, dn, err)
+		}
+		if attempt == nextIDAttempts {
+			return 0, fmt.Errorf("counter %s of %s changed concurrently %d times in a row, giving up", attribute, dn, attempt)
+		}
+	}
+}
+
+// readCounter returns the value of the counter attribute of the entry dn, which must be a single
+// integer.
+func readCounter(conn LdapSearcher, dn string, attribute string) (int64, error) {
+	sr, err := LdapSearch(conn, dn, "base", "(objectClass=*)", []string{attribute}, LdapSearchOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("unable to read counter entry %s: %w", dn, err)
+	}
+	if len(sr.Entries) == 0 {
+		return 0, fmt.Errorf("counter entry not found: %s", dn)
+	}
+
+	values := sr.Entries[0].GetEqualFoldAttributeValues(attribute)
+	switch len(values) {
+	case 0:
+		return 0, fmt.Errorf("counter entry %s has no %s value, or the bound identity cannot read it", dn, attribute)
+	case 1:
+	default:
+		return 0, fmt.Errorf("counter %s of %s has %d values (%s), expected one", attribute, dn, len(values), strings.Join(values, ", "))
+	}
+
+	value, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("counter %s of %s is not an integer: %q", attribute, dn, values[0])
+	}
+	return value, nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// fakeCounter holds a counter attribute and applies modify requests to it the way a server does:
+// deleting a value the attribute does not hold fails with noSuchAttribute.
+type fakeCounter struct {
+	values []string
+	// raceFor advances the counter between the read and the modify for the given number of
+	// allocations, as another client would.
+	raceFor  int
+	modifies int
+}
+
+func (c *fakeCounter) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	entry := ldap.NewEntry(req.BaseDN, map[string][]string{"uidNumber": c.values})
+	if c.raceFor > 0 {
+		c.raceFor--
+		current, _ := strconv.Atoi(c.values[0])
+		c.values = []string{strconv.Itoa(current + 1)}
+	}
+	return &ldap.SearchResult{Entries: []*ldap.Entry{entry}}, nil
+}
+
+func (c *fakeCounter) Modify(req *ldap.ModifyRequest) error {
+	c.modifies++
+	values := c.values
+	for _, change := range req.Changes {
+		switch change.Operation {
+		case ldap.DeleteAttribute:
+			if len(values) != 1 || values[0] != change.Modification.Vals[0] {
+				return ldap.NewError(ldap.LDAPResultNoSuchAttribute, fmt.Errorf("modify/delete: uidNumber: no such value"))
+			}
+			values = nil
+		case ldap.AddAttribute:
+			values = append(values, change.Modification.Vals...)
+		}
+	}
+	c.values = values
+	return nil
+}
+
+func TestAllocateNextID(t *testing.T) {
+	counter := &fakeCounter{values: []string{"10000"}}
+
+	for _, want := range []int64{10000, 10001} {
+		got, err := AllocateNextID(counter, "cn=uidNumber,dc=example,dc=com", "uidNumber")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got != want {
+			t.Errorf("AllocateNextID() = %d, want %d", got, want)
+		}
+	}
+	if counter.values[0] != "10002" {
+		t.Errorf("counter = %v, want [10002]", counter.values)
+	}
+}
+
+func TestAllocateNextID_Concurrent(t *testing.T) {
+	counter := &fakeCounter{values: []string{"10000"}, raceFor: 2}
+
+	got, err := AllocateNextID(counter, "cn=uidNumber,dc=example,dc=com", "uidNumber")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// Two other clients allocated 10000 and 10001 in between
+	if got != 10002 || counter.modifies != 3 {
+		t.Errorf("AllocateNextID() = %d after %d modifies, want 10002 after 3", got, counter.modifies)
+	}
+
+	counter = &fakeCounter{values: []string{"10000"}, raceFor: nextIDAttempts}
+	_, err = AllocateNextID(counter, "cn=uidNumber,dc=example,dc=com", "uidNumber")
+	if err == nil || !strings.Contains(err.Error(), "changed concurrently") {
+		t.Errorf("expected an error about concurrent changes, got %v", err)
+	}
+}
+
+func TestAllocateNextID_InvalidCounter(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []string
+		expectErr string
+	}{
+		{name: "no value", expectErr: "has no uidNumber value"},
+		{name: "several values", values: []string{"1", "2"}, expectErr: "has 2 values"},
+		{name: "not an integer", values: []string{"ten"}, expectErr: "is not an integer"},
+		{name: "exhausted", values: []string{"9223372036854775807"}, expectErr: "is exhausted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := &fakeCounter{values: tt.values}
+			_, err := AllocateNextID(counter, "cn=uidNumber,dc=example,dc=com", "uidNumber")
+			if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+				t.Errorf("expected an error containing %q, got %v", tt.expectErr, err)
+			}
+			if counter.modifies != 0 {
+				t.Errorf("expected no modify, got %d", counter.modifies)
+			}
+		})
+	}
+}
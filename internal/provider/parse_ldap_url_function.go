@@ -0,0 +1,163 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &ParseLdapURLFunction{}
+
+func NewParseLdapURLFunction() function.Function {
+	return &ParseLdapURLFunction{}
+}
+
+// ParseLdapURLFunction splits an RFC 4516 LDAP URL into its components.
+type ParseLdapURLFunction struct{}
+
+// ldapURLComponents is the result of parse_ldap_url.
+type ldapURLComponents struct {
+	Scheme     string   `tfsdk:"scheme"`
+	Host       string   `tfsdk:"host"`
+	DN         string   `tfsdk:"dn"`
+	Attributes []string `tfsdk:"attributes"`
+	Scope      string   `tfsdk:"scope"`
+	Filter     string   `tfsdk:"filter"`
+}
+
+func (f *ParseLdapURLFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_ldap_url"
+}
+
+func (f *ParseLdapURLFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Parse an LDAP URL",
+		MarkdownDescription: "Splits an RFC 4516 LDAP URL of the form `ldap://host/dn?attributes?scope?filter?extensions`, such as a referral, into an object with " +
+			"`scheme`, `host`, `dn`, `attributes`, `scope` and `filter`, percent-decoding the DN, attributes and filter. This is the reverse of `ldap_url`. " +
+			"Omitted components take their RFC 4516 defaults: no attributes (`[]`, all user attributes), scope `base` and filter `(objectClass=*)`; " +
+			"`host` and `dn` are empty if omitted. For example `parse_ldap_url(\"ldap://ldap1.example.net/o=University%20of%20Michigan,c=US??sub?(cn=Babs%20Jensen)\")` returns " +
+			"`{scheme = \"ldap\", host = \"ldap1.example.net\", dn = \"o=University of Michigan,c=US\", attributes = [], scope = \"sub\", filter = \"(cn=Babs Jensen)\"}`. " +
+			"Non-critical extensions are ignored. Errors if the URL is not an `ldap://` or `ldaps://` URL, a component is invalid, or it has a critical extension (`!`).",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "url",
+				MarkdownDescription: "LDAP URL to parse.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: map[string]attr.Type{
+				"scheme":     types.StringType,
+				"host":       types.StringType,
+				"dn":         types.StringType,
+				"attributes": attributeValuesType,
+				"scope":      types.StringType,
+				"filter":     types.StringType,
+			},
+		},
+	}
+}
+
+func (f *ParseLdapURLFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var rawURL string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &rawURL))
+	if resp.Error != nil {
+		return
+	}
+
+	components, err := parseLdapURL(rawURL)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, components))
+}
+
+// parseLdapURL splits an RFC 4516 LDAP URL into its components, applying the defaults of omitted ones.
+func parseLdapURL(rawURL string) (ldapURLComponents, error) {
+	components := ldapURLComponents{Attributes: []string{}, Scope: "base", Filter: "(objectClass=*)"}
+
+	scheme, rest, found := strings.Cut(rawURL, "://")
+	components.Scheme = strings.ToLower(scheme)
+	if !found || (components.Scheme != "ldap" && components.Scheme != "ldaps") {
+		return components, fmt.Errorf("expected an ldap:// or ldaps:// URL, got %q", rawURL)
+	}
+	if strings.Contains(rest, "#") {
+		return components, fmt.Errorf("LDAP URLs must not have a fragment, got %q", rawURL)
+	}
+
+	host, path, _ := strings.Cut(rest, "/")
+	if strings.Contains(host, "?") {
+		return components, fmt.Errorf("expected \"/\" between the host and the other components, got %q", rawURL)
+	}
+	components.Host = host
+
+	parts := strings.Split(path, "?")
+	if len(parts) > 5 {
+		return components, fmt.Errorf("too many components in %q, expected at most dn?attributes?scope?filter?extensions", rawURL)
+	}
+	for len(parts) < 5 {
+		parts = append(parts, "")
+	}
+
+	dn, err := url.PathUnescape(parts[0])
+	if err != nil {
+		return components, fmt.Errorf("invalid dn %q: %s", parts[0], err)
+	}
+	if dn != "" {
+		if err := validateDN(dn); err != nil {
+			return components, err
+		}
+	}
+	components.DN = dn
+
+	if parts[1] != "" {
+		for _, encoded := range strings.Split(parts[1], ",") {
+			attribute, err := url.PathUnescape(encoded)
+			if err != nil || attribute == "" {
+				return components, fmt.Errorf("invalid attributes %q", parts[1])
+			}
+			components.Attributes = append(components.Attributes, attribute)
+		}
+	}
+
+	if parts[2] != "" {
+		scope := strings.ToLower(parts[2])
+		if _, err := ConvertHumanReadableLDAPScope(scope); err != nil {
+			return components, err
+		}
+		components.Scope = scope
+	}
+
+	if parts[3] != "" {
+		filter, err := url.PathUnescape(parts[3])
+		if err != nil {
+			return components, fmt.Errorf("invalid filter %q: %s", parts[3], err)
+		}
+		if _, err := ldap.CompileFilter(filter); err != nil {
+			return components, fmt.Errorf("invalid filter %q: %s", filter, err)
+		}
+		components.Filter = filter
+	}
+
+	if parts[4] != "" {
+		for _, extension := range strings.Split(parts[4], ",") {
+			if strings.HasPrefix(extension, "!") {
+				return components, fmt.Errorf("unsupported critical extension %q", extension)
+			}
+		}
+	}
+
+	return components, nil
+}
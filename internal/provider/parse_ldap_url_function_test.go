@@ -0,0 +1,144 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseLdapURLFunction_Run(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		expected    ldapURLComponents
+		expectError bool
+	}{
+		{
+			name: "rfc 4516 example",
+			url:  "ldap://ldap1.example.net/o=University%20of%20Michigan,c=US??sub?(cn=Babs%20Jensen)",
+			expected: ldapURLComponents{
+				Scheme: "ldap", Host: "ldap1.example.net", DN: "o=University of Michigan,c=US",
+				Attributes: []string{}, Scope: "sub", Filter: "(cn=Babs Jensen)",
+			},
+		},
+		{
+			name: "attributes and port",
+			url:  "ldap://ldap.example.com:389/dc=example,dc=com?cn,mail?one",
+			expected: ldapURLComponents{
+				Scheme: "ldap", Host: "ldap.example.com:389", DN: "dc=example,dc=com",
+				Attributes: []string{"cn", "mail"}, Scope: "one", Filter: "(objectClass=*)",
+			},
+		},
+		{
+			name: "dn only",
+			url:  "LDAPS://ldap.example.com/uid=jdoe,ou=users,dc=example,dc=com",
+			expected: ldapURLComponents{
+				Scheme: "ldaps", Host: "ldap.example.com", DN: "uid=jdoe,ou=users,dc=example,dc=com",
+				Attributes: []string{}, Scope: "base", Filter: "(objectClass=*)",
+			},
+		},
+		{
+			name: "host only",
+			url:  "ldap://ldap.example.com",
+			expected: ldapURLComponents{
+				Scheme: "ldap", Host: "ldap.example.com",
+				Attributes: []string{}, Scope: "base", Filter: "(objectClass=*)",
+			},
+		},
+		{
+			name: "no host",
+			url:  "ldap:///dc=example,dc=com??SUB",
+			expected: ldapURLComponents{
+				Scheme: "ldap", DN: "dc=example,dc=com",
+				Attributes: []string{}, Scope: "sub", Filter: "(objectClass=*)",
+			},
+		},
+		{
+			name: "ipv6 host and non-critical extension",
+			url:  "ldap://[2001:db8::7]/c=GB?objectClass?one??e-bindname=cn=Manager%2cdc=example%2cdc=com",
+			expected: ldapURLComponents{
+				Scheme: "ldap", Host: "[2001:db8::7]", DN: "c=GB",
+				Attributes: []string{"objectClass"}, Scope: "one", Filter: "(objectClass=*)",
+			},
+		},
+		{
+			name: "encoded question mark in filter",
+			url:  "ldap://ldap.example.com/dc=example,dc=com???(description=why%3f)",
+			expected: ldapURLComponents{
+				Scheme: "ldap", Host: "ldap.example.com", DN: "dc=example,dc=com",
+				Attributes: []string{}, Scope: "base", Filter: "(description=why?)",
+			},
+		},
+		{name: "other scheme", url: "http://ldap.example.com/", expectError: true},
+		{name: "not a url", url: "ldap.example.com", expectError: true},
+		{name: "invalid dn", url: "ldap://ldap.example.com/not%20a%20dn", expectError: true},
+		{name: "invalid scope", url: "ldap://ldap.example.com/dc=example,dc=com??subtree", expectError: true},
+		{name: "invalid filter", url: "ldap://ldap.example.com/dc=example,dc=com???(cn=a", expectError: true},
+		{name: "invalid escape", url: "ldap://ldap.example.com/dc=example%zz", expectError: true},
+		{name: "too many components", url: "ldap://ldap.example.com/dc=com?????", expectError: true},
+		{name: "critical extension", url: "ldap://ldap.example.com/dc=com????!e-bindname=cn=Manager", expectError: true},
+	}
+
+	definition := &function.DefinitionResponse{}
+	NewParseLdapURLFunction().Definition(context.Background(), function.DefinitionRequest{}, definition)
+	returnType := definition.Definition.Return.GetType().(types.ObjectType)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(tt.url)}),
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.ObjectUnknown(returnType.AttrTypes)),
+			}
+
+			NewParseLdapURLFunction().Run(context.Background(), req, resp)
+
+			if tt.expectError {
+				if resp.Error == nil {
+					t.Errorf("expected error, got result %s", resp.Result.Value())
+				}
+				return
+			}
+
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+
+			expected, diags := types.ObjectValueFrom(context.Background(), returnType.AttrTypes, tt.expected)
+			if diags.HasError() {
+				t.Fatalf("unable to build expected result: %v", diags)
+			}
+			if !resp.Result.Value().Equal(expected) {
+				t.Errorf("result = %s, want %s", resp.Result.Value(), expected)
+			}
+		})
+	}
+}
+
+func TestParseLdapURL_FormatRoundTrip(t *testing.T) {
+	original := ldapURLComponents{
+		Scheme:     "ldaps",
+		Host:       "ldap.example.com:636",
+		DN:         "ou=Sales & Marketing,dc=example,dc=com",
+		Attributes: []string{"cn", "mail"},
+		Scope:      "sub",
+		Filter:     "(&(cn=Jane Doe)(description=a?b))",
+	}
+
+	formatted := formatLdapURL(original.Scheme, original.Host, original.DN, original.Attributes, original.Scope, original.Filter)
+	parsed, err := parseLdapURL(formatted)
+	if err != nil {
+		t.Fatalf("unable to parse %q: %s", formatted, err)
+	}
+	if !reflect.DeepEqual(parsed, original) {
+		t.Errorf("parseLdapURL(%q) = %+v, want %+v", formatted, parsed, original)
+	}
+}
@@ -4,6 +4,8 @@
 package provider
 
 import (
+	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -50,3 +52,554 @@ resource "ldap_entry" "test" {
 }
 `
 }
+
+func TestAccProvider_BaseDN(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfigBaseDN(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					// dn is kept as configured, id is the resolved DN
+					statecheck.ExpectKnownValue(
+						"ldap_entry.relative",
+						tfjsonpath.New("dn"),
+						knownvalue.StringExact("cn=base-dn-test,ou=users"),
+					),
+					statecheck.ExpectKnownValue(
+						"ldap_entry.relative",
+						tfjsonpath.New("id"),
+						knownvalue.StringExact("cn=base-dn-test,ou=users,dc=example,dc=com"),
+					),
+					// Absolute DNs are left untouched
+					statecheck.ExpectKnownValue(
+						"ldap_entry.absolute",
+						tfjsonpath.New("id"),
+						knownvalue.StringExact("cn=base-dn-absolute,ou=users,dc=example,dc=com"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.relative",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("dn"),
+						knownvalue.StringExact("cn=base-dn-test,ou=users,dc=example,dc=com"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccProviderConfigBaseDN() string {
+	return `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+  base_dn = "dc=example,dc=com"
+}
+
+resource "ldap_entry" "relative" {
+  dn = "cn=base-dn-test,ou=users"
+  attributes = {
+    objectClass = ["person"]
+    cn = ["base-dn-test"]
+    sn = ["Test"]
+  }
+}
+
+resource "ldap_entry" "absolute" {
+  dn = "cn=base-dn-absolute,ou=users,dc=example,dc=com"
+  attributes = {
+    objectClass = ["person"]
+    cn = ["base-dn-absolute"]
+    sn = ["Test"]
+  }
+}
+
+data "ldap_search" "relative" {
+  basedn = ldap_entry.relative.dn
+  scope = "base"
+  filter = "(objectClass=*)"
+}
+`
+}
+
+func TestAccProvider_AllowedBaseDNs(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfigAllowedBaseDNs("cn=allowed-base-dn-test,ou=groups"),
+				ExpectError: regexp.MustCompile(`DN outside allowed base DNs`),
+			},
+			{
+				Config: testAccProviderConfigAllowedBaseDNs("cn=allowed-base-dn-test,ou=users"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"ldap_entry.test",
+						tfjsonpath.New("id"),
+						knownvalue.StringExact("cn=allowed-base-dn-test,ou=users,dc=example,dc=com"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccProvider_AllowedBaseDNsInvalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+  allowed_base_dns = ["not a dn"]
+}
+
+data "ldap_root_dse" "test" {}
+`,
+				ExpectError: regexp.MustCompile(`Invalid allowed base DN`),
+			},
+		},
+	})
+}
+
+func testAccProviderConfigAllowedBaseDNs(dn string) string {
+	return fmt.Sprintf(`
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+  base_dn = "dc=example,dc=com"
+  allowed_base_dns = ["ou=users,dc=example,dc=com"]
+}
+
+resource "ldap_entry" "test" {
+  dn = %[1]q
+  attributes = {
+    objectClass = ["person"]
+    cn = ["allowed-base-dn-test"]
+    sn = ["Test"]
+  }
+}
+`, dn)
+}
+
+func TestAccProvider_ProtectedObjectClasses(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfigProtectedObjectClasses(false),
+			},
+			{
+				Config:      testAccProviderConfigProtectedObjectClasses(false),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`LDAP entry is protected`),
+			},
+			{
+				Config: testAccProviderConfigProtectedObjectClasses(false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckLdapAttributeValues("ldap_entry.test", "ou", []string{"protected-test"}),
+				),
+			},
+			{
+				// Allowing the delete lets the final destroy remove the entry
+				Config: testAccProviderConfigProtectedObjectClasses(true),
+			},
+		},
+	})
+}
+
+func testAccProviderConfigProtectedObjectClasses(allowDelete bool) string {
+	return fmt.Sprintf(`
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+  protected_object_classes = ["organizationalUnit", "domain"]
+}
+
+resource "ldap_entry" "test" {
+  dn = "ou=protected-test,dc=example,dc=com"
+  attributes = {
+    objectClass = ["organizationalUnit"]
+    ou = ["protected-test"]
+  }
+  allow_protected_delete = %t
+}
+`, allowDelete)
+}
+
+func TestAccProvider_BaseDNFromEnvironment(t *testing.T) {
+	t.Setenv("LDAP_BASE_DN", "dc=example,dc=com")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+data "ldap_search" "relative" {
+  basedn = "ou=users"
+  scope = "base"
+  filter = "(objectClass=*)"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.relative",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("dn"),
+						knownvalue.StringExact("ou=users,dc=example,dc=com"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccProvider_TLSMinVersionInvalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+  tls_min_version = "1.1"
+}
+
+data "ldap_root_dse" "test" {}
+`,
+				ExpectError: regexp.MustCompile(`tls_min_version`),
+			},
+		},
+	})
+}
+
+func TestAccProvider_TLSMinVersionFromEnvironmentInvalid(t *testing.T) {
+	t.Setenv("LDAP_TLS_MIN_VERSION", "1.0")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccLdapEntryResourceConfigProviderOnly() + `data "ldap_root_dse" "test" {}`,
+				ExpectError: regexp.MustCompile(`Invalid TLS minimum version`),
+			},
+		},
+	})
+}
+
+func TestAccProvider_NTLMFromEnvironmentIgnoredWithBindDN(t *testing.T) {
+	// The configured bind_dn wins over the NTLM settings of the environment, so the simple bind works.
+	t.Setenv("LDAP_NTLM_DOMAIN", "EXAMPLE")
+	t.Setenv("LDAP_NTLM_USERNAME", "svc-terraform")
+	t.Setenv("LDAP_NTLM_PASSWORD", "secret")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfigPrecedence("", "ldap://localhost:3389"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.test",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("dn"),
+						knownvalue.StringExact("ou=users,dc=example,dc=com"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccProvider_BindDNFromEnvironmentIgnoredWithNTLM(t *testing.T) {
+	// The configured ntlm block wins over the simple bind credentials of the environment: the
+	// invalid hash is reported instead of a conflict with bind_dn.
+	t.Setenv("LDAP_BIND_DN", "cn=Manager,dc=example,dc=com")
+	t.Setenv("LDAP_BIND_PASSWORD", "secret")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfigNTLM("", `hash = "not-a-hash"`),
+				ExpectError: regexp.MustCompile(`ntlm.hash must be an NT hash`),
+			},
+		},
+	})
+}
+
+func TestAccProvider_NTLMFromEnvironmentWithEnvPrecedence(t *testing.T) {
+	// With env precedence, the NTLM settings of the environment win over the configured bind_dn.
+	t.Setenv("LDAP_CONFIG_PRECEDENCE", "env")
+	t.Setenv("LDAP_NTLM_DOMAIN", "EXAMPLE")
+	t.Setenv("LDAP_NTLM_USERNAME", "svc-terraform")
+	t.Setenv("LDAP_NTLM_HASH", "not-a-hash")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfigPrecedence("", "ldaps://localhost:3636"),
+				ExpectError: regexp.MustCompile(`ntlm.hash must be an NT hash`),
+			},
+		},
+	})
+}
+
+func TestAccProvider_NTLMHashFromEnvironmentInvalid(t *testing.T) {
+	t.Setenv("LDAP_NTLM_HASH", "not-a-hash")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The hash from the environment replaces the password of the block with env precedence
+			{
+				Config:      testAccProviderConfigNTLM("env", `password = "secret"`),
+				ExpectError: regexp.MustCompile(`ntlm.hash must be an NT hash`),
+			},
+		},
+	})
+}
+
+// testAccProviderConfigNTLM returns a provider binding with NTLM over ldaps://, with credential
+// setting the password or hash, and a data source using it.
+func testAccProviderConfigNTLM(precedence string, credential string) string {
+	precedenceArgument := ""
+	if precedence != "" {
+		precedenceArgument = fmt.Sprintf("config_precedence = %q", precedence)
+	}
+
+	return fmt.Sprintf(`
+provider "ldap" {
+  url = "ldaps://localhost:3636"
+  %s
+  ntlm = {
+    domain   = "EXAMPLE"
+    username = "svc-terraform"
+    %s
+  }
+}
+
+data "ldap_root_dse" "test" {}
+`, precedenceArgument, credential)
+}
+
+func TestAccProvider_ConfigPrecedenceConfig(t *testing.T) {
+	// The argument wins by default, so the unreachable URL from the environment is not used.
+	t.Setenv("LDAP_URL", "ldap://localhost:1")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfigPrecedence("", "ldap://localhost:3389"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.test",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("dn"),
+						knownvalue.StringExact("ou=users,dc=example,dc=com"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccProvider_ConfigPrecedenceEnv(t *testing.T) {
+	// The environment wins, so the unreachable URL from the argument is not used.
+	t.Setenv("LDAP_URL", "ldap://localhost:3389")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfigPrecedence("env", "ldap://localhost:1"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.test",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("dn"),
+						knownvalue.StringExact("ou=users,dc=example,dc=com"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccProvider_ConfigPrecedenceEnvFromEnvironment(t *testing.T) {
+	t.Setenv("LDAP_CONFIG_PRECEDENCE", "env")
+	t.Setenv("LDAP_URL", "ldap://localhost:3389")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfigPrecedence("", "ldap://localhost:1"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.ldap_search.test",
+						tfjsonpath.New("results").AtSliceIndex(0).AtMapKey("dn"),
+						knownvalue.StringExact("ou=users,dc=example,dc=com"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccProvider_ConfigPrecedenceInvalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfigPrecedence("environment", "ldap://localhost:3389"),
+				ExpectError: regexp.MustCompile(`config_precedence`),
+			},
+		},
+	})
+}
+
+func testAccProviderConfigPrecedence(precedence string, url string) string {
+	precedenceArgument := ""
+	if precedence != "" {
+		precedenceArgument = fmt.Sprintf("config_precedence = %q", precedence)
+	}
+
+	return fmt.Sprintf(`
+provider "ldap" {
+  url = %q
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+  %s
+}
+
+data "ldap_search" "test" {
+  basedn = "ou=users,dc=example,dc=com"
+  scope = "base"
+  filter = "(objectClass=*)"
+}
+`, url, precedenceArgument)
+}
+
+func TestAccProvider_RequestTimeoutInvalid(t *testing.T) {
+	t.Setenv("LDAP_REQUEST_TIMEOUT", "-1s")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccLdapEntryResourceConfigProviderOnly() + `data "ldap_root_dse" "test" {}`,
+				ExpectError: regexp.MustCompile(`Invalid request timeout`),
+			},
+		},
+	})
+}
+
+func TestAccProvider_DialTimeoutInvalid(t *testing.T) {
+	t.Setenv("LDAP_DIAL_TIMEOUT", "soon")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccLdapEntryResourceConfigProviderOnly() + `data "ldap_root_dse" "test" {}`,
+				ExpectError: regexp.MustCompile(`Invalid dial timeout`),
+			},
+		},
+	})
+}
+
+func TestAccProvider_AuthMethodAnonymous(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  auth_method = "anonymous"
+}
+
+data "ldap_root_dse" "test" {}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.ldap_root_dse.test",
+						tfjsonpath.New("naming_contexts"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("dc=example,dc=com")}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccProvider_AuthMethodAnonymousWithBindDN(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+  auth_method = "anonymous"
+}
+
+data "ldap_root_dse" "test" {}
+`,
+				ExpectError: regexp.MustCompile(`auth_method anonymous`),
+			},
+		},
+	})
+}
+
+func TestAccProvider_AuthMethodFromEnvironmentInvalid(t *testing.T) {
+	t.Setenv("LDAP_AUTH_METHOD", "kerberos")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccLdapEntryResourceConfigProviderOnly() + `data "ldap_root_dse" "test" {}`,
+				ExpectError: regexp.MustCompile(`Invalid authentication method`),
+			},
+		},
+	})
+}
@@ -0,0 +1,71 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &RDNValueFunction{}
+
+func NewRDNValueFunction() function.Function {
+	return &RDNValueFunction{}
+}
+
+// RDNValueFunction extracts the value of a named attribute from any RDN of a DN.
+type RDNValueFunction struct{}
+
+func (f *RDNValueFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "rdn_value"
+}
+
+func (f *RDNValueFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Extract an attribute value from a DN",
+		MarkdownDescription: "Returns the unescaped value of `attribute` (matched case-insensitively) in the leftmost RDN of `dn` that contains it, or an empty string if no RDN does. " +
+			"For example `rdn_value(\"cn=laptop,uid=jdoe,ou=users,dc=example,dc=com\", \"uid\")` returns `jdoe`. " +
+			"Multi-valued RDNs such as `cn=John+uid=jdoe` are searched too. Errors if `dn` is not a valid DN.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "dn",
+				MarkdownDescription: "Distinguished name to search.",
+			},
+			function.StringParameter{
+				Name:                "attribute",
+				MarkdownDescription: "Attribute type to look for, e.g. `uid` or `ou`.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *RDNValueFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var dn, attributeType string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &dn, &attributeType))
+	if resp.Error != nil {
+		return
+	}
+
+	if err := validateDN(dn); err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	parsed, _ := ldap.ParseDN(dn)
+
+	value := ""
+	for _, rdn := range parsed.RDNs {
+		if v, ok := rdnValue(rdn, attributeType); ok {
+			value = v
+			break
+		}
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, value))
+}
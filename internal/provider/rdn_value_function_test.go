@@ -0,0 +1,109 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestRDNValueFunction_Run(t *testing.T) {
+	tests := []struct {
+		name        string
+		dn          string
+		attribute   string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:      "first rdn",
+			dn:        "uid=jdoe,ou=users,dc=example,dc=com",
+			attribute: "uid",
+			expected:  "jdoe",
+		},
+		{
+			name:      "parent rdn",
+			dn:        "cn=laptop,uid=jdoe,ou=users,dc=example,dc=com",
+			attribute: "uid",
+			expected:  "jdoe",
+		},
+		{
+			name:      "leftmost match wins",
+			dn:        "ou=team,ou=engineering,dc=example,dc=com",
+			attribute: "ou",
+			expected:  "team",
+		},
+		{
+			name:      "case insensitive",
+			dn:        "UID=jdoe,ou=users,dc=example,dc=com",
+			attribute: "Uid",
+			expected:  "jdoe",
+		},
+		{
+			name:      "multi-valued rdn",
+			dn:        "cn=John Doe+uid=jdoe,ou=users,dc=example,dc=com",
+			attribute: "uid",
+			expected:  "jdoe",
+		},
+		{
+			name:      "multi-valued parent",
+			dn:        "cn=printer,ou=devices+l=Berlin,dc=example,dc=com",
+			attribute: "l",
+			expected:  "Berlin",
+		},
+		{
+			name:      "escaped value",
+			dn:        `cn=Doe\, John,ou=users,dc=example,dc=com`,
+			attribute: "cn",
+			expected:  "Doe, John",
+		},
+		{
+			name:      "missing attribute",
+			dn:        "cn=John Doe,ou=users,dc=example,dc=com",
+			attribute: "uid",
+			expected:  "",
+		},
+		{
+			name:        "invalid dn",
+			dn:          "jdoe",
+			attribute:   "uid",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue(tt.dn),
+					types.StringValue(tt.attribute),
+				}),
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.StringUnknown()),
+			}
+
+			NewRDNValueFunction().Run(context.Background(), req, resp)
+
+			if tt.expectError {
+				if resp.Error == nil {
+					t.Errorf("expected error, got result %s", resp.Result.Value())
+				}
+				return
+			}
+
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+
+			if !resp.Result.Value().Equal(types.StringValue(tt.expected)) {
+				t.Errorf("result = %s, want %q", resp.Result.Value(), tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// referralError is returned for a write the server referred to other servers (result code 10),
+// naming where to send it instead. Err is the referral itself; FollowErrs holds the errors of
+// following it when follow_referrals is enabled.
+type referralError struct {
+	URLs       []string
+	Err        error
+	FollowErrs []error
+}
+
+func (e *referralError) Error() string {
+	if len(e.FollowErrs) == 0 {
+		return fmt.Sprintf("%s; the server referred the request to %s. "+
+			"Send it to that server, e.g. by pointing the provider url at it, or enable follow_referrals", e.Err, strings.Join(e.URLs, ", "))
+	}
+
+	followed := make([]string, len(e.FollowErrs))
+	for i, err := range e.FollowErrs {
+		followed[i] = err.Error()
+	}
+	return fmt.Sprintf("%s; following the referral to %s failed: %s", e.Err, strings.Join(e.URLs, ", "), strings.Join(followed, "; "))
+}
+
+func (e *referralError) Unwrap() error {
+	return e.Err
+}
+
+// referralURLs returns the referral URLs of an LDAP error with result code 10, or nil. Unlike
+// go-ldap, which only reports the first one, it returns all of them:
+//
+//	LDAPResult ::= SEQUENCE {
+//	     resultCode         ENUMERATED,
+//	     matchedDN          LDAPDN,
+//	     diagnosticMessage  LDAPString,
+//	     referral           [3] Referral OPTIONAL }
+//
+//	Referral ::= SEQUENCE SIZE (1..MAX) OF uri URI
+func referralURLs(err error) []string {
+	var ldapErr *ldap.Error
+	if !errors.As(err, &ldapErr) || ldapErr.ResultCode != ldap.LDAPResultReferral || ldapErr.Packet == nil {
+		return nil
+	}
+	if len(ldapErr.Packet.Children) < 2 {
+		return nil
+	}
+
+	var urls []string
+	for _, child := range ldapErr.Packet.Children[1].Children {
+		if child.ClassType != ber.ClassContext || child.TagType != ber.TypeConstructed || child.Tag != 3 {
+			continue
+		}
+		for _, uri := range child.Children {
+			if value := uri.Data.String(); value != "" {
+				urls = append(urls, value)
+			}
+		}
+	}
+	return urls
+}
+
+// referralTarget returns the DN to use for a request to dn that was referred to referralURL. An
+// LDAP URL naming a DN (RFC 4516) replaces dn, as the entry may have another name on that server.
+func referralTarget(referralURL string, dn string) (string, error) {
+	u, err := url.Parse(referralURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid referral URL %q: %s", referralURL, err)
+	}
+	if u.Scheme != "ldap" && u.Scheme != "ldaps" {
+		return "", fmt.Errorf("unsupported referral URL %q, expected ldap:// or ldaps://", referralURL)
+	}
+	if target := strings.TrimPrefix(u.Path, "/"); target != "" {
+		return target, nil
+	}
+	return dn, nil
+}
+
+// followReferral repeats a write that failed with err on the servers it was referred to, if
+// follow_referrals is enabled. send issues the request for the DN on the given connection. The
+// first server that accepts it wins. Referrals are followed one hop only, so loops cannot occur.
+// Errors other than referrals are returned unchanged.
+func (c *LdapClient) followReferral(err error, dn string, send func(conn *ldap.Conn, dn string) error) error {
+	urls := referralURLs(err)
+	if len(urls) == 0 {
+		return err
+	}
+
+	refErr := &referralError{URLs: urls, Err: err}
+	if c.referralDial == nil {
+		return refErr
+	}
+
+	for _, referralURL := range urls {
+		target, terr := referralTarget(referralURL, dn)
+		if terr != nil {
+			refErr.FollowErrs = append(refErr.FollowErrs, terr)
+			continue
+		}
+
+		conn, derr := c.referralDial(referralURL)
+		if derr != nil {
+			refErr.FollowErrs = append(refErr.FollowErrs, fmt.Errorf("%s: %w", referralURL, derr))
+			continue
+		}
+		serr := send(conn, target)
+		conn.Close()
+		if serr == nil {
+			return nil
+		}
+		refErr.FollowErrs = append(refErr.FollowErrs, fmt.Errorf("%s: %w", referralURL, serr))
+	}
+	return refErr
+}
@@ -0,0 +1,217 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// fakeWriteServer answers LDAP write requests with a fixed result, optionally carrying referrals,
+// and records the DNs it was asked to change.
+type fakeWriteServer struct {
+	resultCode int64
+	referrals  []string
+
+	mu  sync.Mutex
+	dns []string
+}
+
+// connect returns a client connection served by s.
+func (s *fakeWriteServer) connect(t *testing.T) *ldap.Conn {
+	client, server := net.Pipe()
+	t.Cleanup(func() { server.Close() })
+	go s.serve(server)
+
+	conn := ldap.NewConn(client, false)
+	conn.Start()
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func (s *fakeWriteServer) serve(server net.Conn) {
+	for {
+		packet, err := ber.ReadPacket(server)
+		if err != nil {
+			return
+		}
+		messageID := packet.Children[0].Value.(int64)
+		op := packet.Children[1]
+
+		dn := op.Data.String()
+		if len(op.Children) > 0 {
+			dn = op.Children[0].Data.String()
+		}
+		s.mu.Lock()
+		s.dns = append(s.dns, dn)
+		s.mu.Unlock()
+
+		result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, op.Tag+1, nil, "Response")
+		result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, s.resultCode, "resultCode"))
+		result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matchedDN"))
+		result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "diagnosticMessage"))
+		if len(s.referrals) > 0 {
+			referral := ber.Encode(ber.ClassContext, ber.TypeConstructed, 3, nil, "referral")
+			for _, uri := range s.referrals {
+				referral.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, uri, "uri"))
+			}
+			result.AppendChild(referral)
+		}
+
+		response := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+		response.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+		response.AppendChild(result)
+		if _, err := server.Write(response.Bytes()); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeWriteServer) requestedDNs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.dns...)
+}
+
+var testReferrals = []string{
+	"ldap://dc2.example.com/uid=jdoe,ou=users,dc=example,dc=com",
+	"ldaps://dc3.example.com",
+}
+
+func TestLdapClientReferral_NotFollowed(t *testing.T) {
+	primary := &fakeWriteServer{resultCode: ldap.LDAPResultReferral, referrals: testReferrals}
+	client := &LdapClient{conn: primary.connect(t)}
+
+	modifyReq := ldap.NewModifyRequest("uid=jdoe,ou=users,dc=example,dc=com", nil)
+	modifyReq.Replace("mail", []string{"jdoe@example.com"})
+	err := client.Modify(modifyReq)
+
+	var refErr *referralError
+	if !errors.As(err, &refErr) {
+		t.Fatalf("expected a referral error, got %v", err)
+	}
+	if !reflect.DeepEqual(refErr.URLs, testReferrals) {
+		t.Errorf("URLs = %v, want %v", refErr.URLs, testReferrals)
+	}
+	if !ldap.IsErrorWithCode(err, ldap.LDAPResultReferral) {
+		t.Error("expected the LDAP result code to be preserved")
+	}
+	for _, referral := range testReferrals {
+		if !strings.Contains(err.Error(), referral) {
+			t.Errorf("expected the error to name %s, got %q", referral, err)
+		}
+	}
+}
+
+func TestLdapClientReferral_Followed(t *testing.T) {
+	primary := &fakeWriteServer{resultCode: ldap.LDAPResultReferral, referrals: testReferrals}
+	unavailable := &fakeWriteServer{resultCode: ldap.LDAPResultUnavailable}
+	referred := &fakeWriteServer{resultCode: ldap.LDAPResultSuccess}
+
+	var dialed []string
+	client := &LdapClient{
+		conn: primary.connect(t),
+		referralDial: func(referralURL string) (*ldap.Conn, error) {
+			dialed = append(dialed, referralURL)
+			if strings.Contains(referralURL, "dc2") {
+				return unavailable.connect(t), nil
+			}
+			return referred.connect(t), nil
+		},
+	}
+
+	if err := client.Del(ldap.NewDelRequest("uid=jdoe,ou=people,dc=example,dc=com", nil)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(dialed, testReferrals) {
+		t.Errorf("dialed %v, want %v", dialed, testReferrals)
+	}
+	// The first referral names another DN for the entry, the second keeps the original one
+	if got := unavailable.requestedDNs(); !reflect.DeepEqual(got, []string{"uid=jdoe,ou=users,dc=example,dc=com"}) {
+		t.Errorf("first referred server got %v", got)
+	}
+	if got := referred.requestedDNs(); !reflect.DeepEqual(got, []string{"uid=jdoe,ou=people,dc=example,dc=com"}) {
+		t.Errorf("second referred server got %v", got)
+	}
+}
+
+func TestLdapClientReferral_FollowFails(t *testing.T) {
+	primary := &fakeWriteServer{resultCode: ldap.LDAPResultReferral, referrals: testReferrals[:1]}
+	client := &LdapClient{
+		conn: primary.connect(t),
+		referralDial: func(referralURL string) (*ldap.Conn, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	addReq := ldap.NewAddRequest("uid=jdoe,ou=users,dc=example,dc=com", nil)
+	addReq.Attribute("objectClass", []string{"inetOrgPerson"})
+	err := client.Add(addReq)
+
+	var refErr *referralError
+	if !errors.As(err, &refErr) || len(refErr.FollowErrs) != 1 {
+		t.Fatalf("expected a referral error with the failure to follow it, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("expected the error to explain why following failed, got %q", err)
+	}
+}
+
+func TestLdapClientReferral_OtherErrors(t *testing.T) {
+	primary := &fakeWriteServer{resultCode: ldap.LDAPResultNoSuchObject}
+	client := &LdapClient{
+		conn: primary.connect(t),
+		referralDial: func(referralURL string) (*ldap.Conn, error) {
+			t.Fatal("unexpected referral dial")
+			return nil, nil
+		},
+	}
+
+	err := client.Del(ldap.NewDelRequest("uid=jdoe,ou=users,dc=example,dc=com", nil))
+	var refErr *referralError
+	if errors.As(err, &refErr) || !ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+		t.Errorf("expected the original error, got %v", err)
+	}
+}
+
+func TestReferralTarget(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		expected    string
+		expectError bool
+	}{
+		{name: "without dn", url: "ldap://dc2.example.com", expected: "cn=original"},
+		{name: "root path", url: "ldaps://dc2.example.com:636/", expected: "cn=original"},
+		{name: "with dn", url: "ldap://dc2.example.com/cn=moved,dc=example,dc=com", expected: "cn=moved,dc=example,dc=com"},
+		{name: "escaped dn", url: "ldap://dc2.example.com/cn=John%20Doe,dc=example,dc=com??base", expected: "cn=John Doe,dc=example,dc=com"},
+		{name: "unsupported scheme", url: "http://dc2.example.com/", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := referralTarget(tt.url, "cn=original")
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error, got %q", target)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if target != tt.expected {
+				t.Errorf("referralTarget(%q) = %q, want %q", tt.url, target, tt.expected)
+			}
+		})
+	}
+}
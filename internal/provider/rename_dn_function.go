@@ -0,0 +1,116 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &RenameDNFunction{}
+
+func NewRenameDNFunction() function.Function {
+	return &RenameDNFunction{}
+}
+
+// RenameDNFunction computes the DN an entry will have after its RDN is changed.
+type RenameDNFunction struct{}
+
+func (f *RenameDNFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "rename_dn"
+}
+
+func (f *RenameDNFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Replace the first RDN of a DN",
+		MarkdownDescription: "Returns `dn` with its first (leftmost) RDN replaced by `new_rdn`, keeping the parent DN exactly as written. " +
+			"For example `rename_dn(\"uid=jdoe,ou=users,dc=example,dc=com\", \"uid=john.doe\")` returns `uid=john.doe,ou=users,dc=example,dc=com`. " +
+			"`new_rdn` must be a single, possibly multi-valued, RDN such as `cn=John+sn=Doe`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "dn",
+				MarkdownDescription: "Current distinguished name of the entry.",
+			},
+			function.StringParameter{
+				Name:                "new_rdn",
+				MarkdownDescription: "New relative distinguished name. Special characters in values must be escaped, e.g. `cn=Doe\\, John`.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *RenameDNFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var dn, newRDN string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &dn, &newRDN))
+	if resp.Error != nil {
+		return
+	}
+
+	if err := validateDN(dn); err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	if err := validateRDN(newRDN); err != nil {
+		resp.Error = function.NewArgumentFuncError(1, err.Error())
+		return
+	}
+
+	_, parent := splitDN(dn)
+	renamed := strings.TrimSpace(newRDN)
+	if parent != "" {
+		renamed += "," + parent
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, renamed))
+}
+
+// validateDN checks that dn parses as a DN with at least one RDN.
+func validateDN(dn string) error {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return fmt.Errorf("invalid DN %q: %s", dn, err)
+	}
+	if len(parsed.RDNs) == 0 {
+		return errors.New("DN must not be empty")
+	}
+	return nil
+}
+
+// validateRDN checks that rdn parses as exactly one, possibly multi-valued, RDN.
+func validateRDN(rdn string) error {
+	parsed, err := ldap.ParseDN(rdn)
+	if err != nil {
+		return fmt.Errorf("invalid RDN %q: %s", rdn, err)
+	}
+	if len(parsed.RDNs) != 1 {
+		return fmt.Errorf("RDN %q must contain exactly one RDN, got %d", rdn, len(parsed.RDNs))
+	}
+	return nil
+}
+
+// splitDN splits dn at the first unescaped RDN separator into its leading RDN and parent DN,
+// leaving both as written. parent is empty if dn has a single RDN.
+func splitDN(dn string) (rdn string, parent string) {
+	escaping := false
+	for i := 0; i < len(dn); i++ {
+		switch {
+		case escaping:
+			escaping = false
+		case dn[i] == '\\':
+			escaping = true
+		case dn[i] == ',' || dn[i] == ';':
+			return strings.TrimSpace(dn[:i]), strings.TrimSpace(dn[i+1:])
+		}
+	}
+	return strings.TrimSpace(dn), ""
+}
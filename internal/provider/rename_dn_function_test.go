@@ -0,0 +1,157 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSplitDN(t *testing.T) {
+	tests := []struct {
+		name           string
+		dn             string
+		expectedRDN    string
+		expectedParent string
+	}{
+		{
+			name:           "simple",
+			dn:             "uid=jdoe,ou=users,dc=example,dc=com",
+			expectedRDN:    "uid=jdoe",
+			expectedParent: "ou=users,dc=example,dc=com",
+		},
+		{
+			name:           "escaped comma in rdn",
+			dn:             `cn=Doe\, John,ou=users,dc=example,dc=com`,
+			expectedRDN:    `cn=Doe\, John`,
+			expectedParent: "ou=users,dc=example,dc=com",
+		},
+		{
+			name:           "multi-valued rdn",
+			dn:             "cn=John+sn=Doe,ou=users,dc=example,dc=com",
+			expectedRDN:    "cn=John+sn=Doe",
+			expectedParent: "ou=users,dc=example,dc=com",
+		},
+		{
+			name:        "single rdn",
+			dn:          "dc=com",
+			expectedRDN: "dc=com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rdn, parent := splitDN(tt.dn)
+			if rdn != tt.expectedRDN || parent != tt.expectedParent {
+				t.Errorf("splitDN(%q) = (%q, %q), want (%q, %q)", tt.dn, rdn, parent, tt.expectedRDN, tt.expectedParent)
+			}
+		})
+	}
+}
+
+func TestRenameDNFunction_Run(t *testing.T) {
+	tests := []struct {
+		name        string
+		dn          string
+		newRDN      string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "simple",
+			dn:       "uid=jdoe,ou=users,dc=example,dc=com",
+			newRDN:   "uid=john.doe",
+			expected: "uid=john.doe,ou=users,dc=example,dc=com",
+		},
+		{
+			name:     "multi-valued parent",
+			dn:       "cn=printer,ou=devices+l=Berlin,dc=example,dc=com",
+			newRDN:   "cn=scanner",
+			expected: "cn=scanner,ou=devices+l=Berlin,dc=example,dc=com",
+		},
+		{
+			name:     "multi-valued new rdn",
+			dn:       "cn=John,ou=users,dc=example,dc=com",
+			newRDN:   "cn=John+sn=Doe",
+			expected: "cn=John+sn=Doe,ou=users,dc=example,dc=com",
+		},
+		{
+			name:     "escaped comma in old rdn",
+			dn:       `cn=Doe\, John,ou=users,dc=example,dc=com`,
+			newRDN:   `cn=Doe\, Jane`,
+			expected: `cn=Doe\, Jane,ou=users,dc=example,dc=com`,
+		},
+		{
+			name:     "single rdn",
+			dn:       "dc=com",
+			newRDN:   "dc=org",
+			expected: "dc=org",
+		},
+		{
+			name:        "new rdn with parent",
+			dn:          "uid=jdoe,ou=users,dc=example,dc=com",
+			newRDN:      "uid=john.doe,ou=people",
+			expectError: true,
+		},
+		{
+			name:        "new rdn without value pair",
+			dn:          "uid=jdoe,ou=users,dc=example,dc=com",
+			newRDN:      "john.doe",
+			expectError: true,
+		},
+		{
+			name:        "empty new rdn",
+			dn:          "uid=jdoe,ou=users,dc=example,dc=com",
+			newRDN:      "",
+			expectError: true,
+		},
+		{
+			name:        "invalid dn",
+			dn:          "uid=jdoe,users",
+			newRDN:      "uid=john.doe",
+			expectError: true,
+		},
+		{
+			name:        "empty dn",
+			dn:          "",
+			newRDN:      "uid=john.doe",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue(tt.dn),
+					types.StringValue(tt.newRDN),
+				}),
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.StringUnknown()),
+			}
+
+			NewRenameDNFunction().Run(context.Background(), req, resp)
+
+			if tt.expectError {
+				if resp.Error == nil {
+					t.Errorf("expected error, got result %s", resp.Result.Value())
+				}
+				return
+			}
+
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+
+			if !resp.Result.Value().Equal(types.StringValue(tt.expected)) {
+				t.Errorf("result = %s, want %q", resp.Result.Value(), tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &RenderLDIFFunction{}
+
+func NewRenderLDIFFunction() function.Function {
+	return &RenderLDIFFunction{}
+}
+
+// RenderLDIFFunction renders the LDIF change record adding an entry.
+type RenderLDIFFunction struct{}
+
+func (f *RenderLDIFFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "render_ldif"
+}
+
+func (f *RenderLDIFFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Render the LDIF adding an entry",
+		MarkdownDescription: "Returns the LDIF (RFC 2849) change record with `changetype: add` that adds the entry `dn` with `attributes`, e.g. to preview in an output what an `ldap_entry` with the same `dn` and `attributes` creates, " +
+			"or to hand the entry to `ldapadd`. " +
+			"`objectClass` comes first, the other attributes follow sorted by name, with one line per value. " +
+			"Values that are not plain ASCII text, such as binary values, values with line breaks or non-ASCII characters, and values starting with a space, `:` or `<` are base64-encoded (`attribute:: <base64>`), " +
+			"and lines longer than 76 characters are folded. " +
+			"The names of attributes whose values are given base64-encoded, like in `ldap_entry.binary_attributes`, can follow `attributes`; their values are decoded first. " +
+			"`dn` is written as given, so pass the absolute DN if the provider sets `base_dn`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "dn",
+				MarkdownDescription: "Distinguished name of the entry.",
+			},
+			function.MapParameter{
+				Name:                "attributes",
+				MarkdownDescription: "Attributes of the entry, in the format of `ldap_entry.attributes`.",
+				ElementType:         attributeValuesType,
+			},
+		},
+		VariadicParameter: function.StringParameter{
+			Name:                "binary_attributes",
+			MarkdownDescription: "Attributes in `attributes` whose values are base64-encoded, e.g. `jpegPhoto`.",
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *RenderLDIFFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var dn string
+	var attributesMap types.Map
+	var binaryAttributes []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &dn, &attributesMap, &binaryAttributes))
+	if resp.Error != nil {
+		return
+	}
+
+	if err := validateDN(dn); err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	attributes := make(map[string][]string)
+	if diags := attributesMap.ElementsAs(ctx, &attributes, false); diags.HasError() {
+		resp.Error = function.FuncErrorFromDiags(ctx, diags)
+		return
+	}
+	if err := DecodeBinaryAttributes(attributes, binaryAttributes); err != nil {
+		resp.Error = function.NewArgumentFuncError(1, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, ldifAddRecord(dn, attributes)))
+}
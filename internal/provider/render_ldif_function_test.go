@@ -0,0 +1,115 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestRenderLDIFFunction_Run(t *testing.T) {
+	tests := []struct {
+		name             string
+		dn               string
+		attributes       map[string][]string
+		binaryAttributes []string
+		expected         string
+		expectError      bool
+	}{
+		{
+			name: "multi-valued attribute",
+			dn:   "uid=jdoe,ou=users,dc=example,dc=com",
+			attributes: map[string][]string{
+				"objectClass": {"inetOrgPerson"},
+				"uid":         {"jdoe"},
+				"mail":        {"jdoe@example.com", "john.doe@example.com"},
+			},
+			expected: "dn: uid=jdoe,ou=users,dc=example,dc=com\n" +
+				"changetype: add\n" +
+				"objectClass: inetOrgPerson\n" +
+				"mail: jdoe@example.com\n" +
+				"mail: john.doe@example.com\n" +
+				"uid: jdoe\n",
+		},
+		{
+			name: "binary attribute",
+			dn:   "uid=jdoe,ou=users,dc=example,dc=com",
+			attributes: map[string][]string{
+				"objectClass": {"inetOrgPerson"},
+				"jpegPhoto":   {"/9j/AA=="},
+				"description": {"/9j/AA=="},
+			},
+			binaryAttributes: []string{"jpegPhoto"},
+			expected: "dn: uid=jdoe,ou=users,dc=example,dc=com\n" +
+				"changetype: add\n" +
+				"objectClass: inetOrgPerson\n" +
+				"description: /9j/AA==\n" +
+				"jpegPhoto:: /9j/AA==\n",
+		},
+		{
+			name:             "binary attribute not base64",
+			dn:               "uid=jdoe,ou=users,dc=example,dc=com",
+			attributes:       map[string][]string{"jpegPhoto": {"not base64!"}},
+			binaryAttributes: []string{"jpegPhoto"},
+			expectError:      true,
+		},
+		{
+			name:        "invalid dn",
+			dn:          "uid=jdoe,users",
+			attributes:  map[string][]string{"uid": {"jdoe"}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attributes := make(map[string]attr.Value, len(tt.attributes))
+			for name, values := range tt.attributes {
+				elements := make([]attr.Value, len(values))
+				for i, v := range values {
+					elements[i] = types.StringValue(v)
+				}
+				attributes[name] = types.ListValueMust(types.StringType, elements)
+			}
+			binaryAttributes := make([]attr.Value, len(tt.binaryAttributes))
+			binaryAttributeTypes := make([]attr.Type, len(tt.binaryAttributes))
+			for i, name := range tt.binaryAttributes {
+				binaryAttributes[i] = types.StringValue(name)
+				binaryAttributeTypes[i] = types.StringType
+			}
+
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue(tt.dn),
+					types.MapValueMust(attributeValuesType, attributes),
+					types.TupleValueMust(binaryAttributeTypes, binaryAttributes),
+				}),
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.StringUnknown()),
+			}
+
+			NewRenderLDIFFunction().Run(context.Background(), req, resp)
+
+			if tt.expectError {
+				if resp.Error == nil {
+					t.Errorf("expected error, got result %s", resp.Result.Value())
+				}
+				return
+			}
+
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+
+			if !resp.Result.Value().Equal(types.StringValue(tt.expected)) {
+				t.Errorf("result = %s, want %q", resp.Result.Value(), tt.expected)
+			}
+		})
+	}
+}
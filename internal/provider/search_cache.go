@@ -0,0 +1,63 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// searchCache remembers search results for the lifetime of a provider instance, which is a
+// single plan or apply. The directory is assumed not to change meanwhile, so entries are never
+// invalidated. Errors are not cached.
+type searchCache struct {
+	mu      sync.Mutex
+	results map[string]*ldap.SearchResult
+}
+
+func newSearchCache() *searchCache {
+	return &searchCache{results: make(map[string]*ldap.SearchResult)}
+}
+
+// search returns the cached result of an identical earlier search, or runs it with LdapSearch.
+// Searches sending controls bypass the cache, since controls may change what is returned.
+func (c *searchCache) search(conn LdapSearcher, baseDN string, scope string, filter string, attributes []string, opts LdapSearchOptions) (*ldap.SearchResult, error) {
+	if len(opts.Controls) > 0 {
+		return LdapSearch(conn, baseDN, scope, filter, attributes, opts)
+	}
+
+	key := searchCacheKey(baseDN, scope, filter, attributes, opts)
+
+	c.mu.Lock()
+	sr, ok := c.results[key]
+	c.mu.Unlock()
+	if ok {
+		return sr, nil
+	}
+
+	sr, err := LdapSearch(conn, baseDN, scope, filter, attributes, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.results[key] = sr
+	c.mu.Unlock()
+
+	return sr, nil
+}
+
+// searchCacheKey identifies a search by everything that affects its result.
+func searchCacheKey(baseDN string, scope string, filter string, attributes []string, opts LdapSearchOptions) string {
+	return strings.Join([]string{
+		baseDN,
+		scope,
+		filter,
+		strings.Join(attributes, "\x01"),
+		fmt.Sprintf("%d/%t/%d", opts.DerefAliases, opts.TypesOnly, opts.PageSize),
+	}, "\x00")
+}
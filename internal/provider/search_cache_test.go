@@ -0,0 +1,67 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestSearchCache(t *testing.T) {
+	type search struct {
+		baseDN     string
+		filter     string
+		attributes []string
+		opts       LdapSearchOptions
+	}
+	base := search{baseDN: "ou=users,dc=example,dc=com", filter: "(uid=jdoe)", attributes: []string{"cn"}}
+
+	tests := []struct {
+		name     string
+		second   search
+		expected int
+	}{
+		{name: "identical search is reused", second: base, expected: 1},
+		{name: "different base DN", second: search{baseDN: "ou=groups,dc=example,dc=com", filter: base.filter, attributes: base.attributes}, expected: 2},
+		{name: "different filter", second: search{baseDN: base.baseDN, filter: "(uid=other)", attributes: base.attributes}, expected: 2},
+		{name: "different attributes", second: search{baseDN: base.baseDN, filter: base.filter, attributes: []string{"cn", "mail"}}, expected: 2},
+		{name: "types only", second: search{baseDN: base.baseDN, filter: base.filter, attributes: base.attributes, opts: LdapSearchOptions{TypesOnly: true}}, expected: 2},
+		{name: "controls bypass the cache", second: search{baseDN: base.baseDN, filter: base.filter, attributes: base.attributes, opts: LdapSearchOptions{Controls: []ldap.Control{ldap.NewControlManageDsaIT(false)}}}, expected: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searcher := &recordingSearcher{}
+			cache := newSearchCache()
+
+			for _, s := range []search{base, tt.second} {
+				if _, err := cache.search(searcher, s.baseDN, "sub", s.filter, s.attributes, s.opts); err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+			}
+
+			if got := len(searcher.requests); got != tt.expected {
+				t.Errorf("server searches = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSearchCache_ErrorsNotCached(t *testing.T) {
+	cache := newSearchCache()
+
+	if _, err := cache.search(&staticSearcher{err: errors.New("busy")}, "dc=example,dc=com", "base", "(objectClass=*)", nil, LdapSearchOptions{}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	searcher := &recordingSearcher{}
+	if _, err := cache.search(searcher, "dc=example,dc=com", "base", "(objectClass=*)", nil, LdapSearchOptions{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(searcher.requests) != 1 {
+		t.Errorf("server searches = %d, want 1", len(searcher.requests))
+	}
+}
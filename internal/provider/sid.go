@@ -0,0 +1,49 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// formatSID converts a binary Windows security identifier, as stored in objectSid or returned in
+// tokenGroups, to its string form such as "S-1-5-21-1004336348-1177238915-682003330-512".
+//
+// The binary form is a revision byte, a sub-authority count, a 48-bit big-endian identifier
+// authority and the sub-authorities as 32-bit little-endian integers (MS-DTYP 2.4.2.2).
+func formatSID(b []byte) (string, error) {
+	if len(b) < 8 {
+		return "", fmt.Errorf("SID is %d bytes long, expected at least 8", len(b))
+	}
+
+	count := int(b[1])
+	if len(b) != 8+4*count {
+		return "", fmt.Errorf("SID with %d sub-authorities is %d bytes long, expected %d", count, len(b), 8+4*count)
+	}
+
+	var authority uint64
+	for _, v := range b[2:8] {
+		authority = authority<<8 | uint64(v)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("S-")
+	sb.WriteString(strconv.Itoa(int(b[0])))
+	sb.WriteByte('-')
+	// Authorities that do not fit 32 bits are written in hexadecimal, as Windows does
+	if authority >= 1<<32 {
+		fmt.Fprintf(&sb, "0x%012X", authority)
+	} else {
+		sb.WriteString(strconv.FormatUint(authority, 10))
+	}
+	for i := 0; i < count; i++ {
+		sb.WriteByte('-')
+		sb.WriteString(strconv.FormatUint(uint64(binary.LittleEndian.Uint32(b[8+4*i:])), 10))
+	}
+
+	return sb.String(), nil
+}
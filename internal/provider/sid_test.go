@@ -0,0 +1,154 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// sidBytes encodes a SID with revision 1 in its binary form.
+func sidBytes(authority uint64, subAuthorities ...uint32) []byte {
+	b := []byte{1, byte(len(subAuthorities))}
+	for shift := 40; shift >= 0; shift -= 8 {
+		b = append(b, byte(authority>>shift))
+	}
+	for _, s := range subAuthorities {
+		b = binary.LittleEndian.AppendUint32(b, s)
+	}
+	return b
+}
+
+func TestFormatSID(t *testing.T) {
+	tests := []struct {
+		name        string
+		sid         []byte
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "domain group",
+			sid:      sidBytes(5, 21, 1004336348, 1177238915, 682003330, 512),
+			expected: "S-1-5-21-1004336348-1177238915-682003330-512",
+		},
+		{
+			name:     "well-known builtin",
+			sid:      sidBytes(5, 32, 544),
+			expected: "S-1-5-32-544",
+		},
+		{
+			name:     "everyone",
+			sid:      sidBytes(1, 0),
+			expected: "S-1-1-0",
+		},
+		{
+			name:     "no sub-authorities",
+			sid:      sidBytes(5),
+			expected: "S-1-5",
+		},
+		{
+			name:     "large authority",
+			sid:      sidBytes(0x010000000000, 1),
+			expected: "S-1-0x010000000000-1",
+		},
+		{
+			name:        "too short",
+			sid:         []byte{1, 1, 0, 0},
+			expectError: true,
+		},
+		{
+			name:        "truncated sub-authority",
+			sid:         sidBytes(5, 21, 512)[:14],
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatSID(tt.sid)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tt.expected {
+				t.Errorf("formatSID() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// adTokenGroupsSearcher mimics Active Directory, which only returns the constructed tokenGroups
+// attribute for base-scope searches naming it.
+type adTokenGroupsSearcher struct {
+	dn          string
+	tokenGroups [][]byte
+}
+
+func (s *adTokenGroupsSearcher) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	entry := &ldap.Entry{DN: s.dn}
+	if req.Scope == ldap.ScopeBaseObject {
+		for _, attr := range req.Attributes {
+			if attr == "tokenGroups" {
+				entry.Attributes = append(entry.Attributes, &ldap.EntryAttribute{Name: "tokenGroups", ByteValues: s.tokenGroups})
+			}
+		}
+	}
+	return &ldap.SearchResult{Entries: []*ldap.Entry{entry}}, nil
+}
+
+func TestTokenGroupsFromADFixture(t *testing.T) {
+	dn := "CN=Jane Doe,CN=Users,DC=example,DC=com"
+	searcher := &adTokenGroupsSearcher{
+		dn: dn,
+		tokenGroups: [][]byte{
+			sidBytes(5, 21, 1004336348, 1177238915, 682003330, 513),
+			sidBytes(5, 21, 1004336348, 1177238915, 682003330, 1108),
+			sidBytes(5, 32, 545),
+		},
+	}
+
+	sr, err := LdapSearch(searcher, dn, "base", "(objectClass=*)", []string{"tokenGroups"}, LdapSearchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected search error: %s", err)
+	}
+
+	results, err := MarshalLdapResults(context.Background(), sr, []string{"tokenGroups"}, MarshalOptions{SIDAttributes: []string{"tokengroups"}})
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %s", err)
+	}
+
+	var attributes map[string][]string
+	if diags := results[0].Attributes.ElementsAs(context.Background(), &attributes, false); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	expected := []string{
+		"S-1-5-21-1004336348-1177238915-682003330-513",
+		"S-1-5-21-1004336348-1177238915-682003330-1108",
+		"S-1-5-32-545",
+	}
+	if !reflect.DeepEqual(attributes["tokenGroups"], expected) {
+		t.Errorf("tokenGroups = %v, want %v", attributes["tokenGroups"], expected)
+	}
+}
+
+func TestMarshalLdapResults_InvalidSID(t *testing.T) {
+	sr := &ldap.SearchResult{Entries: []*ldap.Entry{{
+		DN:         "CN=Jane Doe,CN=Users,DC=example,DC=com",
+		Attributes: []*ldap.EntryAttribute{{Name: "objectSid", ByteValues: [][]byte{{1, 5, 0}}}},
+	}}}
+
+	if _, err := MarshalLdapResults(context.Background(), sr, nil, MarshalOptions{SIDAttributes: []string{"objectSid"}}); err == nil {
+		t.Fatal("expected error for a malformed SID")
+	}
+}
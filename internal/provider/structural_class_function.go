@@ -0,0 +1,143 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &StructuralClassFunction{}
+
+func NewStructuralClassFunction() function.Function {
+	return &StructuralClassFunction{}
+}
+
+// StructuralClassFunction picks the most derived structural object class from a class list.
+type StructuralClassFunction struct{}
+
+// structuralClassSuperiors maps common structural object classes, in lower case, to their
+// structural superiors (none for classes derived directly from top). A class has several when
+// directories disagree: Active Directory derives inetOrgPerson from user rather than from
+// organizationalPerson. Sources are RFC 4519, RFC 2798, RFC 4524 and the Active Directory schema.
+// Auxiliary classes such as dcObject or posixAccount are deliberately missing.
+var structuralClassSuperiors = map[string][]string{
+	// RFC 4519
+	"applicationprocess":   nil,
+	"country":              nil,
+	"device":               nil,
+	"groupofnames":         nil,
+	"groupofuniquenames":   nil,
+	"locality":             nil,
+	"organization":         nil,
+	"organizationalperson": {"person"},
+	"organizationalrole":   nil,
+	"organizationalunit":   nil,
+	"person":               nil,
+	"residentialperson":    {"person"},
+	// RFC 2798
+	"inetorgperson": {"organizationalperson", "user"},
+	// RFC 4524
+	"account":  nil,
+	"document": nil,
+	"domain":   nil,
+	// Active Directory
+	"computer":  {"user"},
+	"contact":   {"organizationalperson"},
+	"container": nil,
+	"group":     nil,
+	"user":      {"organizationalperson"},
+}
+
+func (f *StructuralClassFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "structural_class"
+}
+
+func (f *StructuralClassFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Find the structural object class in a class list",
+		MarkdownDescription: "Returns the most derived structural object class in `classes`, e.g. `inetOrgPerson` for `[\"top\", \"person\", \"organizationalPerson\", \"inetOrgPerson\"]`, spelled as in `classes`. " +
+			"The server schema is not consulted: the function knows the common structural classes of RFC 4519, RFC 2798 and RFC 4524 " +
+			"(such as `person`, `organizationalPerson`, `inetOrgPerson`, `groupOfNames`, `groupOfUniqueNames`, `organizationalUnit`, `organization`, `domain` and `account`) " +
+			"and of Active Directory (`user`, `computer`, `contact`, `group` and `container`), compared ignoring case. Other classes, including all auxiliary classes, are ignored. " +
+			"Fails if `classes` contains none of the known structural classes, or several that do not derive from one another (e.g. `person` and `groupOfNames`).",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:                "classes",
+				MarkdownDescription: "Object classes of an entry, in any order.",
+				ElementType:         types.StringType,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *StructuralClassFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var classes []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &classes))
+	if resp.Error != nil {
+		return
+	}
+
+	class, err := structuralClass(classes)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, class))
+}
+
+// structuralClass returns the class of classes that every other known structural class of classes
+// is a superior of.
+func structuralClass(classes []string) (string, error) {
+	var structural []string
+	for _, class := range classes {
+		if _, known := structuralClassSuperiors[strings.ToLower(class)]; known {
+			structural = append(structural, class)
+		}
+	}
+	if len(structural) == 0 {
+		return "", errors.New("classes contain no known structural object class")
+	}
+
+	for _, candidate := range structural {
+		superiors := structuralClassAncestors(candidate)
+		derived := true
+		for _, other := range structural {
+			if !strings.EqualFold(other, candidate) && !superiors[strings.ToLower(other)] {
+				derived = false
+				break
+			}
+		}
+		if derived {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("structural object classes %s do not form a single chain", strings.Join(structural, ", "))
+}
+
+// structuralClassAncestors returns the lower-case names of all structural superiors of class.
+func structuralClassAncestors(class string) map[string]bool {
+	ancestors := make(map[string]bool)
+	pending := structuralClassSuperiors[strings.ToLower(class)]
+	for len(pending) > 0 {
+		superior := pending[0]
+		pending = pending[1:]
+		if ancestors[superior] {
+			continue
+		}
+		ancestors[superior] = true
+		pending = append(pending, structuralClassSuperiors[superior]...)
+	}
+	return ancestors
+}
@@ -0,0 +1,74 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestStructuralClassFunction_Run(t *testing.T) {
+	tests := []struct {
+		name        string
+		classes     []string
+		expected    string
+		expectError bool
+	}{
+		{name: "inetOrgPerson chain", classes: []string{"top", "person", "organizationalPerson", "inetOrgPerson"}, expected: "inetOrgPerson"},
+		{name: "any order", classes: []string{"inetOrgPerson", "top", "organizationalPerson", "person"}, expected: "inetOrgPerson"},
+		{name: "person only", classes: []string{"top", "person"}, expected: "person"},
+		{name: "with auxiliary classes", classes: []string{"person", "organizationalPerson", "posixAccount", "shadowAccount"}, expected: "organizationalPerson"},
+		{name: "case preserved", classes: []string{"TOP", "PERSON", "ORGANIZATIONALPERSON"}, expected: "ORGANIZATIONALPERSON"},
+		{name: "groupOfNames", classes: []string{"top", "groupOfNames"}, expected: "groupOfNames"},
+		{name: "groupOfUniqueNames", classes: []string{"groupOfUniqueNames", "top"}, expected: "groupOfUniqueNames"},
+		{name: "groupOfNames with posixGroup auxiliary", classes: []string{"groupOfNames", "posixGroup"}, expected: "groupOfNames"},
+		{name: "ad group", classes: []string{"top", "group"}, expected: "group"},
+		{name: "ad user", classes: []string{"top", "person", "organizationalPerson", "user"}, expected: "user"},
+		{name: "ad computer", classes: []string{"top", "person", "organizationalPerson", "user", "computer"}, expected: "computer"},
+		{name: "ad inetOrgPerson", classes: []string{"top", "person", "organizationalPerson", "user", "inetOrgPerson"}, expected: "inetOrgPerson"},
+		{name: "organizational unit", classes: []string{"top", "organizationalUnit"}, expected: "organizationalUnit"},
+		{name: "domain with dcObject", classes: []string{"dcObject", "organization"}, expected: "organization"},
+		{name: "unrelated chains", classes: []string{"person", "groupOfNames"}, expectError: true},
+		{name: "sibling classes", classes: []string{"person", "organizationalPerson", "residentialPerson"}, expectError: true},
+		{name: "only auxiliary", classes: []string{"top", "posixAccount"}, expectError: true},
+		{name: "empty", classes: []string{}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elements := make([]attr.Value, len(tt.classes))
+			for i, class := range tt.classes {
+				elements[i] = types.StringValue(class)
+			}
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.ListValueMust(types.StringType, elements),
+				}),
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.StringUnknown()),
+			}
+
+			NewStructuralClassFunction().Run(context.Background(), req, resp)
+
+			if tt.expectError {
+				if resp.Error == nil {
+					t.Errorf("expected error, got %s", resp.Result.Value())
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+
+			if !resp.Result.Value().Equal(types.StringValue(tt.expected)) {
+				t.Errorf("result = %s, want %q", resp.Result.Value(), tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// OIDs of LDAP transactions, see RFC 5805.
+const (
+	oidStartTransaction         = "1.3.6.1.1.21.1"
+	oidTransactionSpecification = "1.3.6.1.1.21.2"
+	oidEndTransaction           = "1.3.6.1.1.21.3"
+)
+
+// Actions of a transaction operation.
+const (
+	transactionActionAdd           = "add"
+	transactionActionDelete        = "delete"
+	transactionActionAddValues     = "add_values"
+	transactionActionDeleteValues  = "delete_values"
+	transactionActionReplaceValues = "replace_values"
+)
+
+var transactionActions = []string{
+	transactionActionAdd,
+	transactionActionDelete,
+	transactionActionAddValues,
+	transactionActionDeleteValues,
+	transactionActionReplaceValues,
+}
+
+// Stages of a transaction, reported by transactionError.
+const (
+	transactionStageStart     = "start"
+	transactionStageOperation = "operation"
+	transactionStageCommit    = "commit"
+)
+
+// transactionConn is the subset of *ldap.Conn a transaction runs on. All requests of a transaction
+// must be sent on the same connection.
+type transactionConn interface {
+	Extended(request *ldap.ExtendedRequest) (*ldap.ExtendedResponse, error)
+	Add(addRequest *ldap.AddRequest) error
+	Modify(modifyRequest *ldap.ModifyRequest) error
+	Del(delRequest *ldap.DelRequest) error
+}
+
+// transactionOperation is one write of a transaction.
+type transactionOperation struct {
+	Action     string
+	DN         string
+	Attributes map[string][]string
+}
+
+// send issues the operation on conn with controls, which carry the transaction specification.
+func (op transactionOperation) send(conn transactionConn, controls []ldap.Control) error {
+	switch op.Action {
+	case transactionActionAdd:
+		req := ldap.NewAddRequest(op.DN, controls)
+		for _, name := range sortedAttributeNames(op.Attributes) {
+			req.Attribute(name, op.Attributes[name])
+		}
+		return conn.Add(req)
+	case transactionActionDelete:
+		return conn.Del(ldap.NewDelRequest(op.DN, controls))
+	case transactionActionAddValues, transactionActionDeleteValues, transactionActionReplaceValues:
+		req := ldap.NewModifyRequest(op.DN, controls)
+		for _, name := range sortedAttributeNames(op.Attributes) {
+			switch op.Action {
+			case transactionActionAddValues:
+				req.Add(name, op.Attributes[name])
+			case transactionActionDeleteValues:
+				req.Delete(name, op.Attributes[name])
+			default:
+				req.Replace(name, op.Attributes[name])
+			}
+		}
+		return conn.Modify(req)
+	default:
+		return fmt.Errorf("unsupported action %q", op.Action)
+	}
+}
+
+// sortedAttributeNames returns the names of attributes in a stable order, so requests are reproducible.
+func sortedAttributeNames(attributes map[string][]string) []string {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// transactionError reports the stage of a transaction that failed. When an operation or the
+// commit failed, none of the operations were applied.
+type transactionError struct {
+	Stage string
+	// Operation is the index of the failed operation in the operation stage.
+	Operation int
+	Err       error
+	// AbortErr is set when the transaction could not be aborted after a failed operation. The
+	// server discards it at the latest when the connection is closed.
+	AbortErr error
+}
+
+func (e *transactionError) Error() string {
+	switch e.Stage {
+	case transactionStageStart:
+		return fmt.Sprintf("unable to start transaction: %s", e.Err)
+	case transactionStageOperation:
+		if e.AbortErr != nil {
+			return fmt.Sprintf("operation %d failed: %s (aborting the transaction failed as well: %s)", e.Operation, e.Err, e.AbortErr)
+		}
+		return fmt.Sprintf("operation %d failed: %s", e.Operation, e.Err)
+	default:
+		return fmt.Sprintf("unable to commit transaction: %s", e.Err)
+	}
+}
+
+func (e *transactionError) Unwrap() error {
+	return e.Err
+}
+
+// RunTransaction applies operations atomically in one LDAP transaction (RFC 5805) on the current
+// connection, see do: either all of them are applied or none is. Failures are returned as
+// *transactionError. A transaction whose connection broke before the commit was discarded by the
+// server and runs again from the start; one whose connection broke during the commit may have
+// been applied and is not repeated.
+func (c *LdapClient) RunTransaction(operations []transactionOperation) error {
+	var commitErr error
+	err := c.do(func(conn *ldap.Conn) error {
+		err := runTransaction(conn, operations)
+		var txnErr *transactionError
+		if errors.As(err, &txnErr) && txnErr.Stage == transactionStageCommit {
+			commitErr = err
+			return nil
+		}
+		return err
+	})
+	if commitErr != nil {
+		return commitErr
+	}
+	return err
+}
+
+// runTransaction starts a transaction on conn, sends operations with the transaction
+// specification control and commits. It aborts the transaction if an operation fails.
+func runTransaction(conn transactionConn, operations []transactionOperation) error {
+	id, err := startTransaction(conn)
+	if err != nil {
+		return &transactionError{Stage: transactionStageStart, Err: err}
+	}
+
+	controls := []ldap.Control{ldap.NewControlString(oidTransactionSpecification, true, id)}
+	for i, op := range operations {
+		if err := op.send(conn, controls); err != nil {
+			return &transactionError{
+				Stage:     transactionStageOperation,
+				Operation: i,
+				Err:       err,
+				AbortErr:  endTransaction(conn, id, false),
+			}
+		}
+	}
+
+	if err := endTransaction(conn, id, true); err != nil {
+		return &transactionError{Stage: transactionStageCommit, Err: err}
+	}
+	return nil
+}
+
+// startTransaction sends a Start Transaction request and returns the transaction identifier.
+func startTransaction(conn transactionConn) (string, error) {
+	resp, err := conn.Extended(ldap.NewExtendedRequest(oidStartTransaction, nil))
+	if err != nil {
+		return "", extendedOperationError("Start Transaction", oidStartTransaction, err)
+	}
+	if resp == nil || resp.Value == nil || resp.Value.Data.Len() == 0 {
+		return "", errors.New("server returned no transaction identifier")
+	}
+	return resp.Value.Data.String(), nil
+}
+
+// endTransaction sends an End Transaction request that commits, or aborts, transaction id.
+func endTransaction(conn transactionConn, id string, commit bool) error {
+	_, err := conn.Extended(ldap.NewExtendedRequest(oidEndTransaction, encodeEndTransactionValue(id, commit)))
+	if err != nil {
+		return extendedOperationError("End Transaction", oidEndTransaction, err)
+	}
+	return nil
+}
+
+// encodeEndTransactionValue encodes the requestValue of an End Transaction request:
+//
+//	txnEndReq ::= SEQUENCE {
+//	     commit         BOOLEAN DEFAULT TRUE,
+//	     identifier     OCTET STRING }
+func encodeEndTransactionValue(id string, commit bool) *ber.Packet {
+	value := ber.Encode(ber.ClassContext, ber.TypePrimitive, 1, nil, "End Transaction Request Value")
+	request := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "txnEndReq")
+	if !commit {
+		request.AppendChild(ber.NewBoolean(ber.ClassUniversal, ber.TypePrimitive, ber.TagBoolean, false, "commit"))
+	}
+	request.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, id, "identifier"))
+	value.AppendChild(request)
+	return value
+}
@@ -0,0 +1,300 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// fakeTransactionConn records the requests of a transaction. Requests fail with the error
+// configured for their description, e.g. "add uid=jdoe,dc=example,dc=com" or "commit".
+type fakeTransactionConn struct {
+	id       string
+	failures map[string]error
+	requests []string
+	// untagged lists requests sent without the transaction specification control of id.
+	untagged []string
+}
+
+func (c *fakeTransactionConn) record(request string, controls []ldap.Control) error {
+	c.requests = append(c.requests, request)
+	tagged := false
+	for _, control := range controls {
+		if s, ok := control.(*ldap.ControlString); ok && s.ControlType == oidTransactionSpecification && s.ControlValue == c.id && s.Criticality {
+			tagged = true
+		}
+	}
+	if !tagged {
+		c.untagged = append(c.untagged, request)
+	}
+	return c.failures[request]
+}
+
+func (c *fakeTransactionConn) Extended(request *ldap.ExtendedRequest) (*ldap.ExtendedResponse, error) {
+	switch request.Name {
+	case oidStartTransaction:
+		c.requests = append(c.requests, "start")
+		if err := c.failures["start"]; err != nil {
+			return nil, err
+		}
+		return &ldap.ExtendedResponse{
+			Value: ber.NewString(ber.ClassContext, ber.TypePrimitive, 11, c.id, "Transaction Identifier"),
+		}, nil
+	case oidEndTransaction:
+		txnEndReq := ber.DecodePacket(request.Value.Children[0].Bytes())
+		commit := true
+		id := ""
+		for _, child := range txnEndReq.Children {
+			switch child.Tag {
+			case ber.TagBoolean:
+				commit = child.Value.(bool)
+			case ber.TagOctetString:
+				id = child.Value.(string)
+			}
+		}
+		if id != c.id {
+			return nil, errors.New("unknown transaction")
+		}
+		stage := "abort"
+		if commit {
+			stage = "commit"
+		}
+		c.requests = append(c.requests, stage)
+		return &ldap.ExtendedResponse{}, c.failures[stage]
+	}
+	return nil, errors.New("unexpected extended request")
+}
+
+func (c *fakeTransactionConn) Add(addRequest *ldap.AddRequest) error {
+	return c.record("add "+addRequest.DN, addRequest.Controls)
+}
+
+func (c *fakeTransactionConn) Modify(modifyRequest *ldap.ModifyRequest) error {
+	return c.record("modify "+modifyRequest.DN, modifyRequest.Controls)
+}
+
+func (c *fakeTransactionConn) Del(delRequest *ldap.DelRequest) error {
+	return c.record("delete "+delRequest.DN, delRequest.Controls)
+}
+
+var testTransactionOperations = []transactionOperation{
+	{
+		Action: transactionActionAdd,
+		DN:     "uid=jdoe,ou=users,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"objectClass": {"inetOrgPerson"},
+			"uid":         {"jdoe"},
+			"cn":          {"John Doe"},
+			"sn":          {"Doe"},
+		},
+	},
+	{
+		Action:     transactionActionAddValues,
+		DN:         "cn=developers,ou=groups,dc=example,dc=com",
+		Attributes: map[string][]string{"member": {"uid=jdoe,ou=users,dc=example,dc=com"}},
+	},
+}
+
+func TestRunTransaction(t *testing.T) {
+	conn := &fakeTransactionConn{id: "txn-1"}
+
+	if err := runTransaction(conn, testTransactionOperations); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []string{"start", "add uid=jdoe,ou=users,dc=example,dc=com", "modify cn=developers,ou=groups,dc=example,dc=com", "commit"}
+	if !reflect.DeepEqual(conn.requests, expected) {
+		t.Errorf("requests = %v, want %v", conn.requests, expected)
+	}
+	if len(conn.untagged) != 0 {
+		t.Errorf("expected every operation to carry the transaction specification, got %v without", conn.untagged)
+	}
+}
+
+func TestRunTransaction_Failures(t *testing.T) {
+	tests := []struct {
+		name             string
+		failures         map[string]error
+		expectedStage    string
+		expectedIndex    int
+		expectedRequests []string
+		expectAbortErr   bool
+	}{
+		{
+			name:             "start",
+			failures:         map[string]error{"start": errors.New("unsupported extended operation")},
+			expectedStage:    transactionStageStart,
+			expectedRequests: []string{"start"},
+		},
+		{
+			name:             "operation",
+			failures:         map[string]error{"modify cn=developers,ou=groups,dc=example,dc=com": errors.New("no such object")},
+			expectedStage:    transactionStageOperation,
+			expectedIndex:    1,
+			expectedRequests: []string{"start", "add uid=jdoe,ou=users,dc=example,dc=com", "modify cn=developers,ou=groups,dc=example,dc=com", "abort"},
+		},
+		{
+			name: "operation and abort",
+			failures: map[string]error{
+				"add uid=jdoe,ou=users,dc=example,dc=com": errors.New("entry already exists"),
+				"abort": errors.New("connection closed"),
+			},
+			expectedStage:    transactionStageOperation,
+			expectedRequests: []string{"start", "add uid=jdoe,ou=users,dc=example,dc=com", "abort"},
+			expectAbortErr:   true,
+		},
+		{
+			name:             "commit",
+			failures:         map[string]error{"commit": errors.New("entry already exists")},
+			expectedStage:    transactionStageCommit,
+			expectedRequests: []string{"start", "add uid=jdoe,ou=users,dc=example,dc=com", "modify cn=developers,ou=groups,dc=example,dc=com", "commit"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &fakeTransactionConn{id: "txn-1", failures: tt.failures}
+
+			err := runTransaction(conn, testTransactionOperations)
+
+			var txnErr *transactionError
+			if !errors.As(err, &txnErr) {
+				t.Fatalf("expected a transaction error, got %v", err)
+			}
+			if txnErr.Stage != tt.expectedStage {
+				t.Errorf("stage = %q, want %q", txnErr.Stage, tt.expectedStage)
+			}
+			if tt.expectedStage == transactionStageOperation && txnErr.Operation != tt.expectedIndex {
+				t.Errorf("operation = %d, want %d", txnErr.Operation, tt.expectedIndex)
+			}
+			if (txnErr.AbortErr != nil) != tt.expectAbortErr {
+				t.Errorf("abort error = %v, want error: %t", txnErr.AbortErr, tt.expectAbortErr)
+			}
+			if !reflect.DeepEqual(conn.requests, tt.expectedRequests) {
+				t.Errorf("requests = %v, want %v", conn.requests, tt.expectedRequests)
+			}
+		})
+	}
+}
+
+func TestTransactionOperationSend(t *testing.T) {
+	conn := &recordingModifyConn{}
+	op := transactionOperation{
+		Action:     transactionActionDeleteValues,
+		DN:         "cn=developers,ou=groups,dc=example,dc=com",
+		Attributes: map[string][]string{"member": {"uid=jdoe,ou=users,dc=example,dc=com"}, "description": {}},
+	}
+
+	if err := op.send(conn, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []ldap.Change{
+		{Operation: ldap.DeleteAttribute, Modification: ldap.PartialAttribute{Type: "description", Vals: []string{}}},
+		{Operation: ldap.DeleteAttribute, Modification: ldap.PartialAttribute{Type: "member", Vals: []string{"uid=jdoe,ou=users,dc=example,dc=com"}}},
+	}
+	if conn.request == nil || !reflect.DeepEqual(conn.request.Changes, expected) {
+		t.Errorf("changes = %+v, want %+v", conn.request, expected)
+	}
+}
+
+// recordingModifyConn keeps the last modify request sent to it.
+type recordingModifyConn struct {
+	fakeTransactionConn
+	request *ldap.ModifyRequest
+}
+
+func (c *recordingModifyConn) Modify(modifyRequest *ldap.ModifyRequest) error {
+	c.request = modifyRequest
+	return nil
+}
+
+func TestTransactionErrorDiagnostic(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		summary  string
+		contains string
+	}{
+		{
+			name:     "start",
+			err:      &transactionError{Stage: transactionStageStart, Err: errors.New("unsupported")},
+			summary:  "Unable to start LDAP transaction",
+			contains: "supportedExtension",
+		},
+		{
+			name: "start unsupported",
+			err: &transactionError{
+				Stage: transactionStageStart,
+				Err:   extendedOperationError("Start Transaction", oidStartTransaction, ldap.NewError(ldap.LDAPResultProtocolError, errors.New("unsupported extended operation"))),
+			},
+			summary:  "LDAP extended operation not supported",
+			contains: "Start Transaction extended operation (OID 1.3.6.1.1.21.1)",
+		},
+		{
+			name:     "operation",
+			err:      &transactionError{Stage: transactionStageOperation, Operation: 1, Err: errors.New("no such object")},
+			summary:  "LDAP transaction aborted",
+			contains: "add_values cn=developers,ou=groups,dc=example,dc=com",
+		},
+		{
+			name:     "commit",
+			err:      &transactionError{Stage: transactionStageCommit, Err: errors.New("entry already exists")},
+			summary:  "LDAP transaction not committed",
+			contains: "no operation was applied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := transactionErrorDiagnostic(testTransactionOperations, tt.err)
+			if d.Summary() != tt.summary {
+				t.Errorf("summary = %q, want %q", d.Summary(), tt.summary)
+			}
+			if !strings.Contains(d.Detail(), tt.contains) {
+				t.Errorf("detail %q does not contain %q", d.Detail(), tt.contains)
+			}
+		})
+	}
+}
+
+func TestTransactionID(t *testing.T) {
+	reordered := []transactionOperation{testTransactionOperations[1], testTransactionOperations[0]}
+	if transactionID(testTransactionOperations) == transactionID(reordered) {
+		t.Error("expected the order of operations to change the id")
+	}
+	if transactionID(testTransactionOperations) != transactionID(testTransactionOperations) {
+		t.Error("expected the id to be stable")
+	}
+}
+
+func TestLdapClientRunTransaction_Reconnect(t *testing.T) {
+	dials := 0
+	client := &LdapClient{
+		conn: newPipeLdapConn(t),
+		dial: func() (*ldap.Conn, error) {
+			dials++
+			conn := newPipeLdapConn(t)
+			conn.Close()
+			return conn, nil
+		},
+	}
+	client.EnableReconnect(nil)
+	client.conn.Close()
+
+	err := client.RunTransaction([]transactionOperation{{Action: transactionActionDelete, DN: "cn=test,dc=example,dc=com"}})
+	var txnErr *transactionError
+	if !errors.As(err, &txnErr) || txnErr.Stage != transactionStageStart || !ldap.IsErrorWithCode(err, ldap.ErrorNetwork) {
+		t.Fatalf("expected the start on the new connection to fail with a network error, got %v", err)
+	}
+	if dials != 1 {
+		t.Errorf("expected a single reconnect, got %d dials", dials)
+	}
+}
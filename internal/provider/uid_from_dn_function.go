@@ -0,0 +1,86 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &UIDFromDNFunction{}
+
+func NewUIDFromDNFunction() function.Function {
+	return &UIDFromDNFunction{}
+}
+
+// UIDFromDNFunction extracts the uid value from the first RDN of a DN.
+type UIDFromDNFunction struct{}
+
+func (f *UIDFromDNFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "uid_from_dn"
+}
+
+func (f *UIDFromDNFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Extract the uid from a DN",
+		MarkdownDescription: "Returns the unescaped value of the `uid` attribute in the first RDN of `dn`. " +
+			"For example `uid_from_dn(\"uid=jdoe,ou=users,dc=example,dc=com\")` returns `jdoe`. " +
+			"This bridges DN-based membership (`member` of `groupOfNames`) and username-based membership (`memberUid` of `posixGroup`). " +
+			"Errors if the first RDN has no `uid` attribute.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "dn",
+				MarkdownDescription: "Distinguished name whose first RDN contains a `uid` attribute.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *UIDFromDNFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var dn string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &dn))
+	if resp.Error != nil {
+		return
+	}
+
+	uid, err := rdnAttributeValue(dn, "uid")
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, uid))
+}
+
+// rdnAttributeValue returns the unescaped value of attributeType (matched case-insensitively) in the
+// first RDN of dn. Multi-valued RDNs such as "cn=John+uid=jdoe" are searched for the attribute.
+func rdnAttributeValue(dn string, attributeType string) (string, error) {
+	if err := validateDN(dn); err != nil {
+		return "", err
+	}
+
+	parsed, _ := ldap.ParseDN(dn)
+	if value, ok := rdnValue(parsed.RDNs[0], attributeType); ok {
+		return value, nil
+	}
+
+	return "", fmt.Errorf("first RDN of %q has no %s attribute", dn, attributeType)
+}
+
+// rdnValue returns the value of attributeType (matched case-insensitively) in rdn.
+func rdnValue(rdn *ldap.RelativeDN, attributeType string) (string, bool) {
+	for _, atv := range rdn.Attributes {
+		if strings.EqualFold(atv.Type, attributeType) {
+			return atv.Value, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,147 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestUIDFromDNFunction_Run(t *testing.T) {
+	tests := []struct {
+		name        string
+		dn          string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "simple",
+			dn:       "uid=jdoe,ou=users,dc=example,dc=com",
+			expected: "jdoe",
+		},
+		{
+			name:     "case insensitive type",
+			dn:       "UID=jdoe,ou=users,dc=example,dc=com",
+			expected: "jdoe",
+		},
+		{
+			name:     "multi-valued rdn",
+			dn:       "cn=John Doe+uid=jdoe,ou=users,dc=example,dc=com",
+			expected: "jdoe",
+		},
+		{
+			name:     "escaped value",
+			dn:       `uid=doe\,j,ou=users,dc=example,dc=com`,
+			expected: "doe,j",
+		},
+		{
+			name:        "uid only in parent",
+			dn:          "cn=laptop,uid=jdoe,ou=users,dc=example,dc=com",
+			expectError: true,
+		},
+		{
+			name:        "no uid",
+			dn:          "cn=John Doe,ou=users,dc=example,dc=com",
+			expectError: true,
+		},
+		{
+			name:        "invalid dn",
+			dn:          "jdoe",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue(tt.dn),
+				}),
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.StringUnknown()),
+			}
+
+			NewUIDFromDNFunction().Run(context.Background(), req, resp)
+
+			if tt.expectError {
+				if resp.Error == nil {
+					t.Errorf("expected error, got result %s", resp.Result.Value())
+				}
+				return
+			}
+
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+
+			if !resp.Result.Value().Equal(types.StringValue(tt.expected)) {
+				t.Errorf("result = %s, want %q", resp.Result.Value(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestAccLdapEntryResource_PosixGroupMemberUid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckLdapEntryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLdapEntryResourceConfigPosixGroup(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"ldap_entry.posix_group",
+						tfjsonpath.New("attributes").AtMapKey("memberUid"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact("posixuser"),
+						}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccLdapEntryResourceConfigPosixGroup() string {
+	return `
+provider "ldap" {
+  url = "ldap://localhost:3389"
+  bind_dn = "cn=Manager,dc=example,dc=com"
+  bind_password = "secret"
+}
+
+resource "ldap_entry" "posix_user" {
+  dn = "uid=posixuser,ou=users,dc=example,dc=com"
+  attributes = {
+    objectClass = ["inetOrgPerson", "posixAccount"]
+    cn = ["Posix User"]
+    sn = ["User"]
+    uid = ["posixuser"]
+    uidNumber = ["10001"]
+    gidNumber = ["10001"]
+    homeDirectory = ["/home/posixuser"]
+  }
+}
+
+resource "ldap_entry" "posix_group" {
+  dn = "cn=posixgroup,ou=groups,dc=example,dc=com"
+  attributes = {
+    objectClass = ["posixGroup"]
+    cn = ["posixgroup"]
+    gidNumber = ["10001"]
+    memberUid = [provider::ldap::uid_from_dn(ldap_entry.posix_user.dn)]
+  }
+}
+`
+}
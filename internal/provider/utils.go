@@ -2,8 +2,15 @@ package provider
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/go-ldap/ldap/v3"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -14,6 +21,9 @@ import (
 
 type LdapEntry struct {
 	entry *ldap.Entry
+	// warnings describe values that were base64-encoded because they could not be represented,
+	// see MarshalOptions.Lenient.
+	warnings []string
 
 	DN         types.String `tfsdk:"dn"`
 	Attributes types.Map    `tfsdk:"attributes"`
@@ -35,56 +45,463 @@ func ConvertHumanReadableLDAPScope(scope string) (int, error) {
 	return ldapScope, nil
 }
 
-func LdapSearch(conn *ldap.Conn, baseDN string, scope string, filter string, attributes []string) (*ldap.SearchResult, error) {
+// LdapSearcher is the subset of *ldap.Conn used to run searches.
+type LdapSearcher interface {
+	Search(searchRequest *ldap.SearchRequest) (*ldap.SearchResult, error)
+}
+
+// LdapPagingSearcher runs searches whose results are fetched in pages as a whole, e.g. to start them
+// over on a new connection. LdapSearch pages through the results of other searchers itself.
+type LdapPagingSearcher interface {
+	LdapSearcher
+	SearchPages(searchRequest *ldap.SearchRequest, pageSize uint32, onPage func(page int, entries int)) (*ldap.SearchResult, error)
+}
+
+// LdapModifier is the subset of *ldap.Conn used to modify entries.
+type LdapModifier interface {
+	Modify(modifyRequest *ldap.ModifyRequest) error
+}
+
+// defaultMemberBatchSize bounds how many values of a single attribute are sent in one Add or Modify
+// operation unless member_batch_size is set. Larger value lists (e.g. the member attribute of very
+// large groups) are written in batches.
+const defaultMemberBatchSize = 1000
+
+// noAttributes is the special attribute selector (RFC 4511 section 4.5.1.8) that requests an entry
+// without any of its attributes.
+const noAttributes = "1.1"
+
+// LdapSearchOptions holds optional search request settings. The zero value never dereferences aliases.
+type LdapSearchOptions struct {
+	// DerefAliases is one of the ldap.NeverDerefAliases, ldap.DerefInSearching,
+	// ldap.DerefFindingBaseObj or ldap.DerefAlways constants.
+	DerefAliases int
+
+	// Controls are sent with the search request.
+	Controls []ldap.Control
+
+	// TypesOnly requests attribute names without values.
+	TypesOnly bool
+
+	// PageSize, if not zero, fetches the results in pages of at most PageSize entries with the
+	// paged results control (RFC 2696), so that a server size limit does not truncate them.
+	PageSize uint32
+
+	// OnPage, if set, is called after each page of a paged search with the number of the page,
+	// from 1, and the number of entries fetched so far.
+	OnPage func(page int, entries int)
+}
+
+// LdapSearch searches below baseDN, which is sent in normalized form (see normalizeDN), and fetches
+// the remaining values of attributes the server returned in ranges.
+func LdapSearch(conn LdapSearcher, baseDN string, scope string, filter string, attributes []string, opts LdapSearchOptions) (*ldap.SearchResult, error) {
 	searchScope, err := ConvertHumanReadableLDAPScope(scope)
 	if err != nil {
 		return nil, err
 	}
 
 	req := ldap.NewSearchRequest(
-		baseDN,
+		normalizeDN(baseDN),
 		searchScope,
-		ldap.NeverDerefAliases,
+		opts.DerefAliases,
 		0,
 		0,
-		false,
+		opts.TypesOnly,
 		filter,
 		attributes,
-		nil,
+		opts.Controls,
 	)
 
-	return conn.Search(req)
+	var sr *ldap.SearchResult
+	if opts.PageSize > 0 {
+		if pager, ok := conn.(LdapPagingSearcher); ok {
+			sr, err = pager.SearchPages(req, opts.PageSize, opts.OnPage)
+		} else {
+			sr, err = searchPages(conn, req, opts.PageSize, opts.OnPage)
+		}
+	} else {
+		sr, err = conn.Search(req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Without values there is nothing to page through
+	if opts.TypesOnly {
+		return sr, nil
+	}
+
+	if err := fetchRangedAttributes(conn, sr); err != nil {
+		return nil, err
+	}
+
+	return sr, nil
+}
+
+// searchPages runs req with the paged results control (RFC 2696), requesting pages of at most
+// pageSize entries until the server returns no cookie, and returns the entries of all pages. req is
+// left unchanged. onPage, if not nil, is called after each page, see LdapSearchOptions.OnPage.
+func searchPages(conn LdapSearcher, req *ldap.SearchRequest, pageSize uint32, onPage func(page int, entries int)) (*ldap.SearchResult, error) {
+	paging := ldap.NewControlPaging(pageSize)
+	paged := *req
+	paged.Controls = append(append([]ldap.Control(nil), req.Controls...), paging)
+
+	result := &ldap.SearchResult{}
+	for page := 1; ; page++ {
+		sr, err := conn.Search(&paged)
+		if err != nil {
+			return nil, err
+		}
+		result.Entries = append(result.Entries, sr.Entries...)
+		result.Referrals = append(result.Referrals, sr.Referrals...)
+		result.Controls = sr.Controls
+		if onPage != nil {
+			onPage(page, len(result.Entries))
+		}
+
+		response, ok := ldap.FindControl(sr.Controls, ldap.ControlTypePaging).(*ldap.ControlPaging)
+		if !ok || len(response.Cookie) == 0 {
+			return result, nil
+		}
+		paging.SetCookie(response.Cookie)
+	}
+}
+
+// hexValuePattern matches an RDN value given as "#" followed by its hex-encoded BER encoding.
+var hexValuePattern = regexp.MustCompile(`=\s*#`)
+
+// normalizeDN returns dn without spaces around separators and values and with lowercase attribute
+// types, so that servers comparing DNs less leniently than RFC 4514 requires see the same DN for
+// cosmetically different spellings. Values keep their case and are escaped with ldap.EscapeDN, which
+// leaves non-ASCII characters as they are. The empty DN, DNs that cannot be parsed and DNs with
+// hex-encoded values, which parsing decodes, are returned unchanged.
+func normalizeDN(dn string) string {
+	if dn == "" || hexValuePattern.MatchString(dn) {
+		return dn
+	}
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 {
+		return dn
+	}
+
+	rdns := make([]string, len(parsed.RDNs))
+	for i, rdn := range parsed.RDNs {
+		parts := make([]string, len(rdn.Attributes))
+		for j, attribute := range rdn.Attributes {
+			parts[j] = strings.ToLower(attribute.Type) + "=" + ldap.EscapeDN(attribute.Value)
+		}
+		rdns[i] = strings.Join(parts, "+")
+	}
+	return strings.Join(rdns, ",")
+}
+
+// parseRangeOption splits a ranged attribute description such as "member;range=0-1499" into the
+// description without the range option ("member") and its bounds. high is -1 for a final range ("*").
+// ok is false if name has no range option.
+func parseRangeOption(name string) (base string, low int, high int, ok bool) {
+	parts := strings.Split(name, ";")
+	for i, option := range parts[1:] {
+		key, bounds, found := strings.Cut(option, "=")
+		if !found || !strings.EqualFold(key, "range") {
+			continue
+		}
+
+		lowStr, highStr, found := strings.Cut(bounds, "-")
+		if !found {
+			return "", 0, 0, false
+		}
+
+		low, err := strconv.Atoi(lowStr)
+		if err != nil {
+			return "", 0, 0, false
+		}
+
+		high = -1
+		if highStr != "*" {
+			if high, err = strconv.Atoi(highStr); err != nil {
+				return "", 0, 0, false
+			}
+		}
+
+		base = strings.Join(append(parts[:i+1:i+1], parts[i+2:]...), ";")
+		return base, low, high, true
+	}
+
+	return "", 0, 0, false
+}
+
+// fetchRangedAttributes completes attributes returned in ranges (Active Directory returns large
+// multi-valued attributes such as member as "member;range=0-1499"). Remaining ranges are fetched
+// with follow-up base searches and merged, in place, into a single attribute without the range option.
+func fetchRangedAttributes(conn LdapSearcher, sr *ldap.SearchResult) error {
+	for _, entry := range sr.Entries {
+		for _, attr := range entry.Attributes {
+			base, _, high, ok := parseRangeOption(attr.Name)
+			if !ok {
+				continue
+			}
+
+			for high != -1 {
+				next := fmt.Sprintf("%s;range=%d-*", base, high+1)
+				req := ldap.NewSearchRequest(entry.DN, ldap.ScopeBaseObject, ldap.NeverDerefAliases, 0, 0, false, "(objectClass=*)", []string{next}, nil)
+
+				nsr, err := conn.Search(req)
+				if err != nil {
+					return fmt.Errorf("unable to fetch range %s of %s: %w", next, entry.DN, err)
+				}
+
+				var chunk *ldap.EntryAttribute
+				if len(nsr.Entries) > 0 {
+					for _, a := range nsr.Entries[0].Attributes {
+						if b, _, _, ok := parseRangeOption(a.Name); ok && strings.EqualFold(b, base) {
+							chunk = a
+							break
+						}
+					}
+				}
+				if chunk == nil {
+					// The server has no further values to return.
+					break
+				}
+
+				_, _, nextHigh, _ := parseRangeOption(chunk.Name)
+				if nextHigh != -1 && nextHigh <= high {
+					return fmt.Errorf("server returned non-advancing range %s for %s", chunk.Name, entry.DN)
+				}
+
+				attr.Values = append(attr.Values, chunk.Values...)
+				attr.ByteValues = append(attr.ByteValues, chunk.ByteValues...)
+				high = nextHigh
+			}
+
+			attr.Name = base
+		}
+	}
+
+	return nil
+}
+
+// chunkValues splits values into consecutive batches of at most size values.
+func chunkValues(values []string, size int) [][]string {
+	var chunks [][]string
+	for len(values) > size {
+		chunks = append(chunks, values[:size])
+		values = values[size:]
+	}
+	return append(chunks, values)
+}
+
+// addAttributeValues appends each batch of values to the entry at dn with one Modify operation per batch.
+func addAttributeValues(ctx context.Context, conn LdapModifier, dn string, batches []ldap.PartialAttribute, controls []ldap.Control) error {
+	for i, batch := range batches {
+		modifyReq := ldap.NewModifyRequest(dn, controls)
+		modifyReq.Add(batch.Type, batch.Vals)
+		if err := conn.Modify(modifyReq); err != nil {
+			return fmt.Errorf("unable to add %d values to %s: %w", len(batch.Vals), batch.Type, err)
+		}
+		tflog.Debug(ctx, fmt.Sprintf("added batch %d/%d of %d %s values to %s", i+1, len(batches), len(batch.Vals), batch.Type, dn))
+	}
+	return nil
+}
+
+// modifyAttributeValues changes the values of attr on the entry at dn from current to desired by
+// deleting the values no longer wanted and adding the new ones, in batches of at most batchSize
+// values with one Modify operation per batch. Unchanged values are not sent at all.
+func modifyAttributeValues(ctx context.Context, conn LdapModifier, dn string, attr string, current []string, desired []string, batchSize int, controls []ldap.Control) error {
+	removals := valuesNotIn(current, desired)
+	additions := valuesNotIn(desired, current)
+
+	if len(removals) > 0 {
+		batches := chunkValues(removals, batchSize)
+		for i, batch := range batches {
+			modifyReq := ldap.NewModifyRequest(dn, controls)
+			modifyReq.Delete(attr, batch)
+			if err := conn.Modify(modifyReq); err != nil {
+				return fmt.Errorf("unable to remove %d values from %s: %w", len(batch), attr, err)
+			}
+			tflog.Debug(ctx, fmt.Sprintf("removed batch %d/%d of %d %s values from %s", i+1, len(batches), len(batch), attr, dn))
+		}
+	}
+
+	if len(additions) > 0 {
+		var batches []ldap.PartialAttribute
+		for _, batch := range chunkValues(additions, batchSize) {
+			batches = append(batches, ldap.PartialAttribute{Type: attr, Vals: batch})
+		}
+		return addAttributeValues(ctx, conn, dn, batches, controls)
+	}
+
+	return nil
+}
+
+// valuesNotIn returns the values of a that do not appear in b, preserving their order.
+func valuesNotIn(a []string, b []string) []string {
+	exclude := make(map[string]bool, len(b))
+	for _, v := range b {
+		exclude[v] = true
+	}
+
+	var result []string
+	for _, v := range a {
+		if !exclude[v] {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// orderModifyChanges returns changes with deletions first, then additions and replacements, each
+// sorted by attribute type, so that a value is removed before a conflicting one is added (e.g. by
+// a uniqueness constraint) and requests are reproducible.
+func orderModifyChanges(changes []ldap.Change) []ldap.Change {
+	ordered := append([]ldap.Change(nil), changes...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		deleteI := ordered[i].Operation == ldap.DeleteAttribute
+		deleteJ := ordered[j].Operation == ldap.DeleteAttribute
+		if deleteI != deleteJ {
+			return deleteI
+		}
+		return strings.ToLower(ordered[i].Modification.Type) < strings.ToLower(ordered[j].Modification.Type)
+	})
+	return ordered
+}
+
+// objectClassChanges returns the object classes to add and to delete to go from current to
+// desired. Object class names are compared ignoring case, so changing only their case changes
+// nothing on the server.
+func objectClassChanges(current []string, desired []string) (additions []string, removals []string) {
+	currentClasses := make(map[string]bool, len(current))
+	for _, class := range current {
+		currentClasses[strings.ToLower(class)] = true
+	}
+	desiredClasses := make(map[string]bool, len(desired))
+	for _, class := range desired {
+		desiredClasses[strings.ToLower(class)] = true
+	}
+
+	for _, class := range desired {
+		if !currentClasses[strings.ToLower(class)] {
+			additions = append(additions, class)
+		}
+	}
+	for _, class := range current {
+		if !desiredClasses[strings.ToLower(class)] {
+			removals = append(removals, class)
+		}
+	}
+	return additions, removals
+}
+
+// MarshalOptions controls how LDAP search results are converted into Terraform values.
+type MarshalOptions struct {
+	// BinaryAttributes lists attribute types whose values are returned base64-encoded.
+	BinaryAttributes []string
+
+	// SIDAttributes lists attribute types holding binary security identifiers, whose values are
+	// returned in string form (S-1-5-...). They take precedence over BinaryAttributes.
+	SIDAttributes []string
+
+	// FileTimeAttributes lists attribute types holding Windows FILETIME integers, whose values are
+	// returned as RFC 3339 timestamps, see formatFileTime.
+	FileTimeAttributes []string
+
+	// MissingAsNull represents requested attributes absent from the entry as null instead of empty lists.
+	MissingAsNull bool
+
+	// FoldAttributeNames matches requested attributes to returned ones ignoring case, and stores
+	// matching attributes under the requested name.
+	FoldAttributeNames bool
+
+	// SortValues sorts the values of each attribute instead of keeping the server order.
+	SortValues bool
+
+	// Lenient base64-encodes the values of an attribute that cannot be decoded as requested (SIDs,
+	// FILETIMEs) or that are not valid UTF-8, and records a warning on the entry, instead of failing.
+	Lenient bool
+
+	// OnlyRequested drops attributes returned by the server that do not match one of the requested
+	// attribute names, so results hold exactly the requested keys. Special selectors such as "*"
+	// match nothing.
+	OnlyRequested bool
 }
 
 // Marshals LDAP search results into []LdapEntry.
-func MarshalLdapResults(ctx context.Context, sr *ldap.SearchResult, requestedAttributes []string) ([]LdapEntry, error) {
+func MarshalLdapResults(ctx context.Context, sr *ldap.SearchResult, requestedAttributes []string, opts MarshalOptions) ([]LdapEntry, error) {
 	results := make([]LdapEntry, 0, len(sr.Entries))
 
 	for _, entry := range sr.Entries {
 		attributes := make(map[string][]string)
 
+		var warnings []string
 		for _, attr := range entry.Attributes {
-			attributes[attr.Name] = attr.Values
+			values, err := marshalAttributeValues(attr, opts)
+			if err != nil {
+				if !opts.Lenient {
+					return nil, fmt.Errorf("unable to decode %s of %s: %w", attr.Name, entry.DN, err)
+				}
+				warnings = append(warnings, fmt.Sprintf("%s of %s: %s; its values are base64-encoded instead", attr.Name, entry.DN, err))
+				values = base64Values(attr.ByteValues)
+			}
+			attributes[attr.Name] = values
+		}
+
+		if opts.SortValues {
+			for name, values := range attributes {
+				sorted := append([]string(nil), values...)
+				sort.Strings(sorted)
+				attributes[name] = sorted
+			}
 		}
 
 		// Compare attributes returned by search against those requested.
-		// This is a provider logic thing. For user experience, we always represent
-		// non-existent attributes as empty lists.
+		// This is a provider logic thing. For user experience, we represent
+		// non-existent attributes as empty lists, or as null lists (a nil slice)
+		// when MissingAsNull is set.
 		for _, ra := range requestedAttributes {
-			if _, exists := attributes[ra]; !exists {
+			if isSpecialAttributeSelector(ra) {
+				continue
+			}
+			if name, exists := findAttribute(attributes, ra, opts.FoldAttributeNames); exists {
+				if name != ra {
+					attributes[ra] = attributes[name]
+					delete(attributes, name)
+				}
+			} else {
 				tflog.Trace(ctx, fmt.Sprintf("Requested attribute '%s' not found in LDAP response", ra))
-				attributes[ra] = []string{}
+				if opts.MissingAsNull {
+					attributes[ra] = nil
+				} else {
+					attributes[ra] = []string{}
+				}
+			}
+		}
+
+		if opts.OnlyRequested {
+			requested := make(map[string]bool, len(requestedAttributes))
+			var wildcards []string
+			for _, ra := range requestedAttributes {
+				if isOptionWildcard(ra) {
+					wildcards = append(wildcards, ra)
+				}
+				requested[ra] = true
+			}
+			for name := range attributes {
+				if !requested[name] && !matchesOptionWildcard(name, wildcards) {
+					tflog.Trace(ctx, fmt.Sprintf("Dropping attribute '%s' that was not requested", name))
+					delete(attributes, name)
+				}
 			}
 		}
 
 		// Convert attributes to types.Map
 		attributesMap, diags := types.MapValueFrom(ctx, types.ListType{ElemType: types.StringType}, attributes)
 		if diags.HasError() {
-			return nil, errors.New(diags[len(diags)].Detail())
+			return nil, errors.New(diags[len(diags)-1].Detail())
 		}
 
 		result := LdapEntry{
 			entry:      entry,
+			warnings:   warnings,
 			DN:         types.StringValue(entry.DN),
 			Attributes: attributesMap,
 		}
@@ -95,19 +512,209 @@ func MarshalLdapResults(ctx context.Context, sr *ldap.SearchResult, requestedAtt
 	return results, nil
 }
 
-// GetLdapConnection extracts the LDAP connection from provider data.
+// marshalAttributeValues returns the values of attr as configured by opts: decoded SIDs or
+// FILETIMEs, base64 for binary attributes, and the values as they are otherwise. It fails for
+// values that cannot be decoded, or that are not valid UTF-8 and so cannot be Terraform strings.
+func marshalAttributeValues(attr *ldap.EntryAttribute, opts MarshalOptions) ([]string, error) {
+	switch {
+	case isBinaryAttribute(attr.Name, opts.SIDAttributes):
+		values := make([]string, len(attr.ByteValues))
+		for i, v := range attr.ByteValues {
+			sid, err := formatSID(v)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = sid
+		}
+		return values, nil
+	case isBinaryAttribute(attr.Name, opts.FileTimeAttributes):
+		values := make([]string, len(attr.Values))
+		for i, v := range attr.Values {
+			timestamp, err := formatFileTime(v)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = timestamp
+		}
+		return values, nil
+	case isBinaryAttribute(attr.Name, opts.BinaryAttributes):
+		return base64Values(attr.ByteValues), nil
+	}
+
+	for _, v := range attr.Values {
+		if !utf8.ValidString(v) {
+			return nil, errors.New("value is not valid UTF-8")
+		}
+	}
+	return attr.Values, nil
+}
+
+// base64Values returns values encoded as standard base64.
+func base64Values(values [][]byte) []string {
+	encoded := make([]string, len(values))
+	for i, v := range values {
+		encoded[i] = base64.StdEncoding.EncodeToString(v)
+	}
+	return encoded
+}
+
+// marshalWarnings reports the values of entries that were base64-encoded because they could not be
+// represented, see MarshalOptions.Lenient.
+func marshalWarnings(entries []LdapEntry) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var warnings []string
+	for _, entry := range entries {
+		warnings = append(warnings, entry.warnings...)
+	}
+	if len(warnings) > 0 {
+		diags.AddWarning(
+			"Unrepresentable LDAP attribute values",
+			"Some attribute values could not be decoded and are base64-encoded instead. List binary attributes in binary_attributes where available, or set strict_read in the provider configuration to fail instead.\n\n"+strings.Join(warnings, "\n"),
+		)
+	}
+	return diags
+}
+
+// AttributeNames returns the sorted union of the attribute names of all entries in sr. Names
+// differing only in case are reported once, and range options are stripped.
+func AttributeNames(sr *ldap.SearchResult) []string {
+	seen := make(map[string]bool)
+	var names []string
+
+	for _, entry := range sr.Entries {
+		for _, attr := range entry.Attributes {
+			name := attr.Name
+			if base, _, _, ok := parseRangeOption(name); ok {
+				name = base
+			}
+			if seen[strings.ToLower(name)] {
+				continue
+			}
+			seen[strings.ToLower(name)] = true
+			names = append(names, name)
+		}
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		return strings.ToLower(names[i]) < strings.ToLower(names[j])
+	})
+	return names
+}
+
+// isSpecialAttributeSelector reports whether a requested attribute is a selector rather than an
+// attribute description: "1.1" (no attributes), "*" (all user attributes), "+" (all operational
+// attributes, RFC 3673), "@objectClass" (all attributes of an object class, RFC 4529) or
+// "description;*" (every option variant of an attribute, see isOptionWildcard).
+func isSpecialAttributeSelector(name string) bool {
+	return name == noAttributes || name == "*" || name == "+" || strings.HasPrefix(name, "@") || isOptionWildcard(name)
+}
+
+// isOptionWildcard reports whether a requested attribute asks for an attribute with any options,
+// such as "description;*" for all language variants. Servers return each variant under its own
+// description (e.g. "description;lang-en"), never under the wildcard itself.
+func isOptionWildcard(name string) bool {
+	return strings.HasSuffix(name, ";*")
+}
+
+// matchesOptionWildcard reports whether the attribute description name has the attribute type
+// (ignoring case) selected by one of wildcards.
+func matchesOptionWildcard(name string, wildcards []string) bool {
+	for _, w := range wildcards {
+		if strings.EqualFold(attributeType(name), attributeType(w)) {
+			return true
+		}
+	}
+	return false
+}
+
+// findAttribute returns the key under which attributes holds name. With foldCase, names are
+// matched ignoring case, so requesting "entrydn" finds the "entryDN" returned by the server.
+func findAttribute(attributes map[string][]string, name string, foldCase bool) (string, bool) {
+	if _, exists := attributes[name]; exists {
+		return name, true
+	}
+	if foldCase {
+		for n := range attributes {
+			if strings.EqualFold(n, name) {
+				return n, true
+			}
+		}
+	}
+	return "", false
+}
+
+// alignAttributeNames renames the keys of attributes that match a key of reference ignoring case,
+// but not exactly, to the spelling used in reference.
+func alignAttributeNames(attributes map[string][]string, reference map[string][]string) {
+	for name, values := range attributes {
+		if _, exists := reference[name]; exists {
+			continue
+		}
+		if refName, exists := findAttribute(reference, name, true); exists {
+			if _, taken := attributes[refName]; !taken {
+				attributes[refName] = values
+				delete(attributes, name)
+			}
+		}
+	}
+}
+
+// attributeType returns the attribute type of an attribute description, stripping any
+// options. For example "userCertificate;binary" becomes "userCertificate".
+func attributeType(name string) string {
+	if i := strings.IndexByte(name, ';'); i >= 0 {
+		return name[:i]
+	}
+	return name
+}
+
+// isBinaryAttribute reports whether the attribute description name has the same attribute type
+// (ignoring options and case) as one of binaryAttributes.
+func isBinaryAttribute(name string, binaryAttributes []string) bool {
+	for _, ba := range binaryAttributes {
+		if strings.EqualFold(attributeType(name), attributeType(ba)) {
+			return true
+		}
+	}
+	return false
+}
+
+// DecodeBinaryAttributes base64-decodes, in place, the values of every attribute in attributes
+// that matches one of binaryAttributes. Returns an error naming the attribute on invalid input.
+func DecodeBinaryAttributes(attributes map[string][]string, binaryAttributes []string) error {
+	for name, values := range attributes {
+		if !isBinaryAttribute(name, binaryAttributes) {
+			continue
+		}
+
+		decoded := make([]string, len(values))
+		for i, v := range values {
+			b, err := base64.StdEncoding.DecodeString(v)
+			if err != nil {
+				return fmt.Errorf("value %d of binary attribute %s is not valid base64: %w", i, name, err)
+			}
+			decoded[i] = string(b)
+		}
+		attributes[name] = decoded
+	}
+
+	return nil
+}
+
+// GetLdapConnection extracts the LDAP client from provider data.
 // Returns nil if providerData is nil (provider not configured) or adds an error diagnostic if the type is unexpected.
-func GetLdapConnection(providerData any, diagnostics *diag.Diagnostics, resourceType string) *ldap.Conn {
+func GetLdapConnection(providerData any, diagnostics *diag.Diagnostics, resourceType string) *LdapClient {
 	// Prevent panic if the provider has not been configured.
 	if providerData == nil {
 		return nil
 	}
 
-	conn, ok := providerData.(*ldap.Conn)
+	conn, ok := providerData.(*LdapClient)
 	if !ok {
 		diagnostics.AddError(
 			fmt.Sprintf("Unexpected %s Configure Type", resourceType),
-			fmt.Sprintf("Expected *ldap.Conn, got: %T. Please report this issue to the provider developers.", providerData),
+			fmt.Sprintf("Expected *LdapClient, got: %T. Please report this issue to the provider developers.", providerData),
 		)
 		return nil
 	}
@@ -116,26 +723,39 @@ func GetLdapConnection(providerData any, diagnostics *diag.Diagnostics, resource
 }
 
 // ProcessUnicodePwd handles special encoding for Active Directory's unicodePwd attribute.
-// If the attributes map contains a unicodePwd key, it encodes the password as UTF-16LE
-// with double quotes as required by Active Directory. Returns diagnostics on encoding errors.
+// Any key naming unicodePwd, ignoring case and options, has its password encoded as UTF-16LE
+// with double quotes as required by Active Directory. Other attributes, such as userPassword
+// set alongside it, are left as they are. Returns diagnostics on encoding errors.
 func ProcessUnicodePwd(attributes map[string][]string) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	if value, ok := attributes["unicodePwd"]; ok && len(value) > 0 {
+	for name, value := range attributes {
+		if !strings.EqualFold(attributeType(name), "unicodePwd") || len(value) == 0 {
+			continue
+		}
+
 		encoded, err := encodeUnicodePwd(value[0])
 		if err != nil {
 			diags.AddError(
 				"Error encoding unicodePwd",
-				fmt.Sprintf("Unable to encode unicodePwd value: %s", err),
+				fmt.Sprintf("Unable to encode %s value: %s", name, err),
 			)
 			return diags
 		}
-		attributes["unicodePwd"] = []string{encoded}
+		attributes[name] = []string{encoded}
 	}
 
 	return diags
 }
 
+// UnlockAccount unlocks an Active Directory account locked out by failed logons by setting its
+// lockoutTime to 0, the only value Active Directory accepts for it.
+func UnlockAccount(conn LdapModifier, dn string, controls []ldap.Control) error {
+	modifyReq := ldap.NewModifyRequest(dn, controls)
+	modifyReq.Replace("lockoutTime", []string{"0"})
+	return conn.Modify(modifyReq)
+}
+
 // encodeUnicodePwd encodes a password for Active Directory's unicodePwd attribute.
 // return value is double quoted and encoded as UTF-16LE.
 // See: https://ldapwiki.com/wiki/Wiki.jsp?page=UnicodePwd
@@ -151,8 +771,8 @@ func encodeUnicodePwd(password string) (string, error) {
 // AttributeExistsInLDAP checks if an attribute exists on an LDAP entry.
 // Returns true if the attribute exists (even if empty), false if it doesn't exist.
 // Returns an error if the LDAP query fails.
-func AttributeExistsInLDAP(conn *ldap.Conn, dn string, attributeName string) (bool, []string, error) {
-	sr, err := LdapSearch(conn, dn, "base", "(objectClass=*)", []string{attributeName})
+func AttributeExistsInLDAP(conn LdapSearcher, dn string, attributeName string) (bool, []string, error) {
+	sr, err := LdapSearch(conn, dn, "base", "(objectClass=*)", []string{attributeName}, LdapSearchOptions{})
 	if err != nil {
 		return false, nil, err
 	}
@@ -163,10 +783,103 @@ func AttributeExistsInLDAP(conn *ldap.Conn, dn string, attributeName string) (bo
 
 	entry := sr.Entries[0]
 	for _, attr := range entry.Attributes {
-		if attr.Name == attributeName {
+		if strings.EqualFold(attr.Name, attributeName) {
 			return true, attr.Values, nil
 		}
 	}
 
 	return false, nil, nil
 }
+
+// EntryExistsInLDAP reports whether the entry dn exists, using a base search that requests no attributes.
+func EntryExistsInLDAP(conn LdapSearcher, dn string, opts LdapSearchOptions) (bool, error) {
+	sr, err := LdapSearch(conn, dn, "base", "(objectClass=*)", []string{noAttributes}, opts)
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return len(sr.Entries) > 0, nil
+}
+
+// WaitForEntry searches for the entry dn until it exists, sleeping interval between searches, for at
+// most timeout. A missing entry is expected on replicas the add has not reached yet. Returns false
+// without error if the entry did not appear in time.
+func WaitForEntry(ctx context.Context, conn LdapSearcher, dn string, opts LdapSearchOptions, timeout time.Duration, interval time.Duration) (bool, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		exists, err := EntryExistsInLDAP(conn, dn, opts)
+		if err != nil || exists {
+			return exists, err
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			return false, nil
+		}
+
+		tflog.Debug(ctx, fmt.Sprintf("LDAP entry %s not yet visible, retrying in %s", dn, interval))
+
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// WaitForAttributeValues reads an attribute from an entry until it holds the expected values (compared as sets,
+// with equal). The attribute is read once plus up to retries more times, sleeping interval between reads. A
+// missing entry is treated as not yet consistent. Returns false without error if the values never matched.
+func WaitForAttributeValues(ctx context.Context, conn LdapSearcher, dn string, attributeName string, expected []string, equal func(a, b string) bool, retries int, interval time.Duration) (bool, error) {
+	for attempt := 0; ; attempt++ {
+		sr, err := LdapSearch(conn, dn, "base", "(objectClass=*)", []string{attributeName}, LdapSearchOptions{})
+		if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return false, err
+		}
+
+		if err == nil && len(sr.Entries) > 0 {
+			values := sr.Entries[0].GetEqualFoldAttributeValues(attributeName)
+			if valuesEqualAsSets(values, expected, equal) {
+				return true, nil
+			}
+		}
+
+		if attempt >= retries {
+			return false, nil
+		}
+
+		tflog.Debug(ctx, fmt.Sprintf("Attribute '%s' on %s not yet consistent, retrying in %s (%d/%d)", attributeName, dn, interval, attempt+1, retries))
+
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// valuesEqualAsSets reports whether a and b hold the same values in any order, each value of a
+// matching a distinct value of b with equal.
+func valuesEqualAsSets(a []string, b []string, equal func(a, b string) bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	matched := make([]bool, len(b))
+	for _, value := range a {
+		found := false
+		for i, candidate := range b {
+			if !matched[i] && equal(value, candidate) {
+				matched[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
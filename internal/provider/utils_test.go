@@ -0,0 +1,1138 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestIsBinaryAttribute(t *testing.T) {
+	tests := []struct {
+		name             string
+		attribute        string
+		binaryAttributes []string
+		expected         bool
+	}{
+		{
+			name:             "exact match",
+			attribute:        "jpegPhoto",
+			binaryAttributes: []string{"jpegPhoto"},
+			expected:         true,
+		},
+		{
+			name:             "case insensitive",
+			attribute:        "JPEGPHOTO",
+			binaryAttributes: []string{"jpegPhoto"},
+			expected:         true,
+		},
+		{
+			name:             "option on attribute",
+			attribute:        "userCertificate;binary",
+			binaryAttributes: []string{"userCertificate"},
+			expected:         true,
+		},
+		{
+			name:             "option on list entry",
+			attribute:        "userCertificate;binary",
+			binaryAttributes: []string{"userCertificate;binary"},
+			expected:         true,
+		},
+		{
+			name:             "prefix is not a match",
+			attribute:        "userCertificateRevocation",
+			binaryAttributes: []string{"userCertificate"},
+			expected:         false,
+		},
+		{
+			name:             "empty list",
+			attribute:        "jpegPhoto",
+			binaryAttributes: nil,
+			expected:         false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := isBinaryAttribute(tt.attribute, tt.binaryAttributes)
+			if result != tt.expected {
+				t.Errorf("isBinaryAttribute(%q, %v) = %v, want %v", tt.attribute, tt.binaryAttributes, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecodeBinaryAttributes(t *testing.T) {
+	raw := string([]byte{0x30, 0x82, 0x00, 0xff, 0x00})
+
+	attributes := map[string][]string{
+		"userCertificate;binary": {base64.StdEncoding.EncodeToString([]byte(raw))},
+		"cn":                     {"dGVzdA=="},
+	}
+
+	if err := DecodeBinaryAttributes(attributes, []string{"userCertificate"}); err != nil {
+		t.Fatalf("DecodeBinaryAttributes unexpected error: %v", err)
+	}
+
+	if got := attributes["userCertificate;binary"][0]; got != raw {
+		t.Errorf("userCertificate;binary = %x, want %x", got, raw)
+	}
+
+	// Attributes that are not binary must be left untouched
+	if got := attributes["cn"][0]; got != "dGVzdA==" {
+		t.Errorf("cn = %q, want it unchanged", got)
+	}
+
+	invalid := map[string][]string{"jpegPhoto": {"not base64!"}}
+	if err := DecodeBinaryAttributes(invalid, []string{"jpegPhoto"}); err == nil {
+		t.Errorf("DecodeBinaryAttributes expected error for invalid base64, got nil")
+	}
+}
+
+func TestMarshalLdapResults_BinaryAttributes(t *testing.T) {
+	der := []byte{0x30, 0x82, 0x01, 0x7d, 0x00, 0xfe}
+
+	sr := &ldap.SearchResult{
+		Entries: []*ldap.Entry{
+			{
+				DN: "uid=testuser,dc=example,dc=com",
+				Attributes: []*ldap.EntryAttribute{
+					{Name: "userCertificate;binary", Values: []string{string(der)}, ByteValues: [][]byte{der}},
+					{Name: "cn", Values: []string{"Test User"}, ByteValues: [][]byte{[]byte("Test User")}},
+				},
+			},
+		},
+	}
+
+	results, err := MarshalLdapResults(context.Background(), sr, []string{"userCertificate;binary", "cn"}, MarshalOptions{
+		BinaryAttributes: []string{"userCertificate"},
+	})
+	if err != nil {
+		t.Fatalf("MarshalLdapResults unexpected error: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+
+	var attributes map[string][]string
+	if diags := results[0].Attributes.ElementsAs(context.Background(), &attributes, false); diags.HasError() {
+		t.Fatalf("unable to read attributes: %v", diags)
+	}
+
+	if got, want := attributes["userCertificate;binary"], base64.StdEncoding.EncodeToString(der); len(got) != 1 || got[0] != want {
+		t.Errorf("userCertificate;binary = %v, want [%s]", got, want)
+	}
+
+	if got := attributes["cn"]; len(got) != 1 || got[0] != "Test User" {
+		t.Errorf("cn = %v, want [Test User]", got)
+	}
+
+	if !results[0].DN.Equal(types.StringValue("uid=testuser,dc=example,dc=com")) {
+		t.Errorf("DN = %s, want uid=testuser,dc=example,dc=com", results[0].DN)
+	}
+}
+
+func TestParseRangeOption(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantBase string
+		wantLow  int
+		wantHigh int
+		wantOk   bool
+	}{
+		{name: "bounded range", input: "member;range=0-1499", wantBase: "member", wantLow: 0, wantHigh: 1499, wantOk: true},
+		{name: "final range", input: "member;range=1500-*", wantBase: "member", wantLow: 1500, wantHigh: -1, wantOk: true},
+		{name: "case insensitive option", input: "member;Range=0-9", wantBase: "member", wantLow: 0, wantHigh: 9, wantOk: true},
+		{name: "other options kept", input: "userCertificate;binary;range=0-9", wantBase: "userCertificate;binary", wantLow: 0, wantHigh: 9, wantOk: true},
+		{name: "no options", input: "member", wantOk: false},
+		{name: "unrelated option", input: "userCertificate;binary", wantOk: false},
+		{name: "malformed bounds", input: "member;range=abc", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, low, high, ok := parseRangeOption(tt.input)
+			if ok != tt.wantOk {
+				t.Fatalf("parseRangeOption(%q) ok = %v, want %v", tt.input, ok, tt.wantOk)
+			}
+			if !ok {
+				return
+			}
+			if base != tt.wantBase || low != tt.wantLow || high != tt.wantHigh {
+				t.Errorf("parseRangeOption(%q) = (%q, %d, %d), want (%q, %d, %d)", tt.input, base, low, high, tt.wantBase, tt.wantLow, tt.wantHigh)
+			}
+		})
+	}
+}
+
+func TestChunkValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		size     int
+		expected []int
+	}{
+		{name: "below limit", values: make([]string, 3), size: 5, expected: []int{3}},
+		{name: "exact limit", values: make([]string, 5), size: 5, expected: []int{5}},
+		{name: "over limit", values: make([]string, 12), size: 5, expected: []int{5, 5, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := chunkValues(tt.values, tt.size)
+			if len(chunks) != len(tt.expected) {
+				t.Fatalf("expected %d chunks, got %d", len(tt.expected), len(chunks))
+			}
+			for i, chunk := range chunks {
+				if len(chunk) != tt.expected[i] {
+					t.Errorf("chunk %d: expected %d values, got %d", i, tt.expected[i], len(chunk))
+				}
+			}
+		})
+	}
+}
+
+// rangedSearcher simulates a server that returns a multi-valued attribute in ranges of pageSize values.
+type rangedSearcher struct {
+	dn       string
+	values   []string
+	pageSize int
+	searches int
+}
+
+func (s *rangedSearcher) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	s.searches++
+
+	low := 0
+	if len(req.Attributes) > 0 {
+		if _, l, _, ok := parseRangeOption(req.Attributes[0]); ok {
+			low = l
+		}
+	}
+
+	high := low + s.pageSize - 1
+	name := fmt.Sprintf("member;range=%d-%d", low, high)
+	if high >= len(s.values)-1 {
+		high = len(s.values) - 1
+		name = fmt.Sprintf("member;range=%d-*", low)
+	}
+
+	entry := ldap.NewEntry(s.dn, map[string][]string{
+		name: s.values[low : high+1],
+	})
+	return &ldap.SearchResult{Entries: []*ldap.Entry{entry}}, nil
+}
+
+func TestLdapSearch_RangedAttributes(t *testing.T) {
+	members := make([]string, 3500)
+	for i := range members {
+		members[i] = "uid=user" + strconv.Itoa(i) + ",ou=users,dc=example,dc=com"
+	}
+
+	searcher := &rangedSearcher{
+		dn:       "cn=big,ou=groups,dc=example,dc=com",
+		values:   members,
+		pageSize: 1500,
+	}
+
+	sr, err := LdapSearch(searcher, searcher.dn, "base", "(objectClass=*)", []string{"member"}, LdapSearchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if searcher.searches != 3 {
+		t.Errorf("expected 3 searches (0-1499, 1500-2999, 3000-*), got %d", searcher.searches)
+	}
+
+	attrs := sr.Entries[0].Attributes
+	if len(attrs) != 1 || attrs[0].Name != "member" {
+		t.Fatalf("expected a single attribute named member, got %v", attrs)
+	}
+
+	if !stringSlicesEqual(attrs[0].Values, members) {
+		t.Errorf("expected %d merged values, got %d", len(members), len(attrs[0].Values))
+	}
+	if len(attrs[0].ByteValues) != len(members) {
+		t.Errorf("expected %d merged byte values, got %d", len(members), len(attrs[0].ByteValues))
+	}
+}
+
+func TestMarshalLdapResults_NoAttributes(t *testing.T) {
+	sr := &ldap.SearchResult{
+		Entries: []*ldap.Entry{
+			{DN: "uid=testuser,dc=example,dc=com"},
+		},
+	}
+
+	results, err := MarshalLdapResults(context.Background(), sr, []string{noAttributes}, MarshalOptions{})
+	if err != nil {
+		t.Fatalf("MarshalLdapResults unexpected error: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("expected the entry to be returned without attributes, got %d results", len(results))
+	}
+
+	if n := len(results[0].Attributes.Elements()); n != 0 {
+		t.Errorf("expected no attributes, got %d: %s", n, results[0].Attributes)
+	}
+}
+
+// recordingSearcher captures search requests and returns a single empty entry.
+type recordingSearcher struct {
+	requests []*ldap.SearchRequest
+}
+
+func (s *recordingSearcher) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	s.requests = append(s.requests, req)
+	return &ldap.SearchResult{Entries: []*ldap.Entry{{DN: req.BaseDN}}}, nil
+}
+
+func TestLdapSearch_DerefAliases(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     LdapSearchOptions
+		expected int
+	}{
+		{name: "default never dereferences", opts: LdapSearchOptions{}, expected: ldap.NeverDerefAliases},
+		{name: "finding base object", opts: LdapSearchOptions{DerefAliases: ldap.DerefFindingBaseObj}, expected: ldap.DerefFindingBaseObj},
+		{name: "always", opts: LdapSearchOptions{DerefAliases: ldap.DerefAlways}, expected: ldap.DerefAlways},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searcher := &recordingSearcher{}
+			if _, err := LdapSearch(searcher, "uid=alias,dc=example,dc=com", "base", "(objectClass=*)", nil, tt.opts); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if got := searcher.requests[0].DerefAliases; got != tt.expected {
+				t.Errorf("DerefAliases = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeDN(t *testing.T) {
+	tests := []struct {
+		dn       string
+		expected string
+	}{
+		{dn: "ou=users,dc=example,dc=com", expected: "ou=users,dc=example,dc=com"},
+		{dn: " ou = users , dc=example,  dc=com ", expected: "ou=users,dc=example,dc=com"},
+		{dn: "OU=Users,DC=Example,DC=com", expected: "ou=Users,dc=Example,dc=com"},
+		{dn: "cn=John+SN=Doe,dc=example,dc=com", expected: "cn=John+sn=Doe,dc=example,dc=com"},
+		{dn: `cn=Doe\, John,dc=example,dc=com`, expected: `cn=Doe\, John,dc=example,dc=com`},
+		{dn: `cn=\ padded\ ,dc=example,dc=com`, expected: `cn=\ padded\ ,dc=example,dc=com`},
+		{dn: `cn=Doe\2C John,dc=example,dc=com`, expected: `cn=Doe\, John,dc=example,dc=com`},
+		{dn: "cn=Jürgen, dc=example,dc=com", expected: "cn=Jürgen,dc=example,dc=com"},
+		{dn: "cn=#04024869,dc=example,dc=com", expected: "cn=#04024869,dc=example,dc=com"},
+		{dn: "", expected: ""},
+		{dn: "not a dn", expected: "not a dn"},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeDN(tt.dn); got != tt.expected {
+			t.Errorf("normalizeDN(%q) = %q, want %q", tt.dn, got, tt.expected)
+		}
+	}
+}
+
+func TestLdapSearch_NormalizesBaseDN(t *testing.T) {
+	searcher := &recordingSearcher{}
+	if _, err := LdapSearch(searcher, "OU=Users , dc=example, dc=com ", "sub", "(objectClass=*)", nil, LdapSearchOptions{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got := searcher.requests[0].BaseDN; got != "ou=Users,dc=example,dc=com" {
+		t.Errorf("BaseDN = %q, want the normalized DN", got)
+	}
+}
+
+func TestMarshalLdapResults_MissingAttributes(t *testing.T) {
+	tests := []struct {
+		name          string
+		missingAsNull bool
+		expectNull    bool
+	}{
+		{name: "empty list by default", missingAsNull: false, expectNull: false},
+		{name: "null when missing_as_null", missingAsNull: true, expectNull: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := &ldap.SearchResult{
+				Entries: []*ldap.Entry{
+					ldap.NewEntry("uid=testuser,dc=example,dc=com", map[string][]string{"cn": {"Test User"}}),
+				},
+			}
+
+			results, err := MarshalLdapResults(context.Background(), sr, []string{"cn", "mail"}, MarshalOptions{MissingAsNull: tt.missingAsNull})
+			if err != nil {
+				t.Fatalf("MarshalLdapResults unexpected error: %v", err)
+			}
+
+			mail, ok := results[0].Attributes.Elements()["mail"].(types.List)
+			if !ok {
+				t.Fatalf("expected mail to be present as a list, got %v", results[0].Attributes)
+			}
+
+			if mail.IsNull() != tt.expectNull {
+				t.Errorf("mail null = %v, want %v", mail.IsNull(), tt.expectNull)
+			}
+			if !tt.expectNull && len(mail.Elements()) != 0 {
+				t.Errorf("expected mail to be an empty list, got %s", mail)
+			}
+
+			cn := results[0].Attributes.Elements()["cn"].(types.List)
+			if cn.IsNull() || len(cn.Elements()) != 1 {
+				t.Errorf("expected cn to hold one value, got %s", cn)
+			}
+		})
+	}
+}
+
+func TestMarshalLdapResults_OperationalAttributes(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested []string
+		expected  string
+	}{
+		{name: "entryDN", requested: []string{"entryDN"}, expected: "entryDN"},
+		{name: "different case", requested: []string{"entrydn"}, expected: "entrydn"},
+		{name: "all operational", requested: []string{"+"}, expected: "entryDN"},
+		{name: "all user and operational", requested: []string{"*", "+"}, expected: "entryDN"},
+		{name: "object class selector", requested: []string{"@inetOrgPerson", "entryDN"}, expected: "entryDN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := &ldap.SearchResult{
+				Entries: []*ldap.Entry{
+					ldap.NewEntry("uid=testuser, dc=Example,dc=com", map[string][]string{
+						"entryDN": {"uid=testuser,dc=example,dc=com"},
+					}),
+				},
+			}
+
+			results, err := MarshalLdapResults(context.Background(), sr, tt.requested, MarshalOptions{FoldAttributeNames: true})
+			if err != nil {
+				t.Fatalf("MarshalLdapResults unexpected error: %v", err)
+			}
+
+			attributes := results[0].Attributes.Elements()
+			if len(attributes) != 1 {
+				t.Fatalf("expected only entryDN, got %v", results[0].Attributes)
+			}
+
+			entryDN, ok := attributes[tt.expected].(types.List)
+			if !ok || len(entryDN.Elements()) != 1 || entryDN.Elements()[0].(types.String).ValueString() != "uid=testuser,dc=example,dc=com" {
+				t.Errorf("expected %s to hold the normalized DN, got %v", tt.expected, results[0].Attributes)
+			}
+		})
+	}
+}
+
+// staticSearcher returns the same result or error for every search.
+type staticSearcher struct {
+	result *ldap.SearchResult
+	err    error
+}
+
+func (s *staticSearcher) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	return s.result, s.err
+}
+
+func TestEntryExistsInLDAP(t *testing.T) {
+	tests := []struct {
+		name        string
+		searcher    *staticSearcher
+		expected    bool
+		expectError bool
+	}{
+		{
+			name:     "exists",
+			searcher: &staticSearcher{result: &ldap.SearchResult{Entries: []*ldap.Entry{{DN: "cn=test,dc=example,dc=com"}}}},
+			expected: true,
+		},
+		{
+			name:     "no such object",
+			searcher: &staticSearcher{err: ldap.NewError(ldap.LDAPResultNoSuchObject, errors.New("no such object"))},
+			expected: false,
+		},
+		{
+			name:     "no entries",
+			searcher: &staticSearcher{result: &ldap.SearchResult{}},
+			expected: false,
+		},
+		{
+			name:        "other error",
+			searcher:    &staticSearcher{err: ldap.NewError(ldap.LDAPResultReferral, errors.New("referral"))},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exists, err := EntryExistsInLDAP(tt.searcher, "cn=test,dc=example,dc=com", LdapSearchOptions{})
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if exists != tt.expected {
+				t.Errorf("EntryExistsInLDAP() = %v, want %v", exists, tt.expected)
+			}
+		})
+	}
+}
+
+// laggingSearcher simulates a replica the add reaches late: the entry is missing for the first
+// missingSearches searches.
+type laggingSearcher struct {
+	missingSearches int
+	searches        int
+}
+
+func (s *laggingSearcher) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	s.searches++
+	if s.searches <= s.missingSearches {
+		return nil, ldap.NewError(ldap.LDAPResultNoSuchObject, errors.New("no such object"))
+	}
+	return &ldap.SearchResult{Entries: []*ldap.Entry{{DN: req.BaseDN}}}, nil
+}
+
+func TestWaitForEntry(t *testing.T) {
+	tests := []struct {
+		name             string
+		missingSearches  int
+		timeout          time.Duration
+		expected         bool
+		expectedSearches int
+	}{
+		{name: "visible at once", timeout: time.Second, expected: true, expectedSearches: 1},
+		{name: "replica lag", missingSearches: 2, timeout: time.Second, expected: true, expectedSearches: 3},
+		{name: "lag longer than timeout", missingSearches: 100, timeout: 5 * time.Millisecond, expected: false, expectedSearches: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searcher := &laggingSearcher{missingSearches: tt.missingSearches}
+			visible, err := WaitForEntry(context.Background(), searcher, "cn=test,dc=example,dc=com", LdapSearchOptions{}, tt.timeout, 10*time.Millisecond)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if visible != tt.expected {
+				t.Errorf("WaitForEntry() = %t, want %t", visible, tt.expected)
+			}
+			if searcher.searches != tt.expectedSearches {
+				t.Errorf("expected %d searches, got %d", tt.expectedSearches, searcher.searches)
+			}
+		})
+	}
+}
+
+func TestWaitForEntry_Error(t *testing.T) {
+	searcher := &staticSearcher{err: ldap.NewError(ldap.LDAPResultInsufficientAccessRights, errors.New("insufficient access"))}
+	if _, err := WaitForEntry(context.Background(), searcher, "cn=test,dc=example,dc=com", LdapSearchOptions{}, time.Second, 10*time.Millisecond); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestWaitForAttributeValues(t *testing.T) {
+	exact := func(a, b string) bool { return a == b }
+	searcher := &staticSearcher{result: &ldap.SearchResult{Entries: []*ldap.Entry{
+		ldap.NewEntry("cn=developers,dc=example,dc=com", map[string][]string{
+			"member": {"UID=jdoe,OU=users,DC=example,DC=com", "cn=Manager,dc=example,dc=com"},
+		}),
+	}}}
+
+	tests := []struct {
+		name     string
+		expected []string
+		equal    func(a, b string) bool
+		want     bool
+	}{
+		{"DNs compared as DNs", []string{"cn=manager, dc=example, dc=com", "uid=jdoe,ou=users,dc=example,dc=com"}, equalDN, true},
+		{"DNs compared exactly", []string{"cn=Manager,dc=example,dc=com", "uid=jdoe,ou=users,dc=example,dc=com"}, exact, false},
+		{"values read back as written", []string{"cn=Manager,dc=example,dc=com", "UID=jdoe,OU=users,DC=example,DC=com"}, exact, true},
+		{"value missing", []string{"cn=Manager,dc=example,dc=com"}, equalDN, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			consistent, err := WaitForAttributeValues(context.Background(), searcher, "cn=developers,dc=example,dc=com", "member", tt.expected, tt.equal, 0, time.Millisecond)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if consistent != tt.want {
+				t.Errorf("WaitForAttributeValues() = %t, want %t", consistent, tt.want)
+			}
+		})
+	}
+}
+
+func TestLdapSearch_TypesOnly(t *testing.T) {
+	for _, typesOnly := range []bool{false, true} {
+		t.Run(strconv.FormatBool(typesOnly), func(t *testing.T) {
+			searcher := &recordingSearcher{}
+
+			if _, err := LdapSearch(searcher, "dc=example,dc=com", "base", "(objectClass=*)", nil, LdapSearchOptions{TypesOnly: typesOnly}); err != nil {
+				t.Fatalf("LdapSearch unexpected error: %v", err)
+			}
+
+			if got := searcher.requests[0].TypesOnly; got != typesOnly {
+				t.Errorf("TypesOnly = %v, want %v", got, typesOnly)
+			}
+		})
+	}
+}
+
+// pagedSearcher serves entries numbered from 0 in pages as requested by the paged results
+// control, with the offset of the next page as cookie. Unpaged searches return at most sizeLimit
+// entries and a sizeLimitExceeded error, as servers enforcing a size limit do.
+type pagedSearcher struct {
+	entries   int
+	sizeLimit int
+	pages     int
+}
+
+func (s *pagedSearcher) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	sr := &ldap.SearchResult{}
+	add := func(from, to int) {
+		for i := from; i < to && i < s.entries; i++ {
+			sr.Entries = append(sr.Entries, &ldap.Entry{DN: fmt.Sprintf("cn=entry%d,dc=example,dc=com", i)})
+		}
+	}
+
+	paging, ok := ldap.FindControl(req.Controls, ldap.ControlTypePaging).(*ldap.ControlPaging)
+	if !ok {
+		add(0, s.sizeLimit)
+		if s.entries > s.sizeLimit {
+			return sr, ldap.NewError(ldap.LDAPResultSizeLimitExceeded, errors.New("size limit exceeded"))
+		}
+		return sr, nil
+	}
+
+	s.pages++
+	offset := 0
+	if len(paging.Cookie) > 0 {
+		offset, _ = strconv.Atoi(string(paging.Cookie))
+	}
+	next := offset + int(paging.PagingSize)
+	add(offset, next)
+
+	response := ldap.NewControlPaging(0)
+	if next < s.entries {
+		response.SetCookie([]byte(strconv.Itoa(next)))
+	}
+	sr.Controls = []ldap.Control{response}
+	return sr, nil
+}
+
+func TestLdapSearch_PageSize(t *testing.T) {
+	searcher := &pagedSearcher{entries: 1001, sizeLimit: 1000}
+	var progress [][2]int
+
+	sr, err := LdapSearch(searcher, "dc=example,dc=com", "sub", "(objectClass=*)", nil, LdapSearchOptions{
+		PageSize: 400,
+		OnPage: func(page int, entries int) {
+			progress = append(progress, [2]int{page, entries})
+		},
+	})
+	if err != nil {
+		t.Fatalf("LdapSearch unexpected error: %v", err)
+	}
+	if len(sr.Entries) != 1001 || searcher.pages != 3 {
+		t.Errorf("expected 1001 entries in 3 pages, got %d entries in %d pages", len(sr.Entries), searcher.pages)
+	}
+	if expected := [][2]int{{1, 400}, {2, 800}, {3, 1001}}; !reflect.DeepEqual(progress, expected) {
+		t.Errorf("progress = %v, want %v", progress, expected)
+	}
+}
+
+func TestLdapSearch_PageSizeAboveSizeLimit(t *testing.T) {
+	// Without paging the server stops at its size limit
+	searcher := &pagedSearcher{entries: 1001, sizeLimit: 1000}
+	_, err := LdapSearch(searcher, "dc=example,dc=com", "sub", "(objectClass=*)", nil, LdapSearchOptions{})
+	if !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		t.Fatalf("unpaged LdapSearch error = %v, want sizeLimitExceeded", err)
+	}
+	if searcher.pages != 0 {
+		t.Errorf("unpaged LdapSearch requested %d pages", searcher.pages)
+	}
+
+	// Pages below the size limit return all entries
+	searcher = &pagedSearcher{entries: 1001, sizeLimit: 1000}
+	sr, err := LdapSearch(searcher, "dc=example,dc=com", "sub", "(objectClass=*)", nil, LdapSearchOptions{PageSize: 1000})
+	if err != nil {
+		t.Fatalf("paged LdapSearch unexpected error: %v", err)
+	}
+	if len(sr.Entries) != 1001 || searcher.pages != 2 {
+		t.Errorf("expected 1001 entries in 2 pages, got %d entries in %d pages", len(sr.Entries), searcher.pages)
+	}
+	seen := map[string]bool{}
+	for _, entry := range sr.Entries {
+		seen[entry.DN] = true
+	}
+	if len(seen) != 1001 {
+		t.Errorf("expected 1001 distinct entries, got %d", len(seen))
+	}
+}
+
+func TestAttributeNames(t *testing.T) {
+	sr := &ldap.SearchResult{
+		Entries: []*ldap.Entry{
+			ldap.NewEntry("uid=a,dc=example,dc=com", map[string][]string{"objectClass": nil, "uid": nil, "mail": nil}),
+			ldap.NewEntry("uid=b,dc=example,dc=com", map[string][]string{"objectclass": nil, "uid": nil, "telephoneNumber": nil}),
+			ldap.NewEntry("cn=g,dc=example,dc=com", map[string][]string{"member;range=0-*": nil}),
+		},
+	}
+
+	expected := []string{"mail", "member", "objectClass", "telephoneNumber", "uid"}
+	if names := AttributeNames(sr); fmt.Sprint(names) != fmt.Sprint(expected) {
+		t.Errorf("AttributeNames() = %v, want %v", names, expected)
+	}
+}
+
+// recordingModifier records modify requests.
+type recordingModifier struct {
+	requests []*ldap.ModifyRequest
+}
+
+func (m *recordingModifier) Modify(req *ldap.ModifyRequest) error {
+	m.requests = append(m.requests, req)
+	return nil
+}
+
+func memberValues(from, to int) []string {
+	values := make([]string, 0, to-from)
+	for i := from; i < to; i++ {
+		values = append(values, fmt.Sprintf("uid=user%d,ou=users,dc=example,dc=com", i))
+	}
+	return values
+}
+
+func TestModifyAttributeValues(t *testing.T) {
+	type change struct {
+		op     uint
+		values int
+	}
+
+	tests := []struct {
+		name      string
+		current   []string
+		desired   []string
+		batchSize int
+		expected  []change
+	}{
+		{
+			name:      "add 2500 members",
+			current:   memberValues(0, 1),
+			desired:   memberValues(0, 2501),
+			batchSize: 1000,
+			expected:  []change{{ldap.AddAttribute, 1000}, {ldap.AddAttribute, 1000}, {ldap.AddAttribute, 500}},
+		},
+		{
+			name:      "remove and add",
+			current:   memberValues(0, 1500),
+			desired:   memberValues(1200, 1700),
+			batchSize: 1000,
+			expected:  []change{{ldap.DeleteAttribute, 1000}, {ldap.DeleteAttribute, 200}, {ldap.AddAttribute, 200}},
+		},
+		{
+			name:      "custom batch size",
+			current:   memberValues(0, 1),
+			desired:   memberValues(0, 6),
+			batchSize: 2,
+			expected:  []change{{ldap.AddAttribute, 2}, {ldap.AddAttribute, 2}, {ldap.AddAttribute, 1}},
+		},
+		{
+			name:      "unchanged",
+			current:   memberValues(0, 10),
+			desired:   memberValues(0, 10),
+			batchSize: 2,
+			expected:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modifier := &recordingModifier{}
+
+			err := modifyAttributeValues(context.Background(), modifier, "cn=big,ou=groups,dc=example,dc=com", "member", tt.current, tt.desired, tt.batchSize, nil)
+			if err != nil {
+				t.Fatalf("modifyAttributeValues unexpected error: %v", err)
+			}
+
+			if len(modifier.requests) != len(tt.expected) {
+				t.Fatalf("expected %d modify requests, got %d", len(tt.expected), len(modifier.requests))
+			}
+
+			for i, req := range modifier.requests {
+				if len(req.Changes) != 1 {
+					t.Fatalf("request %d: expected a single change, got %d", i, len(req.Changes))
+				}
+				c := req.Changes[0]
+				if c.Operation != tt.expected[i].op || len(c.Modification.Vals) != tt.expected[i].values {
+					t.Errorf("request %d: operation %d with %d values, want operation %d with %d values",
+						i, c.Operation, len(c.Modification.Vals), tt.expected[i].op, tt.expected[i].values)
+				}
+			}
+		})
+	}
+}
+
+func TestUnlockAccount(t *testing.T) {
+	modifier := &recordingModifier{}
+	controls := []ldap.Control{ldap.NewControlMicrosoftSDFlags()}
+
+	if err := UnlockAccount(modifier, "cn=jdoe,cn=Users,dc=example,dc=com", controls); err != nil {
+		t.Fatalf("UnlockAccount unexpected error: %v", err)
+	}
+
+	if len(modifier.requests) != 1 {
+		t.Fatalf("expected 1 modify request, got %d", len(modifier.requests))
+	}
+	req := modifier.requests[0]
+	if req.DN != "cn=jdoe,cn=Users,dc=example,dc=com" {
+		t.Errorf("DN = %q", req.DN)
+	}
+	expected := []ldap.Change{{Operation: ldap.ReplaceAttribute, Modification: ldap.PartialAttribute{Type: "lockoutTime", Vals: []string{"0"}}}}
+	if !reflect.DeepEqual(req.Changes, expected) {
+		t.Errorf("changes = %+v, want %+v", req.Changes, expected)
+	}
+	if !reflect.DeepEqual(req.Controls, controls) {
+		t.Errorf("expected the entry controls to be sent, got %v", req.Controls)
+	}
+}
+
+func TestOrderModifyChanges(t *testing.T) {
+	modifyReq := ldap.NewModifyRequest("uid=jdoe,ou=users,dc=example,dc=com", nil)
+	modifyReq.Replace("mail", []string{"jdoe@example.com"})
+	modifyReq.Add("objectClass", []string{"posixAccount"})
+	modifyReq.Delete("telephoneNumber", nil)
+	modifyReq.Replace("description", []string{"Engineer"})
+	modifyReq.Delete("objectClass", []string{"shadowAccount"})
+	modifyReq.Delete("employeeNumber", nil)
+
+	type change struct {
+		op   uint
+		attr string
+	}
+	var got []change
+	for _, c := range orderModifyChanges(modifyReq.Changes) {
+		got = append(got, change{c.Operation, c.Modification.Type})
+	}
+
+	expected := []change{
+		{ldap.DeleteAttribute, "employeeNumber"},
+		{ldap.DeleteAttribute, "objectClass"},
+		{ldap.DeleteAttribute, "telephoneNumber"},
+		{ldap.ReplaceAttribute, "description"},
+		{ldap.ReplaceAttribute, "mail"},
+		{ldap.AddAttribute, "objectClass"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("orderModifyChanges() = %v, want %v", got, expected)
+	}
+}
+
+func TestObjectClassChanges(t *testing.T) {
+	tests := []struct {
+		name              string
+		current           []string
+		desired           []string
+		expectedAdditions []string
+		expectedRemovals  []string
+	}{
+		{
+			name:              "add auxiliary class",
+			current:           []string{"top", "inetOrgPerson"},
+			desired:           []string{"top", "inetOrgPerson", "posixAccount"},
+			expectedAdditions: []string{"posixAccount"},
+		},
+		{
+			name:             "remove auxiliary class",
+			current:          []string{"inetOrgPerson", "posixAccount", "shadowAccount"},
+			desired:          []string{"inetOrgPerson"},
+			expectedRemovals: []string{"posixAccount", "shadowAccount"},
+		},
+		{
+			name:              "add and remove",
+			current:           []string{"inetOrgPerson", "shadowAccount"},
+			desired:           []string{"inetOrgPerson", "posixAccount"},
+			expectedAdditions: []string{"posixAccount"},
+			expectedRemovals:  []string{"shadowAccount"},
+		},
+		{
+			name:    "case only",
+			current: []string{"inetOrgPerson", "posixAccount"},
+			desired: []string{"InetOrgPerson", "POSIXACCOUNT"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			additions, removals := objectClassChanges(tt.current, tt.desired)
+			if !reflect.DeepEqual(additions, tt.expectedAdditions) {
+				t.Errorf("additions = %v, want %v", additions, tt.expectedAdditions)
+			}
+			if !reflect.DeepEqual(removals, tt.expectedRemovals) {
+				t.Errorf("removals = %v, want %v", removals, tt.expectedRemovals)
+			}
+		})
+	}
+}
+
+func TestMarshalLdapResults_FoldAttributeNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		fold     bool
+		expected map[string]int
+	}{
+		{name: "folded to requested name", fold: true, expected: map[string]int{"objectclass": 1, "MAIL": 1}},
+		{name: "exact server names", fold: false, expected: map[string]int{"objectClass": 1, "mail": 1, "objectclass": 0, "MAIL": 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := &ldap.SearchResult{
+				Entries: []*ldap.Entry{
+					ldap.NewEntry("uid=testuser,dc=example,dc=com", map[string][]string{
+						"objectClass": {"inetOrgPerson"},
+						"mail":        {"test@example.com"},
+					}),
+				},
+			}
+
+			results, err := MarshalLdapResults(context.Background(), sr, []string{"objectclass", "MAIL"}, MarshalOptions{FoldAttributeNames: tt.fold})
+			if err != nil {
+				t.Fatalf("MarshalLdapResults unexpected error: %v", err)
+			}
+
+			attributes := results[0].Attributes.Elements()
+			if len(attributes) != len(tt.expected) {
+				t.Fatalf("expected %d attributes, got %v", len(tt.expected), results[0].Attributes)
+			}
+			for name, size := range tt.expected {
+				list, ok := attributes[name].(types.List)
+				if !ok || len(list.Elements()) != size {
+					t.Errorf("expected %s to hold %d values, got %v", name, size, results[0].Attributes)
+				}
+			}
+		})
+	}
+}
+
+func TestAlignAttributeNames(t *testing.T) {
+	attributes := map[string][]string{
+		"objectClass": {"person"},
+		"MAIL":        {"test@example.com"},
+		"sn":          {"User"},
+	}
+	reference := map[string][]string{
+		"objectclass": {"person"},
+		"mail":        {"new@example.com"},
+		"cn":          {"Test"},
+	}
+
+	alignAttributeNames(attributes, reference)
+
+	expected := map[string][]string{
+		"objectclass": {"person"},
+		"mail":        {"test@example.com"},
+		"sn":          {"User"},
+	}
+	if fmt.Sprint(attributes) != fmt.Sprint(expected) {
+		t.Errorf("alignAttributeNames() = %v, want %v", attributes, expected)
+	}
+}
+
+func TestMarshalLdapResults_SortValues(t *testing.T) {
+	tests := []struct {
+		name       string
+		sortValues bool
+		expected   []string
+	}{
+		{name: "server order by default", sortValues: false, expected: []string{"uid=c", "uid=a", "uid=b"}},
+		{name: "sorted", sortValues: true, expected: []string{"uid=a", "uid=b", "uid=c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := ldap.NewEntry("cn=group,dc=example,dc=com", map[string][]string{"member": {"uid=c", "uid=a", "uid=b"}})
+			sr := &ldap.SearchResult{Entries: []*ldap.Entry{entry}}
+
+			results, err := MarshalLdapResults(context.Background(), sr, nil, MarshalOptions{SortValues: tt.sortValues})
+			if err != nil {
+				t.Fatalf("MarshalLdapResults unexpected error: %v", err)
+			}
+
+			var member []string
+			if diags := results[0].Attributes.Elements()["member"].(types.List).ElementsAs(context.Background(), &member, false); diags.HasError() {
+				t.Fatalf("unable to read member: %v", diags)
+			}
+
+			if fmt.Sprint(member) != fmt.Sprint(tt.expected) {
+				t.Errorf("member = %v, want %v", member, tt.expected)
+			}
+
+			// The search result itself is left untouched
+			if got := entry.GetAttributeValues("member"); got[0] != "uid=c" {
+				t.Errorf("search result values were reordered: %v", got)
+			}
+		})
+	}
+}
+
+func TestMarshalLdapResults_OnlyRequested(t *testing.T) {
+	tests := []struct {
+		name          string
+		fold          bool
+		onlyRequested bool
+		expected      map[string]int
+	}{
+		{name: "everything returned by default", fold: false, onlyRequested: false, expected: map[string]int{"objectClass": 1, "MAIL": 0, "mail": 1}},
+		{name: "only requested, exact", fold: false, onlyRequested: true, expected: map[string]int{"objectClass": 1, "MAIL": 0}},
+		{name: "only requested, folded", fold: true, onlyRequested: true, expected: map[string]int{"objectClass": 1, "MAIL": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := &ldap.SearchResult{
+				Entries: []*ldap.Entry{
+					ldap.NewEntry("uid=testuser,dc=example,dc=com", map[string][]string{
+						"objectClass": {"inetOrgPerson"},
+						"mail":        {"test@example.com"},
+					}),
+				},
+			}
+
+			results, err := MarshalLdapResults(context.Background(), sr, []string{"objectClass", "MAIL"}, MarshalOptions{
+				FoldAttributeNames: tt.fold,
+				OnlyRequested:      tt.onlyRequested,
+			})
+			if err != nil {
+				t.Fatalf("MarshalLdapResults unexpected error: %v", err)
+			}
+
+			attributes := results[0].Attributes.Elements()
+			if len(attributes) != len(tt.expected) {
+				t.Fatalf("expected %d attributes, got %v", len(tt.expected), results[0].Attributes)
+			}
+			for name, size := range tt.expected {
+				list, ok := attributes[name].(types.List)
+				if !ok || len(list.Elements()) != size {
+					t.Errorf("expected %s to hold %d values, got %v", name, size, results[0].Attributes)
+				}
+			}
+		})
+	}
+}
+
+func TestMarshalLdapResults_OptionWildcard(t *testing.T) {
+	sr := &ldap.SearchResult{
+		Entries: []*ldap.Entry{
+			ldap.NewEntry("uid=testuser,dc=example,dc=com", map[string][]string{
+				"description;lang-en": {"Hello"},
+				"description;lang-de": {"Hallo"},
+				"description":         {"Default"},
+				"cn":                  {"testuser"},
+			}),
+		},
+	}
+
+	for _, onlyRequested := range []bool{false, true} {
+		results, err := MarshalLdapResults(context.Background(), sr, []string{"description;*"}, MarshalOptions{
+			OnlyRequested: onlyRequested,
+		})
+		if err != nil {
+			t.Fatalf("MarshalLdapResults unexpected error: %v", err)
+		}
+
+		attributes := results[0].Attributes.Elements()
+		if _, exists := attributes["description;*"]; exists {
+			t.Errorf("onlyRequested=%t: unexpected phantom description;* in %v", onlyRequested, results[0].Attributes)
+		}
+		for name, value := range map[string]string{"description;lang-en": "Hello", "description;lang-de": "Hallo", "description": "Default"} {
+			list, ok := attributes[name].(types.List)
+			if !ok || len(list.Elements()) != 1 || !list.Elements()[0].Equal(types.StringValue(value)) {
+				t.Errorf("onlyRequested=%t: expected %s to hold [%q], got %v", onlyRequested, name, value, results[0].Attributes)
+			}
+		}
+		if _, exists := attributes["cn"]; exists == onlyRequested {
+			t.Errorf("onlyRequested=%t: unexpected presence of cn in %v", onlyRequested, results[0].Attributes)
+		}
+	}
+}
+
+func TestMarshalLdapResults_Lenient(t *testing.T) {
+	invalidUTF8 := []byte{0xff, 0xfe, 'x'}
+	invalidSID := []byte{1, 5, 0}
+	sr := &ldap.SearchResult{Entries: []*ldap.Entry{{
+		DN: "CN=Jane Doe,CN=Users,DC=example,DC=com",
+		Attributes: []*ldap.EntryAttribute{
+			{Name: "cn", Values: []string{"Jane Doe"}, ByteValues: [][]byte{[]byte("Jane Doe")}},
+			{Name: "x-unusualSyntax", Values: []string{string(invalidUTF8)}, ByteValues: [][]byte{invalidUTF8}},
+			{Name: "objectSid", Values: []string{string(invalidSID)}, ByteValues: [][]byte{invalidSID}},
+		},
+	}}}
+	opts := MarshalOptions{SIDAttributes: []string{"objectSid"}}
+
+	if _, err := MarshalLdapResults(context.Background(), sr, nil, opts); err == nil {
+		t.Fatal("expected an error when not lenient")
+	}
+
+	opts.Lenient = true
+	results, err := MarshalLdapResults(context.Background(), sr, nil, opts)
+	if err != nil {
+		t.Fatalf("MarshalLdapResults unexpected error: %v", err)
+	}
+
+	attributes := results[0].Attributes.Elements()
+	expected := map[string]string{
+		"cn":              "Jane Doe",
+		"x-unusualSyntax": base64.StdEncoding.EncodeToString(invalidUTF8),
+		"objectSid":       base64.StdEncoding.EncodeToString(invalidSID),
+	}
+	for name, value := range expected {
+		list, ok := attributes[name].(types.List)
+		if !ok || len(list.Elements()) != 1 || !list.Elements()[0].Equal(types.StringValue(value)) {
+			t.Errorf("expected %s to hold [%q], got %v", name, value, attributes[name])
+		}
+	}
+
+	diags := marshalWarnings(results)
+	if diags.HasError() || diags.WarningsCount() != 1 {
+		t.Fatalf("expected a single warning, got %v", diags)
+	}
+	for _, name := range []string{"x-unusualSyntax", "objectSid"} {
+		if !strings.Contains(diags[0].Detail(), name) {
+			t.Errorf("expected the warning to name %s, got %q", name, diags[0].Detail())
+		}
+	}
+	if strings.Contains(diags[0].Detail(), "cn of") {
+		t.Errorf("unexpected warning about cn: %q", diags[0].Detail())
+	}
+}
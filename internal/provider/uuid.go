@@ -0,0 +1,72 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// uuidPattern matches a UUID in its string representation (RFC 4122), e.g.
+// "597ae2f6-16a6-1027-98f4-abcdefabcdef".
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// isUUID reports whether s is a UUID in its string representation. No DN looks like one, as a DN
+// contains at least one "=".
+func isUUID(s string) bool {
+	return uuidPattern.MatchString(s)
+}
+
+// uuidFilter returns the search filter matching the entry with the UUID uuid: its entryUUID
+// (RFC 4530), or its objectGUID on Active Directory. objectGUID holds the 16 bytes of the UUID with
+// the first three fields in little-endian order, which the filter matches byte by byte.
+func uuidFilter(uuid string) (string, error) {
+	if !isUUID(uuid) {
+		return "", fmt.Errorf("%q is not a UUID such as 597ae2f6-16a6-1027-98f4-abcdefabcdef", uuid)
+	}
+
+	b, err := hex.DecodeString(strings.ReplaceAll(uuid, "-", ""))
+	if err != nil {
+		return "", err
+	}
+	guid := append([]byte{b[3], b[2], b[1], b[0], b[5], b[4], b[7], b[6]}, b[8:]...)
+
+	var escaped strings.Builder
+	for _, c := range guid {
+		fmt.Fprintf(&escaped, "\\%02x", c)
+	}
+
+	return fmt.Sprintf("(|(entryUUID=%s)(objectGUID=%s))", strings.ToLower(uuid), escaped.String()), nil
+}
+
+// FindDNByUUID returns the DN of the entry with the UUID uuid (see uuidFilter), searching the
+// subtrees of baseDNs. It fails unless exactly one entry has the UUID.
+func FindDNByUUID(conn LdapSearcher, baseDNs []string, uuid string) (string, error) {
+	filter, err := uuidFilter(uuid)
+	if err != nil {
+		return "", err
+	}
+
+	var dns []string
+	for _, baseDN := range baseDNs {
+		sr, err := LdapSearch(conn, baseDN, "sub", filter, []string{noAttributes}, LdapSearchOptions{})
+		if err != nil {
+			return "", fmt.Errorf("unable to search %q for UUID %s: %w", baseDN, uuid, err)
+		}
+		for _, entry := range sr.Entries {
+			dns = append(dns, entry.DN)
+		}
+	}
+
+	switch len(dns) {
+	case 0:
+		return "", fmt.Errorf("no entry below %s has the UUID %s, or the bound identity cannot read it", strings.Join(baseDNs, "; "), uuid)
+	case 1:
+		return dns[0], nil
+	default:
+		return "", fmt.Errorf("%d entries have the UUID %s: %s", len(dns), uuid, strings.Join(dns, "; "))
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestIsUUID(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected bool
+	}{
+		{value: "597ae2f6-16a6-1027-98f4-abcdefabcdef", expected: true},
+		{value: "597AE2F6-16A6-1027-98F4-ABCDEFABCDEF", expected: true},
+		{value: "597ae2f616a6102798f4abcdefabcdef", expected: false},
+		{value: "{597ae2f6-16a6-1027-98f4-abcdefabcdef}", expected: false},
+		{value: "cn=597ae2f6-16a6-1027-98f4-abcdefabcdef,dc=example,dc=com", expected: false},
+		{value: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		if got := isUUID(tt.value); got != tt.expected {
+			t.Errorf("isUUID(%q) = %t, want %t", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestUUIDFilter(t *testing.T) {
+	got, err := uuidFilter("597AE2F6-16A6-1027-98F4-ABCDEFABCDEF")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := `(|(entryUUID=597ae2f6-16a6-1027-98f4-abcdefabcdef)(objectGUID=\f6\e2\7a\59\a6\16\27\10\98\f4\ab\cd\ef\ab\cd\ef))`
+	if got != expected {
+		t.Errorf("uuidFilter() = %s, want %s", got, expected)
+	}
+	if _, err := ldap.CompileFilter(got); err != nil {
+		t.Errorf("uuidFilter() returned an invalid filter: %s", err)
+	}
+
+	if _, err := uuidFilter("cn=test,dc=example,dc=com"); err == nil {
+		t.Error("expected an error for a DN")
+	}
+}
+
+// uuidSearcher returns the entries of entries that are below the search base.
+type uuidSearcher struct {
+	entries []string
+	bases   []string
+}
+
+func (s *uuidSearcher) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	s.bases = append(s.bases, req.BaseDN)
+	sr := &ldap.SearchResult{}
+	for _, dn := range s.entries {
+		if strings.HasSuffix(dn, ","+req.BaseDN) {
+			sr.Entries = append(sr.Entries, &ldap.Entry{DN: dn})
+		}
+	}
+	return sr, nil
+}
+
+func TestFindDNByUUID(t *testing.T) {
+	const uuid = "597ae2f6-16a6-1027-98f4-abcdefabcdef"
+
+	tests := []struct {
+		name      string
+		entries   []string
+		baseDNs   []string
+		expected  string
+		expectErr string
+	}{
+		{
+			name:     "found",
+			entries:  []string{"cn=renamed,ou=users,dc=example,dc=com"},
+			baseDNs:  []string{"dc=example,dc=org", "dc=example,dc=com"},
+			expected: "cn=renamed,ou=users,dc=example,dc=com",
+		},
+		{
+			name:      "not found",
+			baseDNs:   []string{"dc=example,dc=com"},
+			expectErr: "no entry below dc=example,dc=com",
+		},
+		{
+			name:      "ambiguous",
+			entries:   []string{"cn=a,dc=example,dc=com", "cn=b,dc=example,dc=com"},
+			baseDNs:   []string{"dc=example,dc=com"},
+			expectErr: "2 entries have the UUID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searcher := &uuidSearcher{entries: tt.entries}
+			got, err := FindDNByUUID(searcher, tt.baseDNs, uuid)
+			if tt.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tt.expected {
+				t.Errorf("FindDNByUUID() = %q, want %q", got, tt.expected)
+			}
+			if len(searcher.bases) != len(tt.baseDNs) {
+				t.Errorf("expected a search below each of %v, got %v", tt.baseDNs, searcher.bases)
+			}
+		})
+	}
+}
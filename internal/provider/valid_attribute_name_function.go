@@ -0,0 +1,61 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &ValidAttributeNameFunction{}
+
+// attributeDescriptionRegexp matches an RFC 4512 attribute description: a descriptor (keystring) or
+// numeric OID, followed by zero or more ";option" suffixes.
+var attributeDescriptionRegexp = regexp.MustCompile(`^(?:[A-Za-z][A-Za-z0-9-]*|(?:0|[1-9][0-9]*)(?:\.(?:0|[1-9][0-9]*))+)(?:;[A-Za-z0-9-]+)*$`)
+
+func NewValidAttributeNameFunction() function.Function {
+	return &ValidAttributeNameFunction{}
+}
+
+// ValidAttributeNameFunction reports whether a string is a syntactically valid LDAP attribute description.
+type ValidAttributeNameFunction struct{}
+
+func (f *ValidAttributeNameFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "valid_attribute_name"
+}
+
+func (f *ValidAttributeNameFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Check whether a string is a valid attribute name",
+		MarkdownDescription: "Returns `true` if `name` matches the RFC 4512 attribute description syntax: a descriptor starting with a letter followed by letters, digits or hyphens (e.g. `mail`, `msDS-UserPasswordExpiryTimeComputed`), " +
+			"or a numeric OID (e.g. `2.5.4.3`), optionally followed by options such as `;binary` or `;lang-en`. " +
+			"Only syntax is checked; the server schema is not consulted.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "name",
+				MarkdownDescription: "Attribute name to check, e.g. a key of an `ldap_entry` `attributes` map.",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *ValidAttributeNameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &name))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, isValidAttributeDescription(name)))
+}
+
+// isValidAttributeDescription reports whether name is a syntactically valid RFC 4512 attribute description.
+func isValidAttributeDescription(name string) bool {
+	return attributeDescriptionRegexp.MatchString(name)
+}
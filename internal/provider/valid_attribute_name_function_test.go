@@ -0,0 +1,82 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestIsValidAttributeDescription(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{name: "descriptor", input: "mail", expected: true},
+		{name: "mixed case", input: "objectClass", expected: true},
+		{name: "hyphen and digits", input: "msDS-KeyVersionNumber2", expected: true},
+		{name: "descriptor with option", input: "userCertificate;binary", expected: true},
+		{name: "descriptor with multiple options", input: "cn;lang-en;x-custom", expected: true},
+		{name: "numeric oid", input: "2.5.4.3", expected: true},
+		{name: "numeric oid with option", input: "2.5.4.36;binary", expected: true},
+		{name: "ranged attribute", input: "member;range=0-1499", expected: false},
+		{name: "empty", input: "", expected: false},
+		{name: "space inside", input: "given Name", expected: false},
+		{name: "trailing space", input: "mail ", expected: false},
+		{name: "leading digit descriptor", input: "1mail", expected: false},
+		{name: "leading hyphen", input: "-mail", expected: false},
+		{name: "underscore", input: "given_name", expected: false},
+		{name: "single number oid", input: "2", expected: false},
+		{name: "oid leading zero", input: "2.05.4.3", expected: false},
+		{name: "oid trailing dot", input: "2.5.4.", expected: false},
+		{name: "empty option", input: "mail;", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isValidAttributeDescription(tt.input); got != tt.expected {
+				t.Errorf("isValidAttributeDescription(%q) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidAttributeNameFunction_Run(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{name: "valid", input: "userCertificate;binary", expected: true},
+		{name: "invalid", input: "user certificate", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue(tt.input),
+				}),
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.BoolUnknown()),
+			}
+
+			NewValidAttributeNameFunction().Run(context.Background(), req, resp)
+
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+
+			if !resp.Result.Value().Equal(types.BoolValue(tt.expected)) {
+				t.Errorf("result = %s, want %v", resp.Result.Value(), tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,449 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure validators satisfy the framework interfaces.
+var _ validator.String = durationValidator{}
+var _ validator.String = proxyURLValidator{}
+var _ validator.String = filterValidator{}
+var _ validator.Int64 = int64BetweenValidator{}
+var _ validator.List = requestedAttributesValidator{}
+var _ resource.ConfigValidator = attributesWriteOnlyConflictValidator{}
+var _ resource.ConfigValidator = writeOnceAttributesValidator{}
+var _ resource.ConfigValidator = readConsistencyAttributeValidator{}
+
+// durationValidator checks that a string attribute is a valid Go duration (e.g. "500ms", "1s", "2m").
+type durationValidator struct{}
+
+func (v durationValidator) Description(ctx context.Context) string {
+	return "value must be a valid duration such as \"500ms\", \"1s\" or \"2m\""
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return "value must be a valid duration such as `500ms`, `1s` or `2m`"
+}
+
+func (v durationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	d, err := time.ParseDuration(req.ConfigValue.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid duration",
+			fmt.Sprintf("Unable to parse %q as a duration: %s", req.ConfigValue.ValueString(), err),
+		)
+		return
+	}
+
+	if d < 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid duration",
+			fmt.Sprintf("Duration %q must not be negative", req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+// proxyURLValidator checks that a string attribute is a supported proxy URL (socks5, socks5h or http).
+type proxyURLValidator struct{}
+
+func (v proxyURLValidator) Description(ctx context.Context) string {
+	return "value must be a proxy URL with a socks5, socks5h or http scheme and an explicit port"
+}
+
+func (v proxyURLValidator) MarkdownDescription(ctx context.Context) string {
+	return "value must be a proxy URL with a `socks5`, `socks5h` or `http` scheme and an explicit port"
+}
+
+func (v proxyURLValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := parseProxyURL(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid proxy URL",
+			err.Error(),
+		)
+	}
+}
+
+// int64BetweenValidator checks that an integer attribute is within [min, max].
+type int64BetweenValidator struct {
+	min int64
+	max int64
+}
+
+func (v int64BetweenValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("value must be between %d and %d", v.min, v.max)
+}
+
+func (v int64BetweenValidator) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("value must be between `%d` and `%d`", v.min, v.max)
+}
+
+func (v int64BetweenValidator) ValidateInt64(ctx context.Context, req validator.Int64Request, resp *validator.Int64Response) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if value := req.ConfigValue.ValueInt64(); value < v.min || value > v.max {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Value out of range",
+			fmt.Sprintf("Value %d must be between %d and %d", value, v.min, v.max),
+		)
+	}
+}
+
+// filterValidator checks that a string attribute is a valid LDAP search filter (RFC 4515).
+type filterValidator struct{}
+
+func (v filterValidator) Description(ctx context.Context) string {
+	return "value must be a valid LDAP search filter such as \"(objectClass=person)\""
+}
+
+func (v filterValidator) MarkdownDescription(ctx context.Context) string {
+	return "value must be a valid LDAP search filter such as `(objectClass=person)`"
+}
+
+func (v filterValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := ldap.CompileFilter(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid filter",
+			fmt.Sprintf("Unable to parse %q as an LDAP search filter: %s", req.ConfigValue.ValueString(), err),
+		)
+	}
+}
+
+// stringOneOfValidator checks that a string attribute is one of values.
+type stringOneOfValidator struct {
+	values []string
+}
+
+func (v stringOneOfValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("value must be one of %s", strings.Join(v.values, ", "))
+}
+
+func (v stringOneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("value must be one of `%s`", strings.Join(v.values, "`, `"))
+}
+
+func (v stringOneOfValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	for _, allowed := range v.values {
+		if value == allowed {
+			return
+		}
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid value",
+		fmt.Sprintf("Value %q must be one of %s", value, strings.Join(v.values, ", ")),
+	)
+}
+
+// requestedAttributesValidator warns about requested attributes that are neither a special
+// selector ("*", "+", "1.1", "@<objectclass>", "<attribute>;*") nor an attribute name or OID, such
+// as "+all". Servers ignore attributes they do not know, so such typos otherwise go unnoticed.
+// It only warns, so that unusual but valid selectors keep working.
+type requestedAttributesValidator struct{}
+
+func (v requestedAttributesValidator) Description(ctx context.Context) string {
+	return "values should be *, +, 1.1, @<objectclass>, <attribute>;* or an attribute name or OID"
+}
+
+func (v requestedAttributesValidator) MarkdownDescription(ctx context.Context) string {
+	return "values should be `*`, `+`, `1.1`, `@<objectclass>`, `<attribute>;*` or an attribute name or OID"
+}
+
+func (v requestedAttributesValidator) ValidateList(ctx context.Context, req validator.ListRequest, resp *validator.ListResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for i, element := range req.ConfigValue.Elements() {
+		value, ok := element.(types.String)
+		if !ok || value.IsNull() || value.IsUnknown() {
+			continue
+		}
+
+		if problem := requestedAttributeProblem(value.ValueString()); problem != "" {
+			resp.Diagnostics.AddAttributeWarning(
+				req.Path.AtListIndex(i),
+				"Unrecognized requested attribute",
+				fmt.Sprintf("Requested attribute %q %s. The server ignores attributes it does not know, so the search may return fewer attributes than intended.", value.ValueString(), problem),
+			)
+		}
+	}
+}
+
+// requestedAttributeProblem describes why name is probably not what was meant in a list of
+// requested attributes, or returns "" if it is a special selector or a valid attribute description.
+func requestedAttributeProblem(name string) string {
+	switch {
+	case name == "*" || name == "+" || name == noAttributes:
+		return ""
+	case strings.EqualFold(name, "all"):
+		return "names an attribute called \"all\"; use \"*\" for all user attributes and \"+\" for all operational attributes"
+	case strings.EqualFold(name, "none"):
+		return fmt.Sprintf("names an attribute called \"none\"; use %q to request no attributes", noAttributes)
+	case strings.HasPrefix(name, "@"):
+		if !isValidObjectClassName(strings.TrimPrefix(name, "@")) {
+			return "is not a valid object class selector, which must be \"@\" followed by an object class name or OID"
+		}
+		return ""
+	case isOptionWildcard(name):
+		if !isValidAttributeDescription(strings.TrimSuffix(name, ";*")) {
+			return "is not a valid option wildcard, which must be an attribute name or OID followed by \";*\""
+		}
+		return ""
+	case strings.HasPrefix(name, "+") || strings.HasPrefix(name, "*"):
+		return "is neither a special selector nor an attribute name; \"*\" and \"+\" must be requested on their own"
+	case !isValidAttributeDescription(name):
+		return "is neither a special selector (\"*\", \"+\", \"1.1\", \"@<objectclass>\", \"<attribute>;*\") nor a valid attribute name or OID"
+	}
+	return ""
+}
+
+// isValidObjectClassName reports whether name is a descriptor or numeric OID, without options.
+func isValidObjectClassName(name string) bool {
+	return !strings.Contains(name, ";") && isValidAttributeDescription(name)
+}
+
+// attributesWriteOnlyConflictValidator rejects ldap_entry configurations naming the same attribute
+// in both attributes and attributes_wo. The two maps are merged before writing, so one value would
+// silently win. Names are compared ignoring case, as the server does. A map only known during apply
+// is checked by ModifyPlan instead.
+type attributesWriteOnlyConflictValidator struct{}
+
+func (v attributesWriteOnlyConflictValidator) Description(ctx context.Context) string {
+	return "attributes and attributes_wo must not set the same attribute"
+}
+
+func (v attributesWriteOnlyConflictValidator) MarkdownDescription(ctx context.Context) string {
+	return "`attributes` and `attributes_wo` must not set the same attribute"
+}
+
+func (v attributesWriteOnlyConflictValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var attributes, attributesWO types.Map
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("attributes"), &attributes)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("attributes_wo"), &attributesWO)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(writeOnlyConflictDiagnostics(attributes, attributesWO)...)
+}
+
+// writeOnlyConflictDiagnostics reports an error for each attribute of writeOnly also in attributes.
+func writeOnlyConflictDiagnostics(attributes types.Map, writeOnly types.Map) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, name := range conflictingAttributeNames(attributes, writeOnly) {
+		diags.AddAttributeError(
+			path.Root("attributes_wo").AtMapKey(name),
+			"Conflicting attribute",
+			fmt.Sprintf("Attribute %q is set in both attributes and attributes_wo. Set it in only one of them: attributes_wo for secrets that must not be stored in state, attributes otherwise.", name),
+		)
+	}
+	return diags
+}
+
+// conflictingAttributeNames returns the sorted keys of writeOnly that name, ignoring case, an
+// attribute also in attributes. Null or unknown maps have no conflicts.
+func conflictingAttributeNames(attributes types.Map, writeOnly types.Map) []string {
+	if attributes.IsNull() || attributes.IsUnknown() || writeOnly.IsNull() || writeOnly.IsUnknown() {
+		return nil
+	}
+
+	names := make(map[string]bool, len(attributes.Elements()))
+	for name := range attributes.Elements() {
+		names[strings.ToLower(name)] = true
+	}
+
+	var conflicts []string
+	for name := range writeOnly.Elements() {
+		if names[strings.ToLower(name)] {
+			conflicts = append(conflicts, name)
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+// writeOnceAttributesValidator rejects ldap_entry write_once_attributes that name no attribute of
+// attributes_wo, as they would have no effect. Names are compared ignoring case.
+type writeOnceAttributesValidator struct{}
+
+func (v writeOnceAttributesValidator) Description(ctx context.Context) string {
+	return "write_once_attributes must name attributes of attributes_wo"
+}
+
+func (v writeOnceAttributesValidator) MarkdownDescription(ctx context.Context) string {
+	return "`write_once_attributes` must name attributes of `attributes_wo`"
+}
+
+func (v writeOnceAttributesValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var writeOnce types.List
+	var attributesWO types.Map
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("write_once_attributes"), &writeOnce)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("attributes_wo"), &attributesWO)...)
+	if resp.Diagnostics.HasError() || writeOnce.IsNull() || writeOnce.IsUnknown() || attributesWO.IsUnknown() {
+		return
+	}
+
+	names := make(map[string]bool, len(attributesWO.Elements()))
+	for name := range attributesWO.Elements() {
+		names[strings.ToLower(name)] = true
+	}
+
+	for i, element := range writeOnce.Elements() {
+		name, ok := element.(types.String)
+		if !ok || name.IsNull() || name.IsUnknown() {
+			continue
+		}
+		if !names[strings.ToLower(name.ValueString())] {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("write_once_attributes").AtListIndex(i),
+				"Unknown write-once attribute",
+				fmt.Sprintf("Attribute %q is not set in attributes_wo. write_once_attributes only applies to attributes of attributes_wo.", name.ValueString()),
+			)
+		}
+	}
+}
+
+// readConsistencyAttributeValidator rejects ldap_entry read_consistency attributes not set in
+// attributes, or set to null there. Values of attributes_wo are only written when
+// attributes_wo_version changes, and secrets are often stored hashed, so they cannot be polled.
+// Names are compared ignoring case if foldCase is set, see LdapClient.FoldsAttributeNames. A map
+// only known during apply is checked by ModifyPlan instead.
+type readConsistencyAttributeValidator struct {
+	foldCase bool
+}
+
+func (v readConsistencyAttributeValidator) Description(ctx context.Context) string {
+	return "read_consistency attribute must be set in attributes"
+}
+
+func (v readConsistencyAttributeValidator) MarkdownDescription(ctx context.Context) string {
+	return "`read_consistency` `attribute` must be set in `attributes`"
+}
+
+func (v readConsistencyAttributeValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var readConsistency *LdapEntryReadConsistencyModel
+	var attributes, attributesWO types.Map
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("read_consistency"), &readConsistency)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("attributes"), &attributes)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("attributes_wo"), &attributesWO)...)
+	if resp.Diagnostics.HasError() || readConsistency == nil {
+		return
+	}
+
+	resp.Diagnostics.Append(readConsistencyAttributeDiagnostics(readConsistency.Attribute, attributes, attributesWO, v.foldCase)...)
+}
+
+// readConsistencyAttributeDiagnostics reports an error if attribute, the read_consistency
+// attribute, is not set in attributes, naming attributes_wo if it is set there instead, or if its
+// value is null, as null attributes are not written. Names are compared ignoring case if foldCase
+// is set.
+func readConsistencyAttributeDiagnostics(attribute types.String, attributes types.Map, writeOnly types.Map, foldCase bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if attribute.IsNull() || attribute.IsUnknown() || attributes.IsUnknown() {
+		return diags
+	}
+
+	name := attribute.ValueString()
+	names := make(map[string][]string, len(attributes.Elements()))
+	for n := range attributes.Elements() {
+		names[n] = nil
+	}
+	if key, ok := findAttribute(names, name, foldCase); ok {
+		if attributes.Elements()[key].IsNull() {
+			diags.AddAttributeError(
+				path.Root("read_consistency").AtName("attribute"),
+				"Invalid read consistency attribute",
+				fmt.Sprintf("Attribute %q is null in attributes, so it is not written and cannot be used for read consistency.", name),
+			)
+		}
+		return diags
+	}
+
+	if !writeOnly.IsUnknown() {
+		for writeOnlyName := range writeOnly.Elements() {
+			if strings.EqualFold(writeOnlyName, name) {
+				diags.AddAttributeError(
+					path.Root("read_consistency").AtName("attribute"),
+					"Invalid read consistency attribute",
+					fmt.Sprintf("Attribute %q is set in attributes_wo. Write-only values are not written on every apply and cannot be read back as written, so read_consistency must use an attribute of attributes.", name),
+				)
+				return diags
+			}
+		}
+	}
+
+	diags.AddAttributeError(
+		path.Root("read_consistency").AtName("attribute"),
+		"Invalid read consistency attribute",
+		fmt.Sprintf("Attribute %q must be managed in attributes to be used for read consistency.", name),
+	)
+	return diags
+}
+
+// rdnSafeValidator checks that a string attribute only contains characters that never need
+// escaping in a DN, see isRDNSafe.
+type rdnSafeValidator struct{}
+
+func (v rdnSafeValidator) Description(ctx context.Context) string {
+	return "value may only contain letters, digits, -, _ and ."
+}
+
+func (v rdnSafeValidator) MarkdownDescription(ctx context.Context) string {
+	return "value may only contain letters, digits, `-`, `_` and `.`"
+}
+
+func (v rdnSafeValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if value := req.ConfigValue.ValueString(); !isRDNSafe(value) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid value",
+			fmt.Sprintf("Value %q may only contain letters, digits, -, _ and ., which need no escaping in a DN", value),
+		)
+	}
+}
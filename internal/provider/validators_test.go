@@ -0,0 +1,343 @@
+// Copyright (c) ngharo <root@ngha.ro>
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDurationValidator(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       types.String
+		expectError bool
+	}{
+		{
+			name:        "null",
+			value:       types.StringNull(),
+			expectError: false,
+		},
+		{
+			name:        "unknown",
+			value:       types.StringUnknown(),
+			expectError: false,
+		},
+		{
+			name:        "seconds",
+			value:       types.StringValue("1s"),
+			expectError: false,
+		},
+		{
+			name:        "milliseconds",
+			value:       types.StringValue("250ms"),
+			expectError: false,
+		},
+		{
+			name:        "compound",
+			value:       types.StringValue("1m30s"),
+			expectError: false,
+		},
+		{
+			name:        "missing unit",
+			value:       types.StringValue("10"),
+			expectError: true,
+		},
+		{
+			name:        "garbage",
+			value:       types.StringValue("soon"),
+			expectError: true,
+		},
+		{
+			name:        "negative",
+			value:       types.StringValue("-1s"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.StringRequest{
+				Path:        path.Root("test"),
+				ConfigValue: tt.value,
+			}
+			resp := &validator.StringResponse{}
+
+			durationValidator{}.ValidateString(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != tt.expectError {
+				t.Errorf("durationValidator(%s) error = %v, want %v: %v", tt.value, resp.Diagnostics.HasError(), tt.expectError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestInt64BetweenValidator(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       types.Int64
+		expectError bool
+	}{
+		{name: "null", value: types.Int64Null(), expectError: false},
+		{name: "unknown", value: types.Int64Unknown(), expectError: false},
+		{name: "min", value: types.Int64Value(0), expectError: false},
+		{name: "max", value: types.Int64Value(15), expectError: false},
+		{name: "below", value: types.Int64Value(-1), expectError: true},
+		{name: "above", value: types.Int64Value(16), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.Int64Request{
+				Path:        path.Root("test"),
+				ConfigValue: tt.value,
+			}
+			resp := &validator.Int64Response{}
+
+			int64BetweenValidator{min: 0, max: 15}.ValidateInt64(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != tt.expectError {
+				t.Errorf("int64BetweenValidator(%s) error = %v, want %v: %v", tt.value, resp.Diagnostics.HasError(), tt.expectError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestFilterValidator(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       types.String
+		expectError bool
+	}{
+		{name: "null", value: types.StringNull(), expectError: false},
+		{name: "unknown", value: types.StringUnknown(), expectError: false},
+		{name: "presence", value: types.StringValue("(objectClass=*)"), expectError: false},
+		{name: "and", value: types.StringValue("(&(objectClass=person)(!(pwdAccountLockedTime=*)))"), expectError: false},
+		{name: "unbalanced", value: types.StringValue("(objectClass=person"), expectError: true},
+		{name: "empty", value: types.StringValue(""), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.StringRequest{
+				Path:        path.Root("test"),
+				ConfigValue: tt.value,
+			}
+			resp := &validator.StringResponse{}
+
+			filterValidator{}.ValidateString(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != tt.expectError {
+				t.Errorf("filterValidator(%s) error = %v, want %v: %v", tt.value, resp.Diagnostics.HasError(), tt.expectError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestStringOneOfValidator(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       types.String
+		expectError bool
+	}{
+		{name: "null", value: types.StringNull(), expectError: false},
+		{name: "unknown", value: types.StringUnknown(), expectError: false},
+		{name: "allowed", value: types.StringValue("anonymous"), expectError: false},
+		{name: "different case", value: types.StringValue("Anonymous"), expectError: true},
+		{name: "other", value: types.StringValue("simple"), expectError: true},
+		{name: "empty", value: types.StringValue(""), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.StringRequest{
+				Path:        path.Root("test"),
+				ConfigValue: tt.value,
+			}
+			resp := &validator.StringResponse{}
+
+			stringOneOfValidator{values: []string{"anonymous"}}.ValidateString(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != tt.expectError {
+				t.Errorf("stringOneOfValidator(%s) error = %v, want %v: %v", tt.value, resp.Diagnostics.HasError(), tt.expectError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestRequestedAttributesValidator(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         string
+		expectWarning bool
+	}{
+		{name: "all user attributes", value: "*"},
+		{name: "all operational attributes", value: "+"},
+		{name: "no attributes", value: "1.1"},
+		{name: "attribute name", value: "mail"},
+		{name: "attribute name with hyphen", value: "msDS-UserPasswordExpiryTimeComputed"},
+		{name: "attribute with option", value: "description;lang-en"},
+		{name: "numeric oid", value: "2.5.4.3"},
+		{name: "object class", value: "@inetOrgPerson"},
+		{name: "object class oid", value: "@2.5.6.6"},
+		{name: "option wildcard", value: "description;*"},
+		{name: "all", value: "all", expectWarning: true},
+		{name: "all uppercase", value: "ALL", expectWarning: true},
+		{name: "none", value: "none", expectWarning: true},
+		{name: "plus all", value: "+all", expectWarning: true},
+		{name: "star prefix", value: "*mail", expectWarning: true},
+		{name: "empty object class", value: "@", expectWarning: true},
+		{name: "object class with option", value: "@person;lang-en", expectWarning: true},
+		{name: "option wildcard without attribute", value: ";*", expectWarning: true},
+		{name: "comma separated", value: "cn,sn", expectWarning: true},
+		{name: "space", value: "given name", expectWarning: true},
+		{name: "leading digit", value: "1cn", expectWarning: true},
+		{name: "empty", value: "", expectWarning: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.ListRequest{
+				Path:        path.Root("requested_attributes"),
+				ConfigValue: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("cn"), types.StringValue(tt.value)}),
+			}
+			resp := &validator.ListResponse{}
+
+			requestedAttributesValidator{}.ValidateList(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("expected warnings only, got %v", resp.Diagnostics)
+			}
+			warnings := resp.Diagnostics.Warnings()
+			if (len(warnings) > 0) != tt.expectWarning {
+				t.Fatalf("requestedAttributesValidator(%q) warnings = %v, want warning: %v", tt.value, warnings, tt.expectWarning)
+			}
+			if tt.expectWarning {
+				if len(warnings) != 1 {
+					t.Fatalf("expected one warning, got %v", warnings)
+				}
+				diagWithPath, ok := warnings[0].(diag.DiagnosticWithPath)
+				if !ok || !diagWithPath.Path().Equal(path.Root("requested_attributes").AtListIndex(1)) {
+					t.Errorf("expected the warning on the second element, got %v", warnings[0])
+				}
+			}
+		})
+	}
+}
+
+func TestRequestedAttributesValidator_NullAndUnknown(t *testing.T) {
+	for _, value := range []types.List{
+		types.ListNull(types.StringType),
+		types.ListUnknown(types.StringType),
+		types.ListValueMust(types.StringType, []attr.Value{types.StringUnknown(), types.StringNull()}),
+	} {
+		resp := &validator.ListResponse{}
+		requestedAttributesValidator{}.ValidateList(context.Background(), validator.ListRequest{Path: path.Root("requested_attributes"), ConfigValue: value}, resp)
+		if len(resp.Diagnostics) != 0 {
+			t.Errorf("expected no diagnostics for %s, got %v", value, resp.Diagnostics)
+		}
+	}
+}
+
+func TestConflictingAttributeNames(t *testing.T) {
+	valuesType := types.ListType{ElemType: types.StringType}
+	attrMap := func(names ...string) types.Map {
+		elements := make(map[string]attr.Value, len(names))
+		for _, name := range names {
+			elements[name] = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("x")})
+		}
+		return types.MapValueMust(valuesType, elements)
+	}
+
+	tests := []struct {
+		name       string
+		attributes types.Map
+		writeOnly  types.Map
+		expected   []string
+	}{
+		{name: "disjoint", attributes: attrMap("cn", "sn"), writeOnly: attrMap("userPassword"), expected: nil},
+		{name: "same name", attributes: attrMap("cn", "userPassword"), writeOnly: attrMap("userPassword"), expected: []string{"userPassword"}},
+		{name: "different case", attributes: attrMap("userpassword"), writeOnly: attrMap("userPassword"), expected: []string{"userPassword"}},
+		{name: "several", attributes: attrMap("cn", "mail", "sn"), writeOnly: attrMap("sn", "cn"), expected: []string{"cn", "sn"}},
+		{name: "no write-only attributes", attributes: attrMap("cn"), writeOnly: types.MapNull(valuesType), expected: nil},
+		{name: "unknown attributes", attributes: types.MapUnknown(valuesType), writeOnly: attrMap("cn"), expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conflictingAttributeNames(tt.attributes, tt.writeOnly); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("conflictingAttributeNames() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWriteOnlyConflictDiagnostics(t *testing.T) {
+	valuesType := types.ListType{ElemType: types.StringType}
+	value := types.ListValueMust(types.StringType, []attr.Value{types.StringValue("x")})
+	attributes := types.MapValueMust(valuesType, map[string]attr.Value{"cn": value, "userpassword": value})
+	writeOnly := types.MapValueMust(valuesType, map[string]attr.Value{"userPassword": value, "unicodePwd": value})
+
+	diags := writeOnlyConflictDiagnostics(attributes, writeOnly)
+	if diags.ErrorsCount() != 1 {
+		t.Fatalf("expected 1 error, got %v", diags)
+	}
+	if want := path.Root("attributes_wo").AtMapKey("userPassword"); !diags.Errors()[0].(diag.DiagnosticWithPath).Path().Equal(want) {
+		t.Errorf("error path = %s, want %s", diags.Errors()[0].(diag.DiagnosticWithPath).Path(), want)
+	}
+
+	// Unknown until apply: checked again once known
+	if diags := writeOnlyConflictDiagnostics(types.MapUnknown(valuesType), writeOnly); diags.HasError() {
+		t.Errorf("unexpected diagnostics for unknown attributes: %v", diags)
+	}
+}
+
+func TestReadConsistencyAttributeDiagnostics(t *testing.T) {
+	valuesType := types.ListType{ElemType: types.StringType}
+	value := types.ListValueMust(types.StringType, []attr.Value{types.StringValue("x")})
+	attributes := types.MapValueMust(valuesType, map[string]attr.Value{"cn": value, "mail": value, "description": types.ListNull(types.StringType)})
+	writeOnly := types.MapValueMust(valuesType, map[string]attr.Value{"userPassword": value})
+
+	tests := []struct {
+		name       string
+		attribute  types.String
+		attributes types.Map
+		foldCase   bool
+		expectErr  string
+	}{
+		{"managed attribute", types.StringValue("mail"), attributes, true, ""},
+		{"managed attribute in other case", types.StringValue("Mail"), attributes, true, ""},
+		{"managed attribute in other case without folding", types.StringValue("Mail"), attributes, false, "must be managed in attributes"},
+		{"null attribute", types.StringValue("description"), attributes, true, "is null in attributes"},
+		{"write-only attribute", types.StringValue("userPassword"), attributes, true, "is set in attributes_wo"},
+		{"write-only attribute in other case", types.StringValue("userpassword"), attributes, true, "is set in attributes_wo"},
+		{"unmanaged attribute", types.StringValue("telephoneNumber"), attributes, true, "must be managed in attributes"},
+		{"no attributes", types.StringValue("mail"), types.MapNull(valuesType), true, "must be managed in attributes"},
+		{"unknown attributes", types.StringValue("mail"), types.MapUnknown(valuesType), true, ""},
+		{"unknown attribute", types.StringUnknown(), attributes, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := readConsistencyAttributeDiagnostics(tt.attribute, tt.attributes, writeOnly, tt.foldCase)
+			if tt.expectErr == "" {
+				if diags.HasError() {
+					t.Errorf("unexpected diagnostics: %v", diags)
+				}
+				return
+			}
+			if diags.ErrorsCount() != 1 || !strings.Contains(diags.Errors()[0].Detail(), tt.expectErr) {
+				t.Errorf("expected an error containing %q, got %v", tt.expectErr, diags)
+			}
+		})
+	}
+}